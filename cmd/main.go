@@ -72,7 +72,7 @@ var (
 	httpAPIFlag = cli.StringSliceFlag{
 		Name:     config.FlagHTTPAPI,
 		Aliases:  []string{"ha"},
-		Usage:    fmt.Sprintf("List of JSON RPC apis to be exposed by the server: --http.api=%v,%v,%v,%v,%v,%v", jsonrpc.APIEth, jsonrpc.APINet, jsonrpc.APIDebug, jsonrpc.APIZKEVM, jsonrpc.APITxPool, jsonrpc.APIWeb3),
+		Usage:    fmt.Sprintf("List of JSON RPC apis to be exposed by the server: --http.api=%v,%v,%v,%v,%v,%v,%v,%v", jsonrpc.APIEth, jsonrpc.APINet, jsonrpc.APIDebug, jsonrpc.APIZKEVM, jsonrpc.APITxPool, jsonrpc.APIWeb3, jsonrpc.APITrace, jsonrpc.APIOts),
 		Required: false,
 		Value:    cli.NewStringSlice(jsonrpc.APIEth, jsonrpc.APINet, jsonrpc.APIZKEVM, jsonrpc.APITxPool, jsonrpc.APIWeb3),
 	}
@@ -172,6 +172,12 @@ func main() {
 			Action: genJSONSchema,
 			Flags:  []cli.Flag{&outputFileFlag, &documentationFileTypeFlag},
 		},
+		{
+			Name:   "generate-openrpc",
+			Usage:  "Generate the OpenRPC document describing the JSON RPC apis, the same document returned by rpc_discover",
+			Action: genOpenRPC,
+			Flags:  []cli.Flag{&outputFileFlag, &httpAPIFlag},
+		},
 		{
 			Name:    "snapshot",
 			Aliases: []string{"snap"},
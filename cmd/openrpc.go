@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/0xPolygonHermez/zkevm-node/config"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc"
+	"github.com/urfave/cli/v2"
+)
+
+const openRPCFilePermissions = 0644
+
+// openRPCServices are the endpoints described for each api, they are only
+// inspected to build the document so they don't need any dependency
+var openRPCServices = map[string]interface{}{
+	jsonrpc.APIEth:    &jsonrpc.EthEndpoints{},
+	jsonrpc.APINet:    &jsonrpc.NetEndpoints{},
+	jsonrpc.APIZKEVM:  &jsonrpc.ZKEVMEndpoints{},
+	jsonrpc.APITxPool: &jsonrpc.TxPoolEndpoints{},
+	jsonrpc.APIDebug:  &jsonrpc.DebugEndpoints{},
+	jsonrpc.APIWeb3:   &jsonrpc.Web3Endpoints{},
+	jsonrpc.APITrace:  &jsonrpc.TraceEndpoints{},
+	jsonrpc.APIOts:    &jsonrpc.OtsEndpoints{},
+}
+
+func genOpenRPC(cliCtx *cli.Context) error {
+	services := []jsonrpc.Service{}
+	for _, api := range cliCtx.StringSlice(config.FlagHTTPAPI) {
+		service, found := openRPCServices[api]
+		if !found {
+			return fmt.Errorf("unknown api %s", api)
+		}
+		services = append(services, jsonrpc.Service{Name: api, Service: service})
+	}
+
+	document, err := json.MarshalIndent(jsonrpc.NewOpenRPCDocument(services), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(cliCtx.String(config.FlagOutputFile), append(document, '\n'), openRPCFilePermissions)
+}
@@ -205,6 +205,12 @@ func start(cliCtx *cli.Context) error {
 				poolInstance.StartPollingMinSuggestedGasPrice(cliCtx.Context)
 			}
 			poolInstance.StartRefreshingBlockedAddressesPeriodically()
+			go func() {
+				// the txs stored before the address index was created are indexed in the background
+				if err := st.BackfillTxAddressIndex(cliCtx.Context); err != nil {
+					log.Errorf("error backfilling the tx address index: %v", err)
+				}
+			}()
 			apis := map[string]bool{}
 			for _, a := range cliCtx.StringSlice(config.FlagHTTPAPI) {
 				apis[a] = true
@@ -327,8 +333,24 @@ func runSynchronizer(cfg config.Config, etherman *etherman.Client, ethTxManagerS
 
 func runJSONRPCServer(c config.Config, etherman *etherman.Client, chainID uint64, pool *pool.Pool, st *state.State, apis map[string]bool) {
 	var err error
-	storage := jsonrpc.NewStorage()
+	if c.RPC.FilterStorage.Type == jsonrpc.FilterStorageTypePostgres {
+		runMigrations(c.RPC.FilterStorage.DB, db.RPCMigrationName)
+	}
+	storage, err := jsonrpc.NewFilterStorage(c.RPC.FilterStorage)
+	if err != nil {
+		log.Fatal(err)
+	}
+	cache := jsonrpc.NewConsolidatedCache(c.RPC.Cache, st)
 	c.RPC.MaxCumulativeGasUsed = c.State.Batch.Constraints.MaxCumulativeGasUsed
+	c.RPC.ZKCountersLimits = c.State.Batch.Constraints
+	c.RPC.Network = jsonrpc.NetworkConfig{
+		L1ChainID:                 c.NetworkConfig.L1Config.L1ChainID,
+		RollupID:                  etherman.RollupID,
+		ZkEVMAddr:                 c.NetworkConfig.L1Config.ZkEVMAddr,
+		RollupManagerAddr:         c.NetworkConfig.L1Config.RollupManagerAddr,
+		PolAddr:                   c.NetworkConfig.L1Config.PolAddr,
+		GlobalExitRootManagerAddr: c.NetworkConfig.L1Config.GlobalExitRootManagerAddr,
+	}
 	c.RPC.L2Coinbase = c.SequenceSender.L2Coinbase
 	if !c.IsTrustedSequencer {
 		if c.RPC.SequencerNodeURI == "" {
@@ -345,7 +367,7 @@ func runJSONRPCServer(c config.Config, etherman *etherman.Client, chainID uint64
 	if _, ok := apis[jsonrpc.APIEth]; ok {
 		services = append(services, jsonrpc.Service{
 			Name:    jsonrpc.APIEth,
-			Service: jsonrpc.NewEthEndpoints(c.RPC, chainID, pool, st, etherman, storage),
+			Service: jsonrpc.NewEthEndpoints(c.RPC, chainID, pool, st, etherman, storage, cache),
 		})
 	}
 
@@ -359,14 +381,14 @@ func runJSONRPCServer(c config.Config, etherman *etherman.Client, chainID uint64
 	if _, ok := apis[jsonrpc.APIZKEVM]; ok {
 		services = append(services, jsonrpc.Service{
 			Name:    jsonrpc.APIZKEVM,
-			Service: jsonrpc.NewZKEVMEndpoints(c.RPC, st, etherman),
+			Service: jsonrpc.NewZKEVMEndpoints(c.RPC, chainID, pool, st, etherman, cache),
 		})
 	}
 
 	if _, ok := apis[jsonrpc.APITxPool]; ok {
 		services = append(services, jsonrpc.Service{
 			Name:    jsonrpc.APITxPool,
-			Service: &jsonrpc.TxPoolEndpoints{},
+			Service: jsonrpc.NewTxPoolEndpoints(c.RPC, pool, st),
 		})
 	}
 
@@ -377,6 +399,20 @@ func runJSONRPCServer(c config.Config, etherman *etherman.Client, chainID uint64
 		})
 	}
 
+	if _, ok := apis[jsonrpc.APITrace]; ok {
+		services = append(services, jsonrpc.Service{
+			Name:    jsonrpc.APITrace,
+			Service: jsonrpc.NewTraceEndpoints(c.RPC, st, etherman),
+		})
+	}
+
+	if _, ok := apis[jsonrpc.APIOts]; ok {
+		services = append(services, jsonrpc.Service{
+			Name:    jsonrpc.APIOts,
+			Service: jsonrpc.NewOtsEndpoints(c.RPC, st, etherman),
+		})
+	}
+
 	if _, ok := apis[jsonrpc.APIWeb3]; ok {
 		services = append(services, jsonrpc.Service{
 			Name:    jsonrpc.APIWeb3,
@@ -12,6 +12,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/aggregator"
 	"github.com/0xPolygonHermez/zkevm-node/config"
 	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
@@ -381,6 +382,22 @@ func Test_Defaults(t *testing.T) {
 			path:          "RPC.BatchRequestsLimit",
 			expectedValue: uint(20),
 		},
+		{
+			path:          "RPC.BatchRequestsConcurrency",
+			expectedValue: uint(4),
+		},
+		{
+			path:          "RPC.BatchRequestsCostLimit",
+			expectedValue: uint64(0),
+		},
+		{
+			path:          "RPC.BatchRequestsTimeout",
+			expectedValue: types.NewDuration(30 * time.Second),
+		},
+		{
+			path:          "RPC.SendRawTransactionSyncTimeout",
+			expectedValue: types.NewDuration(10 * time.Second),
+		},
 		{
 			path:          "RPC.MaxLogsCount",
 			expectedValue: uint64(10000),
@@ -393,6 +410,14 @@ func Test_Defaults(t *testing.T) {
 			path:          "RPC.MaxNativeBlockHashBlockRange",
 			expectedValue: uint64(60000),
 		},
+		{
+			path:          "RPC.MaxTraceFilterBlockRange",
+			expectedValue: uint64(1000),
+		},
+		{
+			path:          "RPC.MaxTraceFilterTxs",
+			expectedValue: uint64(1000),
+		},
 		{
 			path:          "RPC.EnableHttpLog",
 			expectedValue: true,
@@ -413,6 +438,106 @@ func Test_Defaults(t *testing.T) {
 			path:          "RPC.WebSockets.ReadLimit",
 			expectedValue: int64(104857600),
 		},
+		{
+			path:          "RPC.WebSockets.SubscriptionQueueSize",
+			expectedValue: uint(10000),
+		},
+		{
+			path:          "RPC.WebSockets.SubscriptionQueueOverflowPolicy",
+			expectedValue: "dropOldest",
+		},
+		{
+			path:          "RPC.WebSockets.MaxResumeBlockRange",
+			expectedValue: uint64(1000),
+		},
+		{
+			path:          "RPC.FilterStorage.Type",
+			expectedValue: "memory",
+		},
+		{
+			path:          "RPC.FilterStorage.FilterTimeout",
+			expectedValue: types.NewDuration(0),
+		},
+		{
+			path:          "RPC.FilterStorage.DB.User",
+			expectedValue: "rpc_user",
+		},
+		{
+			path:          "RPC.FilterStorage.DB.Password",
+			expectedValue: "rpc_password",
+		},
+		{
+			path:          "RPC.FilterStorage.DB.Name",
+			expectedValue: "rpc_db",
+		},
+		{
+			path:          "RPC.FilterStorage.DB.Host",
+			expectedValue: "zkevm-rpc-db",
+		},
+		{
+			path:          "RPC.FilterStorage.DB.Port",
+			expectedValue: "5432",
+		},
+		{
+			path:          "RPC.FilterStorage.DB.EnableLog",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.FilterStorage.DB.MaxConns",
+			expectedValue: 200,
+		},
+		{
+			path:          "RPC.RateLimit.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.RateLimit.APIKeyHeader",
+			expectedValue: "X-API-Key",
+		},
+		{
+			path:          "RPC.RateLimit.APIKeyRequired",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.RateLimit.AnonymousCreditsPerSecond",
+			expectedValue: float64(0),
+		},
+		{
+			path:          "RPC.RateLimit.TrustedProxies",
+			expectedValue: []string{},
+		},
+		{
+			path:          "RPC.RateLimit.APIKeys",
+			expectedValue: []jsonrpc.APIKeyConfig{},
+		},
+		{
+			path:          "RPC.RateLimit.Methods",
+			expectedValue: []jsonrpc.MethodLimitConfig{},
+		},
+		{
+			path:          "RPC.Cache.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.Cache.Size",
+			expectedValue: 10000,
+		},
+		{
+			path:          "RPC.Cache.ReorgCheckInterval",
+			expectedValue: types.NewDuration(1 * time.Second),
+		},
+		{
+			path:          "RPC.IPC.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.IPC.Path",
+			expectedValue: "/tmp/zkevm-node.ipc",
+		},
+		{
+			path:          "RPC.IPC.FileMode",
+			expectedValue: "0600",
+		},
 		{
 			path:          "Executor.URI",
 			expectedValue: "zkevm-prover:50071",
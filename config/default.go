@@ -85,15 +85,51 @@ SequencerNodeURI = ""
 EnableL2SuggestedGasPricePolling = true
 BatchRequestsEnabled = false
 BatchRequestsLimit = 20
+BatchRequestsConcurrency = 4
+BatchRequestsCostLimit = 0
+BatchRequestsTimeout = "30s"
 MaxLogsCount = 10000
 MaxLogsBlockRange = 10000
 MaxNativeBlockHashBlockRange = 60000
+MaxTraceFilterBlockRange = 1000
+MaxTraceFilterTxs = 1000
 EnableHttpLog = true
+SendRawTransactionSyncTimeout = "10s"
 	[RPC.WebSockets]
 		Enabled = true
 		Host = "0.0.0.0"
 		Port = 8546
 		ReadLimit = 104857600
+		SubscriptionQueueSize = 10000
+		SubscriptionQueueOverflowPolicy = "dropOldest"
+		MaxResumeBlockRange = 1000
+	[RPC.FilterStorage]
+		Type = "memory"
+		FilterTimeout = "0s"
+		[RPC.FilterStorage.DB]
+			User = "rpc_user"
+			Password = "rpc_password"
+			Name = "rpc_db"
+			Host = "zkevm-rpc-db"
+			Port = "5432"
+			EnableLog = false
+			MaxConns = 200
+	[RPC.RateLimit]
+		Enabled = false
+		APIKeyHeader = "X-API-Key"
+		APIKeyRequired = false
+		AnonymousCreditsPerSecond = 0
+		TrustedProxies = []
+		APIKeys = []
+		Methods = []
+	[RPC.Cache]
+		Enabled = false
+		Size = 10000
+		ReorgCheckInterval = "1s"
+	[RPC.IPC]
+		Enabled = false
+		Path = "/tmp/zkevm-node.ipc"
+		FileMode = "0600"
 
 [Synchronizer]
 SyncInterval = "1s"
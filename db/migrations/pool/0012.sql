@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE pool.transaction ADD COLUMN conditions JSONB;
+
+-- +migrate Down
+ALTER TABLE pool.transaction DROP COLUMN conditions;
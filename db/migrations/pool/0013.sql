@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE pool.transaction_status_history
+(
+    item_id       SERIAL PRIMARY KEY,
+    hash          VARCHAR NOT NULL,
+    status        VARCHAR NOT NULL,
+    failed_reason VARCHAR,
+    changed_at    TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_status_history_hash ON pool.transaction_status_history (hash);
+CREATE INDEX IF NOT EXISTS idx_transaction_status_history_changed_at ON pool.transaction_status_history (changed_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS pool.idx_transaction_status_history_changed_at;
+DROP INDEX IF EXISTS pool.idx_transaction_status_history_hash;
+DROP TABLE pool.transaction_status_history;
@@ -0,0 +1,57 @@
+package pool_migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the history of the status transitions of the transactions
+type migrationTest0013 struct{}
+
+func (m migrationTest0013) InsertData(db *sql.DB) error {
+	return nil
+}
+
+const getStatusHistoryTable = `SELECT count(*) FROM information_schema.tables WHERE table_schema = 'pool' AND table_name = 'transaction_status_history';`
+
+var statusHistoryIndexes = []string{"idx_transaction_status_history_hash", "idx_transaction_status_history_changed_at"}
+
+func (m migrationTest0013) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	row := db.QueryRow(getStatusHistoryTable)
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 1, result)
+
+	for _, idx := range statusHistoryIndexes {
+		const getIndex = `SELECT count(*) FROM pg_indexes WHERE indexname = $1;`
+		row := db.QueryRow(getIndex, idx)
+		var result int
+		assert.NoError(t, row.Scan(&result))
+		assert.Equal(t, 1, result)
+	}
+
+	const insertHistory = `INSERT INTO pool.transaction_status_history (hash, status, failed_reason, changed_at) VALUES ('0x1', 'failed', 'out of counters', NOW());`
+	_, err := db.Exec(insertHistory)
+	assert.NoError(t, err)
+}
+
+func (m migrationTest0013) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	row := db.QueryRow(getStatusHistoryTable)
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 0, result)
+
+	for _, idx := range statusHistoryIndexes {
+		const getIndex = `SELECT count(*) FROM pg_indexes WHERE indexname = $1;`
+		row := db.QueryRow(getIndex, idx)
+		var result int
+		assert.NoError(t, row.Scan(&result))
+		assert.Equal(t, 0, result)
+	}
+}
+
+func TestMigration0013(t *testing.T) {
+	runMigrationTest(t, 13, migrationTest0013{})
+}
@@ -0,0 +1,16 @@
+-- +migrate Down
+DROP SCHEMA IF EXISTS rpc CASCADE;
+
+-- +migrate Up
+CREATE SCHEMA rpc;
+
+CREATE TABLE rpc.filter
+(
+    id          VARCHAR PRIMARY KEY,
+    filter_type VARCHAR(15)              NOT NULL,
+    parameters  JSONB,
+    last_poll   TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_filter_last_poll ON rpc.filter (last_poll);
@@ -0,0 +1,27 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS state.transaction_address
+(
+    tx_hash      VARCHAR NOT NULL REFERENCES state.transaction (hash) ON DELETE CASCADE,
+    l2_block_num BIGINT  NOT NULL,
+    address      BYTEA   NOT NULL,
+    PRIMARY KEY (tx_hash, address)
+);
+
+CREATE INDEX IF NOT EXISTS transaction_address_address_l2_block_num ON state.transaction_address (address, l2_block_num);
+
+-- the txs already stored are indexed by the node at startup, which recovers
+-- their senders from the encoded txs from the most recent block backwards,
+-- the first indexed block is kept to know where the backfill continues
+CREATE TABLE IF NOT EXISTS state.transaction_address_start
+(
+    l2_block_num BIGINT NOT NULL
+);
+
+INSERT INTO state.transaction_address_start (l2_block_num)
+SELECT COALESCE(MAX(block_num) + 1, 0)
+  FROM state.l2block;
+
+-- +migrate Down
+DROP TABLE IF EXISTS state.transaction_address_start;
+DROP INDEX IF EXISTS state.transaction_address_address_l2_block_num;
+DROP TABLE IF EXISTS state.transaction_address;
@@ -0,0 +1,101 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// this migration adds the table indexing the addresses of the transactions
+type migrationTest0014 struct{}
+
+var (
+	migration0014Recipient = common.HexToAddress("0x222")
+	migration0014Contract  = common.HexToAddress("0x333")
+)
+
+func (m migrationTest0014) InsertData(db *sql.DB) error {
+	const addBlock = "INSERT INTO state.block (block_num, received_at, block_hash) VALUES ($1, $2, $3)"
+	if _, err := db.Exec(addBlock, 1, time.Now(), blockHashValue); err != nil {
+		return err
+	}
+
+	const insertBatch = `
+		INSERT INTO state.batch (batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, forced_batch_num)
+		VALUES (0, '0x0000', '0x0000', '0x0000', '0x0000', now(), '0x0000', null, null)`
+	if _, err := db.Exec(insertBatch); err != nil {
+		return err
+	}
+
+	const insertL2Block = `
+		INSERT INTO state.l2block (block_num, block_hash, header, uncles, parent_hash, state_root, received_at, batch_num, created_at)
+		VALUES (1, '0x0001', '{}', '{}', '0x0002', '0x003', now(), 0, now())`
+	if _, err := db.Exec(insertL2Block); err != nil {
+		return err
+	}
+
+	const insertTx = `
+		INSERT INTO state.transaction (hash, encoded, decoded, l2_block_num, effective_percentage, l2_hash)
+		VALUES ($1, 'ABCDEF', $2, 1, 255, $3)`
+	const insertReceipt = `
+		INSERT INTO state.receipt (tx_hash, type, post_state, status, cumulative_gas_used, gas_used, effective_gas_price, block_num, tx_index, contract_address)
+		VALUES ($1, 0, null, 1, 21000, 21000, 1, 1, $2, $3)`
+
+	// call to the recipient
+	decoded := fmt.Sprintf(`{"to": "%s"}`, migration0014Recipient.Hex())
+	if _, err := db.Exec(insertTx, "0x0001", decoded, "0x1001"); err != nil {
+		return err
+	}
+	if _, err := db.Exec(insertReceipt, "0x0001", 0, common.Address{}.String()); err != nil {
+		return err
+	}
+
+	// deployment of the contract
+	if _, err := db.Exec(insertTx, "0x0002", `{"to": null}`, "0x1002"); err != nil {
+		return err
+	}
+	if _, err := db.Exec(insertReceipt, "0x0002", 1, migration0014Contract.String()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m migrationTest0014) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	// the stored txs are indexed by the backfill, the index starts after the last block
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT count(*) FROM state.transaction_address`).Scan(&count))
+	assert.Equal(t, 0, count)
+
+	var startBlockNum uint64
+	require.NoError(t, db.QueryRow(`SELECT l2_block_num FROM state.transaction_address_start`).Scan(&startBlockNum))
+	assert.Equal(t, uint64(2), startBlockNum)
+
+	const insertTxAddress = "INSERT INTO state.transaction_address (tx_hash, l2_block_num, address) VALUES ($1, 1, $2)"
+	_, err := db.Exec(insertTxAddress, "0x0001", migration0014Recipient.Bytes())
+	require.NoError(t, err)
+	_, err = db.Exec(insertTxAddress, "0x0002", migration0014Contract.Bytes())
+	require.NoError(t, err)
+
+	// the addresses are removed along with the transaction
+	_, err = db.Exec(`DELETE FROM state.transaction WHERE hash = '0x0001'`)
+	require.NoError(t, err)
+	require.NoError(t, db.QueryRow(`SELECT count(*) FROM state.transaction_address`).Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
+func (m migrationTest0014) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getTables = `SELECT count(*) FROM information_schema.tables WHERE table_schema = 'state' AND table_name IN ('transaction_address', 'transaction_address_start')`
+	var count int
+	require.NoError(t, db.QueryRow(getTables).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigration0014(t *testing.T) {
+	runMigrationTest(t, 14, migrationTest0014{})
+}
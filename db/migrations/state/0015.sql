@@ -0,0 +1,7 @@
+-- +migrate Up
+-- index the contracts created by the txs to find the tx that created a contract
+CREATE INDEX IF NOT EXISTS receipt_contract_address_idx ON state.receipt (contract_address)
+ WHERE contract_address <> '0x0000000000000000000000000000000000000000';
+
+-- +migrate Down
+DROP INDEX IF EXISTS state.receipt_contract_address_idx;
@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 const sampleNumber = 3 // Number of transactions sampled in a batch.
@@ -84,8 +85,7 @@ func (g *LastNL2BlocksGasPrice) UpdateGasPriceAvg() {
 
 	price := lastPrice
 	if len(results) > 0 {
-		sort.Sort(bigIntArray(results))
-		price = results[(len(results)-1)*g.cfg.Percentile/100]
+		price = TipsPercentile(results, g.cfg.Percentile)
 	}
 	if price.Cmp(g.cfg.MaxPrice) > 0 {
 		price = g.cfg.MaxPrice
@@ -117,6 +117,16 @@ func (g *LastNL2BlocksGasPrice) getL2BlockTxsTips(ctx context.Context, l2BlockNu
 		}
 		return
 	}
+	prices := SampleTxsTips(txs, limit, ignorePrice)
+	select {
+	case result <- results{prices, nil}:
+	case <-quit:
+	}
+}
+
+// SampleTxsTips returns up to limit of the lowest gas tips of the given txs, the
+// tips lower than ignorePrice are skipped
+func SampleTxsTips(txs []*types.Transaction, limit int, ignorePrice *big.Int) []*big.Int {
 	sorter := newSorter(txs)
 	sort.Sort(sorter)
 
@@ -131,10 +141,16 @@ func (g *LastNL2BlocksGasPrice) getL2BlockTxsTips(ctx context.Context, l2BlockNu
 			break
 		}
 	}
-	select {
-	case result <- results{prices, nil}:
-	case <-quit:
+	return prices
+}
+
+// TipsPercentile sorts the given tips and returns the one at the given percentile, or nil if there are no tips
+func TipsPercentile(tips []*big.Int, percentile int) *big.Int {
+	if len(tips) == 0 {
+		return nil
 	}
+	sort.Sort(bigIntArray(tips))
+	return tips[(len(tips)-1)*percentile/100]
 }
 
 type results struct {
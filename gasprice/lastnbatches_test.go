@@ -0,0 +1,36 @@
+package gasprice
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleTxsTips(t *testing.T) {
+	txs := []*types.Transaction{
+		types.NewTx(&types.LegacyTx{GasPrice: big.NewInt(30)}),
+		types.NewTx(&types.DynamicFeeTx{GasTipCap: big.NewInt(5), GasFeeCap: big.NewInt(50)}),
+		types.NewTx(&types.LegacyTx{GasPrice: big.NewInt(10)}),
+		types.NewTx(&types.LegacyTx{GasPrice: big.NewInt(20)}),
+	}
+
+	tips := SampleTxsTips(txs, 2, nil)
+	assert.Equal(t, []*big.Int{big.NewInt(5), big.NewInt(10)}, tips)
+
+	tips = SampleTxsTips(txs, 3, big.NewInt(10))
+	assert.Equal(t, []*big.Int{big.NewInt(10), big.NewInt(20), big.NewInt(30)}, tips)
+
+	tips = SampleTxsTips([]*types.Transaction{}, 3, nil)
+	assert.Empty(t, tips)
+}
+
+func TestTipsPercentile(t *testing.T) {
+	tips := []*big.Int{big.NewInt(40), big.NewInt(10), big.NewInt(30), big.NewInt(20), big.NewInt(50)}
+
+	assert.Equal(t, big.NewInt(10), TipsPercentile(tips, 0))
+	assert.Equal(t, big.NewInt(30), TipsPercentile(tips, 50))
+	assert.Equal(t, big.NewInt(50), TipsPercentile(tips, 100))
+	assert.Nil(t, TipsPercentile([]*big.Int{}, 50))
+}
@@ -0,0 +1,121 @@
+package jsonrpc
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+)
+
+// batchResult is the response of the request of a batch request at the
+// provided index
+type batchResult struct {
+	index    int
+	response types.Response
+}
+
+// newMethodCosts returns the cost of the methods with a custom cost defined
+// in the rate limit configuration
+func newMethodCosts(cfg RateLimitConfig) map[string]uint64 {
+	costs := make(map[string]uint64, len(cfg.Methods))
+	for _, method := range cfg.Methods {
+		if method.Cost > 0 {
+			costs[method.Method] = method.Cost
+		}
+	}
+	return costs
+}
+
+// methodCost returns the cost of a request to the method
+func (s *Server) methodCost(method string) uint64 {
+	if cost, found := s.methodCosts[method]; found {
+		return cost
+	}
+	return defaultMethodCost
+}
+
+// processBatchRequest processes the requests of a batch request, concurrently
+// if it's configured, and returns their responses in the same order.
+//
+// The requests after the cost limit of the batch is exceeded aren't
+// processed, and when the timeout of the batch is reached the requests not
+// processed yet are discarded, so the responses of the processed requests are
+// returned together with an error for each one of the discarded requests. The
+// handlers still running when the timeout is reached aren't interrupted and
+// their responses are discarded, only the ones waiting on the context of the
+// http request, like eth_sendRawTransactionSync, stop when the batch is
+// finished.
+func (s *Server) processBatchRequest(httpRequest *http.Request, requests []types.Request) []types.Response {
+	responses := make([]types.Response, len(requests))
+	processed := make([]bool, len(requests))
+
+	toProcess := make([]int, 0, len(requests))
+	totalCost := uint64(0)
+	for i, request := range requests {
+		totalCost += s.methodCost(request.Method)
+		if s.config.BatchRequestsCostLimit > 0 && totalCost > s.config.BatchRequestsCostLimit {
+			for j := i; j < len(requests); j++ {
+				responses[j] = types.NewResponse(requests[j], nil, types.NewRPCError(types.LimitExceededErrorCode, types.ErrBatchRequestsCostLimitExceeded.Error()))
+				processed[j] = true
+			}
+			break
+		}
+		toProcess = append(toProcess, i)
+	}
+
+	// the context stops the workers and the handlers when the batch is finished
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if s.config.BatchRequestsTimeout.Duration > 0 {
+		ctx, cancel = context.WithTimeout(httpRequest.Context(), s.config.BatchRequestsTimeout.Duration)
+	} else {
+		ctx, cancel = context.WithCancel(httpRequest.Context())
+	}
+	defer cancel()
+	batchHttpRequest := httpRequest.WithContext(ctx)
+
+	// the channels are buffered so the workers never get blocked, even if the
+	// timeout is reached while they are processing a request
+	jobs := make(chan int, len(toProcess))
+	for _, i := range toProcess {
+		jobs <- i
+	}
+	close(jobs)
+	results := make(chan batchResult, len(toProcess))
+
+	workers := int(s.config.BatchRequestsConcurrency)
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(toProcess) {
+		workers = len(toProcess)
+	}
+	for w := 0; w < workers; w++ {
+		go func() {
+			for i := range jobs {
+				if ctx.Err() != nil {
+					return
+				}
+				req := handleRequest{Request: requests[i], HttpRequest: batchHttpRequest}
+				results <- batchResult{index: i, response: s.handler.Handle(req)}
+			}
+		}()
+	}
+
+	for received := 0; received < len(toProcess); received++ {
+		select {
+		case result := <-results:
+			responses[result.index] = result.response
+			processed[result.index] = true
+		case <-ctx.Done():
+			for i, request := range requests {
+				if !processed[i] {
+					responses[i] = types.NewResponse(request, nil, types.NewRPCError(types.LimitExceededErrorCode, types.ErrBatchRequestsTimeoutExceeded.Error()))
+				}
+			}
+			return responses
+		}
+	}
+
+	return responses
+}
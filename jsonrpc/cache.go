@@ -0,0 +1,226 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/metrics"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+)
+
+const (
+	// defaultCacheSize is the size used when the cache is enabled without size
+	defaultCacheSize = 10000
+	// defaultCacheReorgCheckInterval is the interval used when the cache is
+	// enabled without reorg check interval
+	defaultCacheReorgCheckInterval = time.Second
+)
+
+// cacheEntry is a response stored in the cache
+type cacheEntry struct {
+	blockNumber uint64
+	value       interface{}
+}
+
+// ConsolidatedCache keeps in memory the responses that refer to consolidated
+// L2 blocks, which never change unless the L2 state is reorganized. A single
+// instance is shared by all the endpoints.
+//
+// The consolidated tip is checked at most once per reorg check interval: if
+// the last consolidated block number decreased or the hash of the last known
+// consolidated block changed, all the responses are removed. Only the
+// responses referring to blocks at or below the last checked tip are stored.
+type ConsolidatedCache struct {
+	cfg   CacheConfig
+	state types.StateInterface
+
+	mu      sync.Mutex
+	entries lru.BasicLRU[string, cacheEntry]
+	// epoch is increased on every reorg, so the responses loaded before the
+	// reorg are not stored after it
+	epoch uint64
+
+	tip          consolidatedTip
+	tipCheckedAt time.Time
+	// checkingTip is set while the tip is loaded from the state, so a single
+	// request checks it and the others keep using the last checked tip
+	checkingTip bool
+}
+
+// consolidatedTip is the last consolidated block known by the cache
+type consolidatedTip struct {
+	known  bool
+	number uint64
+	hash   common.Hash
+}
+
+// NewConsolidatedCache creates a cache for the consolidated responses, returns
+// nil when the cache is disabled
+func NewConsolidatedCache(cfg CacheConfig, st types.StateInterface) *ConsolidatedCache {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Size <= 0 {
+		cfg.Size = defaultCacheSize
+	}
+	if cfg.ReorgCheckInterval.Duration <= 0 {
+		cfg.ReorgCheckInterval.Duration = defaultCacheReorgCheckInterval
+	}
+	return &ConsolidatedCache{
+		cfg:     cfg,
+		state:   st,
+		entries: lru.NewBasicLRU[string, cacheEntry](cfg.Size),
+	}
+}
+
+// cacheLoadFn loads a response and the number of the most recent L2 block it
+// refers to
+type cacheLoadFn func() (interface{}, uint64, types.Error)
+
+// cached returns the response stored for the key of the method, otherwise it
+// loads the response and stores it if it refers to a consolidated block. A nil
+// cache always loads the response.
+func (c *ConsolidatedCache) cached(method, key string, load cacheLoadFn) (interface{}, types.Error) {
+	value, epoch, found := c.get(context.Background(), method, key)
+	if found {
+		return value, nil
+	}
+
+	value, blockNumber, rpcErr := load()
+	if rpcErr != nil {
+		return value, rpcErr
+	}
+	c.put(method, key, blockNumber, value, epoch)
+	return value, nil
+}
+
+// get returns the response stored for the key of the method. The returned
+// epoch must be provided to put when the response is not found, in order to
+// discard it if a reorg happens in the meantime. A nil cache never finds
+// anything.
+func (c *ConsolidatedCache) get(ctx context.Context, method, key string) (interface{}, uint64, bool) {
+	if c == nil {
+		return nil, 0, false
+	}
+
+	tipErr := c.checkTip(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if tipErr != nil {
+		log.Warnf("failed to check the consolidated tip for the rpc cache: %v", tipErr)
+		return nil, c.epoch, false
+	}
+
+	entry, found := c.entries.Get(method + "/" + key)
+	if !found || !c.tip.known || entry.blockNumber > c.tip.number {
+		metrics.CacheMiss(method)
+		return nil, c.epoch, false
+	}
+	metrics.CacheHit(method)
+	return entry.value, c.epoch, true
+}
+
+// put stores the response for the key of the method, as long as it refers to
+// a consolidated block and no reorg happened since the epoch returned by get
+func (c *ConsolidatedCache) put(method, key string, blockNumber uint64, value interface{}, epoch uint64) {
+	if c == nil || value == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.tip.known || epoch != c.epoch || blockNumber > c.tip.number {
+		return
+	}
+	c.entries.Add(method+"/"+key, cacheEntry{blockNumber: blockNumber, value: value})
+}
+
+// checkTip refreshes the last consolidated block once the reorg check
+// interval elapsed, removing all the responses if a reorg is detected. The
+// state is queried without holding the lock
+func (c *ConsolidatedCache) checkTip(ctx context.Context) error {
+	c.mu.Lock()
+	now := time.Now()
+	if c.checkingTip || now.Sub(c.tipCheckedAt) < c.cfg.ReorgCheckInterval.Duration {
+		c.mu.Unlock()
+		return nil
+	}
+	c.checkingTip = true
+	lastTip := c.tip
+	c.mu.Unlock()
+
+	tip, purge, err := c.loadTip(ctx, lastTip)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checkingTip = false
+	if purge {
+		c.purge()
+	}
+	if err != nil {
+		return err
+	}
+	c.tip = tip
+	c.tipCheckedAt = now
+	return nil
+}
+
+// loadTip loads the last consolidated block from the state and compares it
+// with the last checked tip, it returns the new tip and if the responses must
+// be removed because of a reorg or because nothing is consolidated
+func (c *ConsolidatedCache) loadTip(ctx context.Context, lastTip consolidatedTip) (consolidatedTip, bool, error) {
+	tipNumber, err := c.state.GetLastConsolidatedL2BlockNumber(ctx, nil)
+	if errors.Is(err, state.ErrNotFound) {
+		// nothing is consolidated yet
+		return consolidatedTip{}, true, nil
+	} else if err != nil {
+		return consolidatedTip{}, false, err
+	}
+
+	purge := false
+	if lastTip.known {
+		reorged := tipNumber < lastTip.number
+		if !reorged {
+			header, err := c.state.GetL2BlockHeaderByNumber(ctx, lastTip.number, nil)
+			if errors.Is(err, state.ErrNotFound) {
+				reorged = true
+			} else if err != nil {
+				return consolidatedTip{}, false, err
+			} else {
+				reorged = header.Hash() != lastTip.hash
+			}
+		}
+		if reorged {
+			log.Infof("reorg detected below the consolidated block %d, purging the rpc cache", lastTip.number)
+			purge = true
+		} else if tipNumber == lastTip.number {
+			return lastTip, false, nil
+		}
+	}
+
+	header, err := c.state.GetL2BlockHeaderByNumber(ctx, tipNumber, nil)
+	if errors.Is(err, state.ErrNotFound) {
+		// nothing is consolidated yet
+		return consolidatedTip{}, true, nil
+	} else if err != nil {
+		return consolidatedTip{}, purge, err
+	}
+
+	return consolidatedTip{known: true, number: tipNumber, hash: header.Hash()}, purge, nil
+}
+
+// purge removes all the responses and forgets the consolidated tip
+func (c *ConsolidatedCache) purge() {
+	c.entries.Purge()
+	c.epoch++
+	c.tip = consolidatedTip{}
+}
@@ -0,0 +1,139 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/mocks"
+	rpcTypes "github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestL2Header(number int64, extra byte) *state.L2Header {
+	return state.NewL2Header(&ethTypes.Header{Number: big.NewInt(number), Extra: []byte{extra}})
+}
+
+func TestConsolidatedCacheDisabled(t *testing.T) {
+	c := NewConsolidatedCache(CacheConfig{Enabled: false}, nil)
+	assert.Nil(t, c)
+
+	loads := 0
+	for i := 0; i < 2; i++ {
+		v, rpcErr := c.cached("method", "key", func() (interface{}, uint64, rpcTypes.Error) {
+			loads++
+			return "value", 1, nil
+		})
+		require.Nil(t, rpcErr)
+		assert.Equal(t, "value", v)
+	}
+	assert.Equal(t, 2, loads)
+}
+
+func TestConsolidatedCache(t *testing.T) {
+	st := mocks.NewStateMock(t)
+	// the tip is checked on every request
+	c := NewConsolidatedCache(CacheConfig{Enabled: true, Size: 10, ReorgCheckInterval: types.NewDuration(time.Nanosecond)}, st)
+	require.NotNil(t, c)
+
+	loads := 0
+	load := func(value string, blockNumber uint64) cacheLoadFn {
+		return func() (interface{}, uint64, rpcTypes.Error) {
+			loads++
+			return value, blockNumber, nil
+		}
+	}
+	assertCached := func(t *testing.T, key string, blockNumber uint64, expectedLoads int) {
+		v, rpcErr := c.cached("method", key, load("value-"+key, blockNumber))
+		require.Nil(t, rpcErr)
+		assert.Equal(t, "value-"+key, v)
+		assert.Equal(t, expectedLoads, loads)
+	}
+
+	st.On("GetLastConsolidatedL2BlockNumber", context.Background(), nil).Return(uint64(10), nil).Times(4)
+	st.On("GetL2BlockHeaderByNumber", context.Background(), uint64(10), nil).Return(newTestL2Header(10, 1), nil).Times(4)
+
+	t.Run("consolidated responses are cached", func(t *testing.T) {
+		assertCached(t, "a", 10, 1)
+		assertCached(t, "a", 10, 1)
+	})
+
+	t.Run("not consolidated responses are not cached", func(t *testing.T) {
+		assertCached(t, "b", 11, 2)
+		assertCached(t, "b", 11, 3)
+	})
+
+	st.On("GetLastConsolidatedL2BlockNumber", context.Background(), nil).Return(uint64(12), nil).Once()
+	st.On("GetL2BlockHeaderByNumber", context.Background(), uint64(10), nil).Return(newTestL2Header(10, 2), nil).Once()
+	st.On("GetL2BlockHeaderByNumber", context.Background(), uint64(12), nil).Return(newTestL2Header(12, 2), nil).Once()
+
+	t.Run("reorg purges the cache", func(t *testing.T) {
+		assertCached(t, "a", 10, 4)
+	})
+
+	st.On("GetLastConsolidatedL2BlockNumber", context.Background(), nil).Return(uint64(5), nil).Once()
+	st.On("GetL2BlockHeaderByNumber", context.Background(), uint64(5), nil).Return(newTestL2Header(5, 3), nil).Once()
+
+	t.Run("responses loaded before a reorg are discarded", func(t *testing.T) {
+		_, epoch, found := c.get(context.Background(), "method", "c")
+		require.False(t, found)
+		c.put("method", "c", 5, "value-c", epoch-1)
+		_, found = c.entries.Get("method/c")
+		assert.False(t, found)
+
+		c.put("method", "c", 5, "value-c", epoch)
+		_, found = c.entries.Get("method/c")
+		assert.True(t, found)
+	})
+
+	st.On("GetLastConsolidatedL2BlockNumber", context.Background(), nil).Return(uint64(0), errors.New("failed")).Once()
+
+	t.Run("failing to check the tip bypasses the cache", func(t *testing.T) {
+		assertCached(t, "c", 5, 5)
+	})
+
+	st.On("GetLastConsolidatedL2BlockNumber", context.Background(), nil).Return(uint64(0), state.ErrNotFound).Twice()
+
+	t.Run("nothing consolidated purges the cache", func(t *testing.T) {
+		assertCached(t, "c", 5, 6)
+		assertCached(t, "c", 5, 7)
+	})
+}
+
+func TestConsolidatedCacheCheckTipWithoutLock(t *testing.T) {
+	st := mocks.NewStateMock(t)
+	c := NewConsolidatedCache(CacheConfig{Enabled: true, Size: 10, ReorgCheckInterval: types.NewDuration(time.Nanosecond)}, st)
+	require.NotNil(t, c)
+
+	loading := make(chan struct{})
+	release := make(chan struct{})
+	st.On("GetLastConsolidatedL2BlockNumber", context.Background(), nil).Run(func(args mock.Arguments) {
+		close(loading)
+		<-release
+	}).Return(uint64(10), nil).Once()
+	st.On("GetL2BlockHeaderByNumber", context.Background(), uint64(10), nil).Return(newTestL2Header(10, 1), nil).Once()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _, found := c.get(context.Background(), "method", "a")
+		assert.False(t, found)
+	}()
+	<-loading
+
+	// while the tip is loaded the other requests neither wait for it nor load it again
+	_, _, found := c.get(context.Background(), "method", "a")
+	assert.False(t, found)
+
+	close(release)
+	<-done
+	assert.True(t, c.tip.known)
+	assert.Equal(t, uint64(10), c.tip.number)
+}
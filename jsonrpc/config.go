@@ -1,10 +1,29 @@
 package jsonrpc
 
 import (
+	"fmt"
+
 	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/db"
+	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
 )
 
+const (
+	// FilterStorageTypeMemory keeps the filters in the memory of the process
+	FilterStorageTypeMemory = "memory"
+	// FilterStorageTypePostgres keeps the filters created via HTTP in a postgres
+	// database, so they can be shared by several RPC replicas
+	FilterStorageTypePostgres = "postgres"
+
+	// SubscriptionQueueOverflowPolicyDropOldest drops the oldest notification
+	// waiting to be sent when the subscription queue of a connection is full
+	SubscriptionQueueOverflowPolicyDropOldest = "dropOldest"
+	// SubscriptionQueueOverflowPolicyDisconnect closes the connection with an
+	// error when the subscription queue of a connection is full
+	SubscriptionQueueOverflowPolicyDisconnect = "disconnect"
+)
+
 // Config represents the configuration of the json rpc
 type Config struct {
 	// Host defines the network adapter that will be used to serve the HTTP requests
@@ -44,6 +63,23 @@ type Config struct {
 	// BatchRequestsLimit defines the limit of requests that can be incorporated into each batch request
 	BatchRequestsLimit uint `mapstructure:"BatchRequestsLimit"`
 
+	// BatchRequestsConcurrency defines how many requests of a batch request are
+	// processed concurrently, if zero or one they are processed sequentially
+	BatchRequestsConcurrency uint `mapstructure:"BatchRequestsConcurrency"`
+
+	// BatchRequestsCostLimit defines the max cost of the requests processed for
+	// each batch request, the cost of each request is the cost of its method
+	// defined in RateLimit.Methods, a single credit by default. The requests
+	// after the limit is exceeded are not processed and return an error, if
+	// zero it means no limit
+	BatchRequestsCostLimit uint64 `mapstructure:"BatchRequestsCostLimit"`
+
+	// BatchRequestsTimeout defines the max time spent processing each batch
+	// request, the requests not processed in time return an error and the
+	// responses of the ones still running are discarded, if zero it means no
+	// limit
+	BatchRequestsTimeout types.Duration `mapstructure:"BatchRequestsTimeout"`
+
 	// L2Coinbase defines which address is going to receive the fees
 	L2Coinbase common.Address
 
@@ -62,6 +98,149 @@ type Config struct {
 	// EnableHttpLog allows the user to enable or disable the logs related to the HTTP
 	// requests to be captured by the server.
 	EnableHttpLog bool `mapstructure:"EnableHttpLog"`
+
+	// FilterStorage configuration
+	FilterStorage FilterStorageConfig `mapstructure:"FilterStorage"`
+
+	// RateLimit configuration
+	RateLimit RateLimitConfig `mapstructure:"RateLimit"`
+
+	// Cache configuration
+	Cache CacheConfig `mapstructure:"Cache"`
+
+	// ZKCountersLimits are the batch constraints reported as the limits of the
+	// zk counters by zkevm_estimateCounters and as the batch constraints by
+	// zkevm_getChainConfig, they are set from State.Batch.Constraints
+	ZKCountersLimits state.BatchConstraintsCfg
+
+	// Network is the network configuration reported by zkevm_getChainConfig,
+	// it's set from the network config and the rollup id read from L1
+	Network NetworkConfig
+
+	// MaxTraceFilterBlockRange is a configuration to set the max range for block number when
+	// filtering traces with trace_filter, if zero it means no limit
+	MaxTraceFilterBlockRange uint64 `mapstructure:"MaxTraceFilterBlockRange"`
+
+	// MaxTraceFilterTxs is a configuration to set the max number of transactions traced
+	// by a trace_filter request, if zero it means no limit
+	MaxTraceFilterTxs uint64 `mapstructure:"MaxTraceFilterTxs"`
+
+	// IPC configuration
+	IPC IPCConfig `mapstructure:"IPC"`
+
+	// SendRawTransactionSyncTimeout defines the max time eth_sendRawTransactionSync
+	// waits for the transaction to be included in a stored l2 block, the timeout
+	// requested by the client can only be shorter
+	SendRawTransactionSyncTimeout types.Duration `mapstructure:"SendRawTransactionSyncTimeout"`
+}
+
+// NetworkConfig has the parameters of the network the node is connected to
+type NetworkConfig struct {
+	// L1ChainID is the chain id of the L1 network
+	L1ChainID uint64
+
+	// RollupID is the id of the rollup in the rollup manager contract
+	RollupID uint32
+
+	// ZkEVMAddr is the address of the L1 polygonZkEVM contract
+	ZkEVMAddr common.Address
+
+	// RollupManagerAddr is the address of the L1 polygonRollupManager contract
+	RollupManagerAddr common.Address
+
+	// PolAddr is the address of the L1 Pol token contract
+	PolAddr common.Address
+
+	// GlobalExitRootManagerAddr is the address of the L1 GlobalExitRootManager contract
+	GlobalExitRootManagerAddr common.Address
+}
+
+// CacheConfig has parameters to config the in-process cache of the responses
+// that refer to consolidated L2 blocks
+type CacheConfig struct {
+	// Enabled defines if the cache is enabled or disabled
+	Enabled bool `mapstructure:"Enabled"`
+
+	// Size is the max number of responses kept in the cache
+	Size int `mapstructure:"Size"`
+
+	// ReorgCheckInterval is how often the last consolidated L2 block is checked
+	// to detect reorgs, all the cached responses are removed when a reorg is detected
+	ReorgCheckInterval types.Duration `mapstructure:"ReorgCheckInterval"`
+}
+
+// RateLimitConfig has parameters to config the api keys and the rate limits
+// applied to the requests sent via HTTP, batch or WebSockets
+type RateLimitConfig struct {
+	// Enabled defines if the api keys and the rate limits are enabled or disabled
+	Enabled bool `mapstructure:"Enabled"`
+
+	// APIKeyHeader is the HTTP header used to provide the api key, a registered
+	// api key can also be provided as the URL path, e.g. http://host:port/<api key>
+	APIKeyHeader string `mapstructure:"APIKeyHeader"`
+
+	// APIKeyRequired defines if the requests without api key are rejected
+	APIKeyRequired bool `mapstructure:"APIKeyRequired"`
+
+	// AnonymousCreditsPerSecond is the quota of credits per second of each IP
+	// sending requests without api key, if zero it means no limit
+	AnonymousCreditsPerSecond float64 `mapstructure:"AnonymousCreditsPerSecond"`
+
+	// TrustedProxies are the IPs or CIDRs of the proxies in front of the server,
+	// the IP of the requests without api key is read from the X-Forwarded-For or
+	// X-Real-IP headers only when the request is sent by one of them
+	TrustedProxies []string `mapstructure:"TrustedProxies"`
+
+	// APIKeys are the api keys accepted by the server
+	APIKeys []APIKeyConfig `mapstructure:"APIKeys"`
+
+	// Methods defines the cost and the limit of specific methods, the methods
+	// not listed here cost a single credit and have no limit
+	Methods []MethodLimitConfig `mapstructure:"Methods"`
+}
+
+// APIKeyConfig has parameters to config an api key
+type APIKeyConfig struct {
+	// Name identifies the api key in the metrics
+	Name string `mapstructure:"Name"`
+
+	// Key is the value that must be provided by the requests
+	Key string `mapstructure:"Key"`
+
+	// CreditsPerSecond is the quota of credits per second of the api key, if
+	// zero it means no limit
+	CreditsPerSecond float64 `mapstructure:"CreditsPerSecond"`
+}
+
+// MethodLimitConfig has parameters to config the cost and the limit of a method
+type MethodLimitConfig struct {
+	// Method is the name of the method, e.g. eth_getLogs
+	Method string `mapstructure:"Method"`
+
+	// Cost is the number of credits consumed by each request to the method, if
+	// zero it costs a single credit
+	Cost uint64 `mapstructure:"Cost"`
+
+	// MaxRequestsPerSecond is the number of requests per second each caller can
+	// send to the method, if zero it means no limit
+	MaxRequestsPerSecond float64 `mapstructure:"MaxRequestsPerSecond"`
+}
+
+// FilterStorageConfig has parameters to config where the filters are stored
+type FilterStorageConfig struct {
+	// Type defines where the filters are stored, it can be "memory" or "postgres".
+	// Use "postgres" when several RPC replicas are behind a load balancer, so any
+	// replica can serve the filters created via HTTP by any other replica
+	Type string `mapstructure:"Type"`
+
+	// FilterTimeout is the time after which a filter created via HTTP that is not
+	// polled is removed, with any storage type. If zero the filters never expire,
+	// which is the default so the filters stored in memory are kept as before,
+	// set it when Type is "postgres" so the database doesn't keep growing
+	FilterTimeout types.Duration `mapstructure:"FilterTimeout"`
+
+	// DB is the database configuration used when Type is "postgres"
+	DB db.Config `mapstructure:"DB"`
 }
 
 // WebSocketsConfig has parameters to config the rpc websocket support
@@ -77,4 +256,42 @@ type WebSocketsConfig struct {
 
 	// ReadLimit defines the maximum size of a message read from the client (in bytes)
 	ReadLimit int64 `mapstructure:"ReadLimit"`
+
+	// SubscriptionQueueSize defines the maximum number of subscription notifications
+	// waiting to be sent to a WS or IPC connection, 0 means no limit
+	SubscriptionQueueSize uint `mapstructure:"SubscriptionQueueSize"`
+
+	// SubscriptionQueueOverflowPolicy defines what happens when the subscription queue
+	// of a connection is full, "dropOldest" drops the oldest notification and
+	// "disconnect" closes the connection with an error, the notifications replayed
+	// when a subscription is resumed wait for room in the queue instead
+	SubscriptionQueueOverflowPolicy string `mapstructure:"SubscriptionQueueOverflowPolicy"`
+
+	// MaxResumeBlockRange defines the maximum number of blocks replayed when a newHeads
+	// or logs subscription is resumed from a past block, 0 means no limit
+	MaxResumeBlockRange uint64 `mapstructure:"MaxResumeBlockRange"`
+}
+
+// validateSubscriptionQueueOverflowPolicy checks the overflow policy is known,
+// an empty policy drops the oldest notifications
+func (c WebSocketsConfig) validateSubscriptionQueueOverflowPolicy() error {
+	switch c.SubscriptionQueueOverflowPolicy {
+	case SubscriptionQueueOverflowPolicyDropOldest, SubscriptionQueueOverflowPolicyDisconnect, "":
+		return nil
+	default:
+		return fmt.Errorf("unknown subscription queue overflow policy %q", c.SubscriptionQueueOverflowPolicy)
+	}
+}
+
+// IPCConfig has parameters to config the rpc ipc support, the ipc server
+// serves the requests and the subscriptions over a unix socket
+type IPCConfig struct {
+	// Enabled defines if the IPC requests are enabled or disabled
+	Enabled bool `mapstructure:"Enabled"`
+
+	// Path is the path of the unix socket file, it's replaced if it already exists
+	Path string `mapstructure:"Path"`
+
+	// FileMode defines the permissions of the unix socket file in octal notation
+	FileMode string `mapstructure:"FileMode"`
 }
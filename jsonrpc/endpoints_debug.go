@@ -15,6 +15,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/jackc/pgx/v4"
 )
 
@@ -114,6 +115,38 @@ func (d *DebugEndpoints) TraceBlockByHash(hash types.ArgHash, cfg *traceConfig)
 	})
 }
 
+// TraceCall creates a response for debug_traceCall request.
+// See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-debug#debugtracecall
+func (d *DebugEndpoints) TraceCall(arg *types.TxArgs, blockArg *types.BlockNumberOrHash, cfg *traceConfig) (interface{}, types.Error) {
+	return d.txMan.NewDbTxScope(d.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		if arg == nil {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument 0", nil, false)
+		}
+
+		block, blockToProcess, respErr := getBlockToProcessByArg(ctx, d.state, d.etherman, blockArg, dbTx)
+		if respErr != nil {
+			return nil, respErr
+		}
+		if respErr := setDefaultGas(ctx, d.state, arg, block, dbTx); respErr != nil {
+			return nil, respErr
+		}
+
+		defaultSenderAddress := common.HexToAddress(DefaultSenderAddress)
+		sender, tx, err := arg.ToTransaction(ctx, d.state, d.cfg.MaxCumulativeGasUsed, block.Root(), defaultSenderAddress, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to convert arguments into an unsigned transaction", err, false)
+		}
+
+		result, err := d.state.DebugCall(ctx, tx, sender, blockToProcess, toStateTraceConfig(cfg), dbTx)
+		if err != nil {
+			errorMessage := fmt.Sprintf("failed to get trace: %v", err.Error())
+			return nil, types.NewRPCError(types.DefaultErrorCode, errorMessage)
+		}
+
+		return result.TraceResult, nil
+	})
+}
+
 // TraceBatchByNumber creates a response for debug_traceBatchByNumber request.
 // this endpoint tries to help clients to get traces at once for all the transactions
 // attached to the same batch.
@@ -253,6 +286,90 @@ func (d *DebugEndpoints) TraceBatchByNumber(httpRequest *http.Request, number ty
 	})
 }
 
+// GetRawTransaction creates a response for debug_getRawTransaction request.
+// See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-debug#debuggetrawtransaction
+func (d *DebugEndpoints) GetRawTransaction(hash types.ArgHash) (interface{}, types.Error) {
+	return d.txMan.NewDbTxScope(d.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		tx, err := d.state.GetTransactionByHash(ctx, hash.Hash(), dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get tx from state", err, true)
+		}
+
+		raw, err := tx.MarshalBinary()
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to encode tx", err, true)
+		}
+
+		return types.ArgBytes(raw), nil
+	})
+}
+
+// GetRawReceipts creates a response for debug_getRawReceipts request.
+// See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-debug#debuggetrawreceipts
+func (d *DebugEndpoints) GetRawReceipts(blockArg types.BlockNumberOrHash) (interface{}, types.Error) {
+	return d.txMan.NewDbTxScope(d.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		block, receipts, rpcErr := getBlockAndReceiptsByArg(ctx, d.state, d.etherman, blockArg, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		} else if block == nil {
+			return nil, types.NewRPCError(types.DefaultErrorCode, "block not found")
+		}
+
+		rawReceipts := make([]types.ArgBytes, 0, len(receipts))
+		for _, receipt := range receipts {
+			raw, err := receipt.MarshalBinary()
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("failed to encode receipt for tx %v", receipt.TxHash.String()), err, true)
+			}
+			rawReceipts = append(rawReceipts, raw)
+		}
+
+		return rawReceipts, nil
+	})
+}
+
+// GetRawHeader creates a response for debug_getRawHeader request.
+// See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-debug#debuggetrawheader
+func (d *DebugEndpoints) GetRawHeader(blockArg types.BlockNumberOrHash) (interface{}, types.Error) {
+	return d.txMan.NewDbTxScope(d.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		block, rpcErr := getBlockByArg(ctx, d.state, d.etherman, &blockArg, true, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		} else if block == nil {
+			return nil, types.NewRPCError(types.DefaultErrorCode, "header not found")
+		}
+
+		raw, err := rlp.EncodeToBytes(block.Header())
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to encode header", err, true)
+		}
+
+		return types.ArgBytes(raw), nil
+	})
+}
+
+// GetRawBlock creates a response for debug_getRawBlock request.
+// See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-debug#debuggetrawblock
+func (d *DebugEndpoints) GetRawBlock(blockArg types.BlockNumberOrHash) (interface{}, types.Error) {
+	return d.txMan.NewDbTxScope(d.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		block, rpcErr := getBlockByArg(ctx, d.state, d.etherman, &blockArg, true, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		} else if block == nil {
+			return nil, types.NewRPCError(types.DefaultErrorCode, "block not found")
+		}
+
+		raw, err := rlp.EncodeToBytes(block)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to encode block", err, true)
+		}
+
+		return types.ArgBytes(raw), nil
+	})
+}
+
 func (d *DebugEndpoints) buildTraceBlock(ctx context.Context, txs []*ethTypes.Transaction, cfg *traceConfig, dbTx pgx.Tx) (interface{}, types.Error) {
 	traces := []traceBlockTransactionResponse{}
 	for _, tx := range txs {
@@ -271,12 +388,26 @@ func (d *DebugEndpoints) buildTraceBlock(ctx context.Context, txs []*ethTypes.Tr
 }
 
 func (d *DebugEndpoints) buildTraceTransaction(ctx context.Context, hash common.Hash, cfg *traceConfig, dbTx pgx.Tx) (interface{}, types.Error) {
+	result, err := d.state.DebugTransaction(ctx, hash, toStateTraceConfig(cfg), dbTx)
+	if errors.Is(err, state.ErrNotFound) {
+		return RPCErrorResponse(types.DefaultErrorCode, "transaction not found", nil, false)
+	} else if err != nil {
+		errorMessage := fmt.Sprintf("failed to get trace: %v", err.Error())
+		return nil, types.NewRPCError(types.DefaultErrorCode, errorMessage)
+	}
+
+	return result.TraceResult, nil
+}
+
+// toStateTraceConfig converts the trace config received in the request
+// to the trace config expected by the state, applying the defaults
+func toStateTraceConfig(cfg *traceConfig) state.TraceConfig {
 	traceCfg := cfg
 	if traceCfg == nil {
 		traceCfg = defaultTraceConfig
 	}
 
-	stateTraceConfig := state.TraceConfig{
+	return state.TraceConfig{
 		DisableStack:     traceCfg.DisableStack,
 		DisableStorage:   traceCfg.DisableStorage,
 		EnableMemory:     traceCfg.EnableMemory,
@@ -284,15 +415,6 @@ func (d *DebugEndpoints) buildTraceTransaction(ctx context.Context, hash common.
 		Tracer:           traceCfg.Tracer,
 		TracerConfig:     traceCfg.TracerConfig,
 	}
-	result, err := d.state.DebugTransaction(ctx, hash, stateTraceConfig, dbTx)
-	if errors.Is(err, state.ErrNotFound) {
-		return RPCErrorResponse(types.DefaultErrorCode, "transaction not found", nil, false)
-	} else if err != nil {
-		errorMessage := fmt.Sprintf("failed to get trace: %v", err.Error())
-		return nil, types.NewRPCError(types.DefaultErrorCode, errorMessage)
-	}
-
-	return result.TraceResult, nil
 }
 
 // waitTimeout waits for the waitGroup for the specified max timeout.
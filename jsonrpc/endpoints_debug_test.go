@@ -0,0 +1,301 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceCall(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	callTracer := "callTracer"
+
+	type testCase struct {
+		name           string
+		params         []interface{}
+		expectedResult json.RawMessage
+		expectedError  types.Error
+		setupMocks     func(*mocksWrapper, *testCase)
+	}
+
+	testCases := []*testCase{
+		{
+			name: "trace call on latest block with default tracer",
+			params: []interface{}{
+				types.TxArgs{
+					From:     state.HexToAddressPtr("0x1"),
+					To:       state.HexToAddressPtr("0x2"),
+					Gas:      types.ArgUint64Ptr(24000),
+					GasPrice: types.ArgBytesPtr(big.NewInt(1).Bytes()),
+					Data:     types.ArgBytesPtr([]byte("data")),
+				},
+				latest,
+			},
+			expectedResult: json.RawMessage(`{"gas":21000,"failed":false,"returnValue":"","structLogs":[]}`),
+			setupMocks: func(m *mocksWrapper, tc *testCase) {
+				nonce := uint64(7)
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(blockNumOne.Uint64(), nil).Once()
+				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumOne, Root: blockRoot}))
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
+				txArgs := tc.params[0].(types.TxArgs)
+				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
+				txMatchBy := mock.MatchedBy(func(tx *ethTypes.Transaction) bool {
+					return tx != nil && tx.To().Hex() == txArgs.To.Hex() && tx.Gas() == uint64(*txArgs.Gas) && tx.Nonce() == nonce
+				})
+				traceConfigMatchBy := mock.MatchedBy(func(cfg state.TraceConfig) bool {
+					return cfg.IsDefaultTracer()
+				})
+				m.State.
+					On("DebugCall", context.Background(), txMatchBy, *txArgs.From, nilUint64, traceConfigMatchBy, m.DbTx).
+					Return(&runtime.ExecutionResult{TraceResult: tc.expectedResult}, nil).
+					Once()
+			},
+		},
+		{
+			name: "trace call on block by number with call tracer",
+			params: []interface{}{
+				types.TxArgs{
+					From: state.HexToAddressPtr("0x1"),
+					To:   state.HexToAddressPtr("0x2"),
+					Gas:  types.ArgUint64Ptr(24000),
+				},
+				"0xa",
+				map[string]interface{}{"tracer": callTracer},
+			},
+			expectedResult: json.RawMessage(`{"type":"CALL","from":"0x0000000000000000000000000000000000000001","to":"0x0000000000000000000000000000000000000002"}`),
+			setupMocks: func(m *mocksWrapper, tc *testCase) {
+				nonce := uint64(7)
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumTen, Root: blockRoot}))
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumTenUint64, m.DbTx).Return(block, nil).Once()
+				txArgs := tc.params[0].(types.TxArgs)
+				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
+				traceConfigMatchBy := mock.MatchedBy(func(cfg state.TraceConfig) bool {
+					return cfg.IsCallTracer()
+				})
+				m.State.
+					On("DebugCall", context.Background(), mock.IsType(&ethTypes.Transaction{}), *txArgs.From, &blockNumTenUint64, traceConfigMatchBy, m.DbTx).
+					Return(&runtime.ExecutionResult{TraceResult: tc.expectedResult}, nil).
+					Once()
+			},
+		},
+		{
+			name: "trace call fails to execute the call",
+			params: []interface{}{
+				types.TxArgs{
+					From: state.HexToAddressPtr("0x1"),
+					To:   state.HexToAddressPtr("0x2"),
+					Gas:  types.ArgUint64Ptr(24000),
+				},
+				latest,
+			},
+			expectedError: types.NewRPCError(types.DefaultErrorCode, "failed to get trace: failed to process unsigned transaction"),
+			setupMocks: func(m *mocksWrapper, tc *testCase) {
+				nonce := uint64(7)
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(blockNumOne.Uint64(), nil).Once()
+				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumOne, Root: blockRoot}))
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
+				txArgs := tc.params[0].(types.TxArgs)
+				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
+				m.State.
+					On("DebugCall", context.Background(), mock.IsType(&ethTypes.Transaction{}), *txArgs.From, nilUint64, mock.IsType(state.TraceConfig{}), m.DbTx).
+					Return(nil, errors.New("failed to process unsigned transaction")).
+					Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.setupMocks(m, testCase)
+
+			res, err := s.JSONRPCCall("debug_traceCall", testCase.params...)
+			require.NoError(t, err)
+
+			if testCase.expectedResult != nil {
+				require.Nil(t, res.Error)
+				assert.JSONEq(t, string(testCase.expectedResult), string(res.Result))
+			}
+
+			if testCase.expectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, testCase.expectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, testCase.expectedError.Error(), res.Error.Message)
+			}
+		})
+	}
+}
+
+func TestGetRawData(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := ethTypes.NewLondonSigner(big.NewInt(0).SetUint64(s.ChainID()))
+	signedTx, err := ethTypes.SignNewTx(privateKey, signer, &ethTypes.DynamicFeeTx{
+		ChainID:   big.NewInt(0).SetUint64(s.ChainID()),
+		Nonce:     1,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(2),
+		Gas:       21000,
+		To:        state.HexToAddressPtr("0x2"),
+		Value:     big.NewInt(1),
+	})
+	require.NoError(t, err)
+
+	header := &ethTypes.Header{Number: big.NewInt(1), Root: blockRoot}
+	block := state.NewL2Block(state.NewL2Header(header), []*ethTypes.Transaction{signedTx}, []*state.L2Header{}, []*ethTypes.Receipt{}, &trie.StackTrie{})
+
+	receipt := ethTypes.NewReceipt([]byte{}, false, 21000)
+	receipt.Type = signedTx.Type()
+	receipt.TxHash = signedTx.Hash()
+	receipt.Logs = []*ethTypes.Log{{Address: common.HexToAddress("0x3"), Topics: []common.Hash{common.HexToHash("0x4")}, Data: []byte{0x5}}}
+	receipt.Bloom = ethTypes.CreateBloom(ethTypes.Receipts{receipt})
+
+	rawTx, err := signedTx.MarshalBinary()
+	require.NoError(t, err)
+	rawReceipt, err := receipt.MarshalBinary()
+	require.NoError(t, err)
+	rawHeader, err := rlp.EncodeToBytes(block.Header().Header)
+	require.NoError(t, err)
+	rawBlock, err := rlp.EncodeToBytes(block.Block)
+	require.NoError(t, err)
+
+	type testCase struct {
+		name           string
+		method         string
+		params         []interface{}
+		expectedResult interface{}
+		expectedError  types.Error
+		setupMocks     func(*mocksWrapper, *testCase)
+	}
+
+	testCases := []*testCase{
+		{
+			name:           "get raw transaction",
+			method:         "debug_getRawTransaction",
+			params:         []interface{}{signedTx.Hash().String()},
+			expectedResult: types.ArgBytes(rawTx),
+			setupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetTransactionByHash", context.Background(), signedTx.Hash(), m.DbTx).Return(signedTx, nil).Once()
+			},
+		},
+		{
+			name:   "get raw transaction not found",
+			method: "debug_getRawTransaction",
+			params: []interface{}{signedTx.Hash().String()},
+			setupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetTransactionByHash", context.Background(), signedTx.Hash(), m.DbTx).Return(nil, state.ErrNotFound).Once()
+			},
+		},
+		{
+			name:           "get raw receipts",
+			method:         "debug_getRawReceipts",
+			params:         []interface{}{"0x1"},
+			expectedResult: []types.ArgBytes{rawReceipt},
+			setupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL2BlockByNumber", context.Background(), uint64(1), m.DbTx).Return(block, nil).Once()
+				m.State.On("GetReceiptsByL2BlockNumber", context.Background(), uint64(1), m.DbTx).Return([]*ethTypes.Receipt{receipt}, nil).Once()
+			},
+		},
+		{
+			name:          "get raw receipts block not found",
+			method:        "debug_getRawReceipts",
+			params:        []interface{}{"0x2"},
+			expectedError: types.NewRPCError(types.DefaultErrorCode, "block not found"),
+			setupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL2BlockByNumber", context.Background(), uint64(2), m.DbTx).Return(nil, state.ErrNotFound).Once()
+			},
+		},
+		{
+			name:           "get raw header by hash",
+			method:         "debug_getRawHeader",
+			params:         []interface{}{block.Hash().String()},
+			expectedResult: types.ArgBytes(rawHeader),
+			setupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL2BlockByHash", context.Background(), block.Hash(), m.DbTx).Return(block, nil).Once()
+			},
+		},
+		{
+			name:          "get raw header not found",
+			method:        "debug_getRawHeader",
+			params:        []interface{}{"0x2"},
+			expectedError: types.NewRPCError(types.DefaultErrorCode, "header not found"),
+			setupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL2BlockByNumber", context.Background(), uint64(2), m.DbTx).Return(nil, state.ErrNotFound).Once()
+			},
+		},
+		{
+			name:           "get raw block",
+			method:         "debug_getRawBlock",
+			params:         []interface{}{latest},
+			expectedResult: types.ArgBytes(rawBlock),
+			setupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(uint64(1), nil).Once()
+				m.State.On("GetL2BlockByNumber", context.Background(), uint64(1), m.DbTx).Return(block, nil).Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.setupMocks(m, testCase)
+
+			res, err := s.JSONRPCCall(testCase.method, testCase.params...)
+			require.NoError(t, err)
+
+			if testCase.expectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, testCase.expectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, testCase.expectedError.Error(), res.Error.Message)
+				return
+			}
+
+			require.Nil(t, res.Error)
+			if testCase.expectedResult == nil {
+				assert.Equal(t, "null", string(res.Result))
+				return
+			}
+
+			expected, err := json.Marshal(testCase.expectedResult)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(expected), string(res.Result))
+		})
+	}
+}
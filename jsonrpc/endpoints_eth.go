@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"math/big"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/gasprice"
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/client"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
@@ -31,23 +33,56 @@ const (
 
 	// maxTopics is the max number of topics a log can have
 	maxTopics = 4
+
+	// maxFeeHistoryBlockCount is the max number of blocks that can be requested to eth_feeHistory,
+	// bigger block counts are capped to this value
+	maxFeeHistoryBlockCount = 1024
+
+	// maxFeeHistoryRewardPercentiles is the max number of reward percentiles that can be requested to eth_feeHistory
+	maxFeeHistoryRewardPercentiles = 100
+
+	// maxPriorityFeePerGasCheckBlocks is the number of l2 blocks sampled by eth_maxPriorityFeePerGas
+	maxPriorityFeePerGasCheckBlocks = 20
+
+	// maxPriorityFeePerGasSamplesPerBlock is the number of tips sampled by eth_maxPriorityFeePerGas on each l2 block
+	maxPriorityFeePerGasSamplesPerBlock = 3
+
+	// maxPriorityFeePerGasPercentile is the percentile of the sampled tips suggested by eth_maxPriorityFeePerGas
+	maxPriorityFeePerGasPercentile = 60
+
+	// resumeLogsPageSize is the number of logs read from the state at once while
+	// replaying the logs missed by a resumed logs subscription
+	resumeLogsPageSize = 1000
+
+	// maxConditionalTxKnownSlots is the max number of storage slots the conditions
+	// of a tx sent with eth_sendRawTransactionConditional can check, the sequencer
+	// reads them again from the state tree before processing the tx, so the limit
+	// is kept low to bound the time they take
+	maxConditionalTxKnownSlots = 32
 )
 
+// errSubscriptionResumeFailed is sent to the clients whose subscription failed
+// to replay the blocks they missed
+var errSubscriptionResumeFailed = errors.New("failed to replay the missed blocks of the subscription")
+
 // EthEndpoints contains implementations for the "eth" RPC endpoints
 type EthEndpoints struct {
-	cfg      Config
-	chainID  uint64
-	pool     types.PoolInterface
-	state    types.StateInterface
-	etherman types.EthermanInterface
-	storage  storageInterface
-	txMan    DBTxManager
+	cfg       Config
+	chainID   uint64
+	pool      types.PoolInterface
+	state     types.StateInterface
+	etherman  types.EthermanInterface
+	storage   storageInterface
+	cache     *ConsolidatedCache
+	txMan     DBTxManager
+	txWaiters *txWaiters
 }
 
 // NewEthEndpoints creates an new instance of Eth
-func NewEthEndpoints(cfg Config, chainID uint64, p types.PoolInterface, s types.StateInterface, etherman types.EthermanInterface, storage storageInterface) *EthEndpoints {
-	e := &EthEndpoints{cfg: cfg, chainID: chainID, pool: p, state: s, etherman: etherman, storage: storage}
+func NewEthEndpoints(cfg Config, chainID uint64, p types.PoolInterface, s types.StateInterface, etherman types.EthermanInterface, storage storageInterface, cache *ConsolidatedCache) *EthEndpoints {
+	e := &EthEndpoints{cfg: cfg, chainID: chainID, pool: p, state: s, etherman: etherman, storage: storage, cache: cache, txWaiters: newTxWaiters()}
 	s.RegisterNewL2BlockEventHandler(e.onNewL2Block)
+	s.RegisterNewBatchEventHandler(e.onNewBatch)
 
 	return e
 }
@@ -68,37 +103,25 @@ func (e *EthEndpoints) BlockNumber() (interface{}, types.Error) {
 // executed contract and potential error.
 // Note, this function doesn't make any changes in the state/blockchain and is
 // useful to execute view/pure methods and retrieve values.
-func (e *EthEndpoints) Call(arg *types.TxArgs, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
+// The optional state override replaces the balance, nonce, code or storage of
+// the given accounts during the execution.
+func (e *EthEndpoints) Call(arg *types.TxArgs, blockArg *types.BlockNumberOrHash, stateOverrideArg types.StateOverride) (interface{}, types.Error) {
 	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
 		if arg == nil {
 			return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument 0", nil, false)
 		} else if blockArg == nil {
 			return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument 1", nil, false)
 		}
-		block, respErr := e.getBlockByArg(ctx, blockArg, dbTx)
+		stateOverride := stateOverrideArg.ToStateOverride()
+		if err := stateOverride.Validate(); err != nil {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, err.Error(), nil, false)
+		}
+		block, blockToProcess, respErr := getBlockToProcessByArg(ctx, e.state, e.etherman, blockArg, dbTx)
 		if respErr != nil {
 			return nil, respErr
 		}
-		var blockToProcess *uint64
-		if blockArg != nil {
-			blockNumArg := blockArg.Number()
-			if blockNumArg != nil && (*blockArg.Number() == types.LatestBlockNumber || *blockArg.Number() == types.PendingBlockNumber) {
-				blockToProcess = nil
-			} else {
-				n := block.NumberU64()
-				blockToProcess = &n
-			}
-		}
-
-		// If the caller didn't supply the gas limit in the message, then we set it to maximum possible => block gas limit
-		if arg.Gas == nil || uint64(*arg.Gas) <= 0 {
-			header, err := e.state.GetL2BlockHeaderByNumber(ctx, block.NumberU64(), dbTx)
-			if err != nil {
-				return RPCErrorResponse(types.DefaultErrorCode, "failed to get block header", err, true)
-			}
-
-			gas := types.ArgUint64(header.GasLimit)
-			arg.Gas = &gas
+		if respErr := setDefaultGas(ctx, e.state, arg, block, dbTx); respErr != nil {
+			return nil, respErr
 		}
 
 		defaultSenderAddress := common.HexToAddress(DefaultSenderAddress)
@@ -107,7 +130,7 @@ func (e *EthEndpoints) Call(arg *types.TxArgs, blockArg *types.BlockNumberOrHash
 			return RPCErrorResponse(types.DefaultErrorCode, "failed to convert arguments into an unsigned transaction", err, false)
 		}
 
-		result, err := e.state.ProcessUnsignedTransaction(ctx, tx, sender, blockToProcess, true, dbTx)
+		result, err := e.state.ProcessUnsignedTransaction(ctx, tx, sender, blockToProcess, true, stateOverride, dbTx)
 		if err != nil {
 			errMsg := fmt.Sprintf("failed to execute the unsigned transaction: %v", err.Error())
 			logError := !runtime.IsOutOfCounterError(err) && !errors.Is(err, runtime.ErrOutOfGas)
@@ -157,41 +180,76 @@ func (e *EthEndpoints) getCoinbaseFromSequencerNode() (interface{}, types.Error)
 	return coinbaseAddress.String(), nil
 }
 
+// CreateAccessList creates the access list of the accounts and storage slots
+// the transaction touches when executed on top of the given block, along with
+// the gas used by the transaction.
+// The transaction will not be added to the blockchain.
+func (e *EthEndpoints) CreateAccessList(arg *types.AccessListTxArgs, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
+	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		if arg == nil {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument 0", nil, false)
+		}
+
+		block, blockToProcess, respErr := getBlockToProcessByArg(ctx, e.state, e.etherman, blockArg, dbTx)
+		if respErr != nil {
+			return nil, respErr
+		}
+		if respErr := setDefaultGas(ctx, e.state, &arg.TxArgs, block, dbTx); respErr != nil {
+			return nil, respErr
+		}
+
+		var providedAccessList ethTypes.AccessList
+		if arg.AccessList != nil {
+			providedAccessList = *arg.AccessList
+		}
+
+		defaultSenderAddress := common.HexToAddress(DefaultSenderAddress)
+		sender, tx, err := arg.ToTransaction(ctx, e.state, e.cfg.MaxCumulativeGasUsed, block.Root(), defaultSenderAddress, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to convert arguments into an unsigned transaction", err, false)
+		}
+
+		result, err := e.state.CreateAccessList(ctx, tx, sender, blockToProcess, providedAccessList, dbTx)
+		if err != nil {
+			errMsg := fmt.Sprintf("failed to create access list: %v", err.Error())
+			logError := !runtime.IsOutOfCounterError(err) && !errors.Is(err, runtime.ErrOutOfGas)
+			return RPCErrorResponse(types.DefaultErrorCode, errMsg, nil, logError)
+		}
+
+		return types.NewAccessListResult(*result), nil
+	})
+}
+
 // EstimateGas generates and returns an estimate of how much gas is necessary to
 // allow the transaction to complete.
 // The transaction will not be added to the blockchain.
 // Note that the estimate may be significantly more than the amount of gas actually
 // used by the transaction, for a variety of reasons including EVM mechanics and
 // node performance.
-func (e *EthEndpoints) EstimateGas(arg *types.TxArgs, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
+// The optional state override replaces the balance, nonce, code or storage of
+// the given accounts during the estimation.
+func (e *EthEndpoints) EstimateGas(arg *types.TxArgs, blockArg *types.BlockNumberOrHash, stateOverrideArg types.StateOverride) (interface{}, types.Error) {
 	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
 		if arg == nil {
 			return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument 0", nil, false)
 		}
+		stateOverride := stateOverrideArg.ToStateOverride()
+		if err := stateOverride.Validate(); err != nil {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, err.Error(), nil, false)
+		}
 
-		block, respErr := e.getBlockByArg(ctx, blockArg, dbTx)
+		block, blockToProcess, respErr := getBlockToProcessByArg(ctx, e.state, e.etherman, blockArg, dbTx)
 		if respErr != nil {
 			return nil, respErr
 		}
 
-		var blockToProcess *uint64
-		if blockArg != nil {
-			blockNumArg := blockArg.Number()
-			if blockNumArg != nil && (*blockArg.Number() == types.LatestBlockNumber || *blockArg.Number() == types.PendingBlockNumber) {
-				blockToProcess = nil
-			} else {
-				n := block.NumberU64()
-				blockToProcess = &n
-			}
-		}
-
 		defaultSenderAddress := common.HexToAddress(DefaultSenderAddress)
 		sender, tx, err := arg.ToTransaction(ctx, e.state, e.cfg.MaxCumulativeGasUsed, block.Root(), defaultSenderAddress, dbTx)
 		if err != nil {
 			return RPCErrorResponse(types.DefaultErrorCode, "failed to convert arguments into an unsigned transaction", err, false)
 		}
 
-		gasEstimation, returnValue, err := e.state.EstimateGas(tx, sender, blockToProcess, dbTx)
+		gasEstimation, returnValue, err := e.state.EstimateGas(tx, sender, blockToProcess, stateOverride, dbTx)
 		if errors.Is(err, runtime.ErrExecutionReverted) {
 			data := make([]byte, len(returnValue))
 			copy(data, returnValue)
@@ -209,7 +267,7 @@ func (e *EthEndpoints) EstimateGas(arg *types.TxArgs, blockArg *types.BlockNumbe
 func (e *EthEndpoints) GasPrice() (interface{}, types.Error) {
 	ctx := context.Background()
 	if e.cfg.SequencerNodeURI != "" {
-		return e.getPriceFromSequencerNode()
+		return e.getPriceFromSequencerNode("eth_gasPrice")
 	}
 	gasPrices, err := e.pool.GetGasPrices(ctx)
 	if err != nil {
@@ -218,8 +276,147 @@ func (e *EthEndpoints) GasPrice() (interface{}, types.Error) {
 	return hex.EncodeUint64(gasPrices.L2GasPrice), nil
 }
 
-func (e *EthEndpoints) getPriceFromSequencerNode() (interface{}, types.Error) {
-	res, err := client.JSONRPCCall(e.cfg.SequencerNodeURI, "eth_gasPrice")
+// MaxPriorityFeePerGas returns a suggestion for the gas tip cap of EIP-1559 transactions
+// based on the tips of the transactions included in the last l2 blocks. Since the L2 has
+// no base fee, the suggestion is never lower than the current L2 gas price
+func (e *EthEndpoints) MaxPriorityFeePerGas() (interface{}, types.Error) {
+	if e.cfg.SequencerNodeURI != "" {
+		return e.getPriceFromSequencerNode("eth_maxPriorityFeePerGas")
+	}
+	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		lastBlockNumber, err := e.state.GetLastL2BlockNumber(ctx, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get the last block number from state", err, true)
+		}
+
+		var tips []*big.Int
+		for i, blockNumber := 0, lastBlockNumber; i < maxPriorityFeePerGasCheckBlocks && blockNumber > 0; i, blockNumber = i+1, blockNumber-1 {
+			txs, err := e.state.GetTxsByBlockNumber(ctx, blockNumber, dbTx)
+			if err != nil && !errors.Is(err, state.ErrNotFound) {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("failed to get txs of block %d", blockNumber), err, true)
+			}
+			tips = append(tips, gasprice.SampleTxsTips(txs, maxPriorityFeePerGasSamplesPerBlock, nil)...)
+		}
+
+		tip := gasprice.TipsPercentile(tips, maxPriorityFeePerGasPercentile)
+		if tip == nil {
+			tip = big.NewInt(0)
+		}
+
+		gasPrices, err := e.pool.GetGasPrices(ctx)
+		if err == nil && tip.Cmp(new(big.Int).SetUint64(gasPrices.L2GasPrice)) < 0 {
+			tip = new(big.Int).SetUint64(gasPrices.L2GasPrice)
+		}
+
+		return hex.EncodeBig(tip), nil
+	})
+}
+
+// FeeHistory returns the gas used ratio and the effective gas price paid at the requested reward
+// percentiles for the blockCount l2 blocks up to newestBlock. Since the L2 has no base fee, the
+// base fee per gas is always zero and the rewards are the whole effective gas price
+func (e *EthEndpoints) FeeHistory(blockCount types.ArgHexOrDecimalUint64, newestBlock types.BlockNumber, rewardPercentiles *[]float64) (interface{}, types.Error) {
+	var percentiles []float64
+	if rewardPercentiles != nil {
+		percentiles = *rewardPercentiles
+	}
+	if len(percentiles) > maxFeeHistoryRewardPercentiles {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, fmt.Sprintf("too many reward percentiles, max is %d", maxFeeHistoryRewardPercentiles), nil, false)
+	}
+	for i, p := range percentiles {
+		if p < 0 || p > 100 { //nolint:gomnd
+			return RPCErrorResponse(types.InvalidParamsErrorCode, fmt.Sprintf("invalid reward percentile: %f", p), nil, false)
+		}
+		if i > 0 && p <= percentiles[i-1] {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, fmt.Sprintf("invalid reward percentile: #%d:%f >= #%d:%f", i-1, percentiles[i-1], i, p), nil, false)
+		}
+	}
+
+	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		count := uint64(blockCount)
+		if count == 0 {
+			return types.FeeHistory{GasUsedRatio: []float64{}}, nil
+		}
+		if count > maxFeeHistoryBlockCount {
+			count = maxFeeHistoryBlockCount
+		}
+
+		newestBlockNumber, rpcErr := newestBlock.GetNumericBlockNumber(ctx, e.state, e.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		if count > newestBlockNumber+1 {
+			count = newestBlockNumber + 1
+		}
+		oldestBlockNumber := newestBlockNumber + 1 - count
+
+		feeHistory := types.FeeHistory{
+			OldestBlock: types.ArgUint64(oldestBlockNumber),
+			// the base fee of the next block is included too
+			BaseFee:      make([]types.ArgBig, count+1),
+			GasUsedRatio: make([]float64, 0, count),
+		}
+		if len(percentiles) > 0 {
+			feeHistory.Reward = make([][]types.ArgBig, 0, count)
+		}
+
+		for blockNumber := oldestBlockNumber; blockNumber <= newestBlockNumber; blockNumber++ {
+			header, err := e.state.GetL2BlockHeaderByNumber(ctx, blockNumber, dbTx)
+			if errors.Is(err, state.ErrNotFound) {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("block %d not found", blockNumber), nil, false)
+			} else if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("failed to get block %d from state", blockNumber), err, true)
+			}
+
+			gasUsedRatio := float64(0)
+			if header.GasLimit > 0 {
+				gasUsedRatio = float64(header.GasUsed) / float64(header.GasLimit)
+			}
+			feeHistory.GasUsedRatio = append(feeHistory.GasUsedRatio, gasUsedRatio)
+
+			if len(percentiles) == 0 {
+				continue
+			}
+			gasPrices, err := e.state.GetEffectiveGasPricesByL2BlockNumber(ctx, blockNumber, dbTx)
+			if err != nil && !errors.Is(err, state.ErrNotFound) {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("failed to get effective gas prices of block %d from state", blockNumber), err, true)
+			}
+			feeHistory.Reward = append(feeHistory.Reward, feeHistoryRewards(header.GasUsed, gasPrices, percentiles))
+		}
+
+		return feeHistory, nil
+	})
+}
+
+// feeHistoryRewards returns the effective gas price paid at each of the given percentiles,
+// weighting the txs of the block by the gas they used
+func feeHistoryRewards(blockGasUsed uint64, gasPrices []state.L2TxEffectiveGasPrice, percentiles []float64) []types.ArgBig {
+	rewards := make([]types.ArgBig, len(percentiles))
+	if len(gasPrices) == 0 {
+		return rewards
+	}
+
+	sorted := make([]state.L2TxEffectiveGasPrice, len(gasPrices))
+	copy(sorted, gasPrices)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].EffectiveGasPrice.Cmp(sorted[j].EffectiveGasPrice) < 0
+	})
+
+	txIndex := 0
+	sumGasUsed := sorted[0].GasUsed
+	for i, p := range percentiles {
+		thresholdGasUsed := uint64(float64(blockGasUsed) * p / 100) //nolint:gomnd
+		for sumGasUsed < thresholdGasUsed && txIndex < len(sorted)-1 {
+			txIndex++
+			sumGasUsed += sorted[txIndex].GasUsed
+		}
+		rewards[i] = types.ArgBig(*sorted[txIndex].EffectiveGasPrice)
+	}
+	return rewards
+}
+
+func (e *EthEndpoints) getPriceFromSequencerNode(method string) (interface{}, types.Error) {
+	res, err := client.JSONRPCCall(e.cfg.SequencerNodeURI, method)
 	if err != nil {
 		return RPCErrorResponse(types.DefaultErrorCode, "failed to get gas price from sequencer node", err, true)
 	}
@@ -256,9 +453,16 @@ func (e *EthEndpoints) GetBalance(address types.ArgAddress, blockArg *types.Bloc
 }
 
 func (e *EthEndpoints) getBlockByArg(ctx context.Context, blockArg *types.BlockNumberOrHash, dbTx pgx.Tx) (*state.L2Block, types.Error) {
+	return getBlockByArg(ctx, e.state, e.etherman, blockArg, false, dbTx)
+}
+
+// getBlockByArg loads the L2 block identified by the block number or hash argument,
+// the latest block if it's not provided. When the block doesn't exist an error is
+// returned, or a nil block if nilIfNotFound is set
+func getBlockByArg(ctx context.Context, st types.StateInterface, etherman types.EthermanInterface, blockArg *types.BlockNumberOrHash, nilIfNotFound bool, dbTx pgx.Tx) (*state.L2Block, types.Error) {
 	// If no block argument is provided, return the latest block
 	if blockArg == nil {
-		block, err := e.state.GetLastL2Block(ctx, dbTx)
+		block, err := st.GetLastL2Block(ctx, dbTx)
 		if err != nil {
 			return nil, types.NewRPCError(types.DefaultErrorCode, "failed to get the last block number from state")
 		}
@@ -267,8 +471,11 @@ func (e *EthEndpoints) getBlockByArg(ctx context.Context, blockArg *types.BlockN
 
 	// If we have a block hash, try to get the block by hash
 	if blockArg.IsHash() {
-		block, err := e.state.GetL2BlockByHash(ctx, blockArg.Hash().Hash(), dbTx)
+		block, err := st.GetL2BlockByHash(ctx, blockArg.Hash().Hash(), dbTx)
 		if errors.Is(err, state.ErrNotFound) {
+			if nilIfNotFound {
+				return nil, nil
+			}
 			return nil, types.NewRPCError(types.DefaultErrorCode, "header for hash not found")
 		} else if err != nil {
 			return nil, types.NewRPCError(types.DefaultErrorCode, fmt.Sprintf("failed to get block by hash %v", blockArg.Hash().Hash()))
@@ -277,12 +484,15 @@ func (e *EthEndpoints) getBlockByArg(ctx context.Context, blockArg *types.BlockN
 	}
 
 	// Otherwise, try to get the block by number
-	blockNum, rpcErr := blockArg.Number().GetNumericBlockNumber(ctx, e.state, e.etherman, dbTx)
+	blockNum, rpcErr := blockArg.Number().GetNumericBlockNumber(ctx, st, etherman, dbTx)
 	if rpcErr != nil {
 		return nil, rpcErr
 	}
-	block, err := e.state.GetL2BlockByNumber(context.Background(), blockNum, dbTx)
+	block, err := st.GetL2BlockByNumber(context.Background(), blockNum, dbTx)
 	if errors.Is(err, state.ErrNotFound) || block == nil {
+		if nilIfNotFound {
+			return nil, nil
+		}
 		return nil, types.NewRPCError(types.DefaultErrorCode, "header not found")
 	} else if err != nil {
 		return nil, types.NewRPCError(types.DefaultErrorCode, fmt.Sprintf("failed to get block by number %v", blockNum))
@@ -291,6 +501,78 @@ func (e *EthEndpoints) getBlockByArg(ctx context.Context, blockArg *types.BlockN
 	return block, nil
 }
 
+// getBlockToProcessByArg loads the L2 block identified by the block number or
+// hash argument, along with the number of the block to process a tx on top of,
+// which is nil for the latest and pending blocks so the tx is processed on top
+// of the latest state
+func getBlockToProcessByArg(ctx context.Context, st types.StateInterface, etherman types.EthermanInterface, blockArg *types.BlockNumberOrHash, dbTx pgx.Tx) (*state.L2Block, *uint64, types.Error) {
+	block, rpcErr := getBlockByArg(ctx, st, etherman, blockArg, false, dbTx)
+	if rpcErr != nil {
+		return nil, nil, rpcErr
+	}
+
+	if blockArg == nil {
+		return block, nil, nil
+	}
+	blockNumArg := blockArg.Number()
+	if blockNumArg != nil && (*blockNumArg == types.LatestBlockNumber || *blockNumArg == types.PendingBlockNumber) {
+		return block, nil, nil
+	}
+	blockNumber := block.NumberU64()
+	return block, &blockNumber, nil
+}
+
+// setDefaultGas sets the gas limit of the tx arguments to the gas limit of the
+// block when the caller didn't supply it, which is the maximum possible
+func setDefaultGas(ctx context.Context, st types.StateInterface, arg *types.TxArgs, block *state.L2Block, dbTx pgx.Tx) types.Error {
+	if arg.Gas != nil && uint64(*arg.Gas) > 0 {
+		return nil
+	}
+
+	header, err := st.GetL2BlockHeaderByNumber(ctx, block.NumberU64(), dbTx)
+	if err != nil {
+		_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, "failed to get block header", err, true)
+		return rpcErr
+	}
+
+	gas := types.ArgUint64(header.GasLimit)
+	arg.Gas = &gas
+	return nil
+}
+
+// getBlockAndReceiptsByArg loads the L2 block identified by the block number or hash
+// argument together with the receipts of its txs, in the same order as the txs.
+// A nil block is returned when the block doesn't exist
+func getBlockAndReceiptsByArg(ctx context.Context, st types.StateInterface, etherman types.EthermanInterface, blockArg types.BlockNumberOrHash, dbTx pgx.Tx) (*state.L2Block, []*ethTypes.Receipt, types.Error) {
+	block, rpcErr := getBlockByArg(ctx, st, etherman, &blockArg, true, dbTx)
+	if rpcErr != nil || block == nil {
+		return nil, nil, rpcErr
+	}
+
+	stateReceipts, err := st.GetReceiptsByL2BlockNumber(ctx, block.NumberU64(), dbTx)
+	if err != nil && !errors.Is(err, state.ErrNotFound) {
+		_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load receipts for block %v", block.NumberU64()), err, true)
+		return nil, nil, rpcErr
+	}
+	receiptsByTxHash := make(map[common.Hash]*ethTypes.Receipt, len(stateReceipts))
+	for _, receipt := range stateReceipts {
+		receiptsByTxHash[receipt.TxHash] = receipt
+	}
+
+	txs := block.Transactions()
+	receipts := make([]*ethTypes.Receipt, 0, len(txs))
+	for _, tx := range txs {
+		receipt, found := receiptsByTxHash[tx.Hash()]
+		if !found {
+			_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load receipt for tx %v", tx.Hash().String()), state.ErrNotFound, true)
+			return nil, nil, rpcErr
+		}
+		receipts = append(receipts, receipt)
+	}
+
+	return block, receipts, nil
+}
+
 // GetBlockByHash returns information about a block by hash
 func (e *EthEndpoints) GetBlockByHash(hash types.ArgHash, fullTx bool) (interface{}, types.Error) {
 	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
@@ -322,6 +604,17 @@ func (e *EthEndpoints) GetBlockByHash(hash types.ArgHash, fullTx bool) (interfac
 
 // GetBlockByNumber returns information about a block by block number
 func (e *EthEndpoints) GetBlockByNumber(number types.BlockNumber, fullTx bool) (interface{}, types.Error) {
+	if number < 0 {
+		return e.getBlockByNumber(number, fullTx)
+	}
+	return e.cache.cached("eth_getBlockByNumber", fmt.Sprintf("%d/%t", number, fullTx), func() (interface{}, uint64, types.Error) {
+		block, rpcErr := e.getBlockByNumber(number, fullTx)
+		return block, uint64(number), rpcErr
+	})
+}
+
+// getBlockByNumber loads the block with the given number, without using the cache
+func (e *EthEndpoints) getBlockByNumber(number types.BlockNumber, fullTx bool) (interface{}, types.Error) {
 	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
 		if number == types.PendingBlockNumber {
 			lastBlock, err := e.state.GetLastL2Block(ctx, dbTx)
@@ -373,6 +666,31 @@ func (e *EthEndpoints) GetBlockByNumber(number types.BlockNumber, fullTx bool) (
 	})
 }
 
+// GetBlockReceipts returns the receipts of all the txs of the block identified
+// by the given block number or hash
+func (e *EthEndpoints) GetBlockReceipts(blockArg types.BlockNumberOrHash) (interface{}, types.Error) {
+	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		block, receipts, rpcErr := getBlockAndReceiptsByArg(ctx, e.state, e.etherman, blockArg, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		} else if block == nil {
+			return nil, nil
+		}
+
+		txs := block.Transactions()
+		rpcReceipts := make([]types.Receipt, 0, len(receipts))
+		for i, r := range receipts {
+			receipt, err := types.NewReceipt(*txs[i], r)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, "failed to build the receipt response", err, true)
+			}
+			rpcReceipts = append(rpcReceipts, receipt)
+		}
+
+		return rpcReceipts, nil
+	})
+}
+
 // GetCode returns account code at given block number
 func (e *EthEndpoints) GetCode(address types.ArgAddress, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
 	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
@@ -516,6 +834,37 @@ func (e *EthEndpoints) internalGetLogs(ctx context.Context, dbTx pgx.Tx, filter
 	return result, nil
 }
 
+// GetProof returns the values of the balance, nonce, code hash and the given
+// storage keys of an account, along with the sparse merkle tree proofs of their
+// leaves at the state root of the given block
+func (e *EthEndpoints) GetProof(address types.ArgAddress, storageKeys []types.ArgHash, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
+	keys := make([]common.Hash, 0, len(storageKeys))
+	positions := make([]*big.Int, 0, len(storageKeys))
+	for _, storageKey := range storageKeys {
+		keys = append(keys, storageKey.Hash())
+		positions = append(positions, storageKey.Hash().Big())
+	}
+
+	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		block, rpcErr := e.getBlockByArg(ctx, blockArg, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		proof, err := e.state.GetAccountProof(ctx, address.Address(), positions, block.Root())
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get account proof from state", err, true)
+		}
+
+		res, err := types.NewAccountProof(address.Address(), keys, proof)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to build the account proof response", err, true)
+		}
+
+		return res, nil
+	})
+}
+
 // GetStorageAt gets the value stored for an specific address and position
 func (e *EthEndpoints) GetStorageAt(address types.ArgAddress, storageKeyStr string, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
 	storageKey := types.ArgHash{}
@@ -789,6 +1138,18 @@ func (e *EthEndpoints) getBlockTransactionCountByNumberFromSequencerNode(number
 
 // GetTransactionReceipt returns a transaction receipt by his hash
 func (e *EthEndpoints) GetTransactionReceipt(hash types.ArgHash) (interface{}, types.Error) {
+	return e.cache.cached("eth_getTransactionReceipt", hash.Hash().String(), func() (interface{}, uint64, types.Error) {
+		v, rpcErr := e.getTransactionReceipt(hash)
+		receipt, ok := v.(types.Receipt)
+		if !ok {
+			return v, 0, rpcErr
+		}
+		return receipt, uint64(receipt.BlockNumber), rpcErr
+	})
+}
+
+// getTransactionReceipt loads the receipt of the tx with the given hash, without using the cache
+func (e *EthEndpoints) getTransactionReceipt(hash types.ArgHash) (interface{}, types.Error) {
 	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
 		tx, err := e.state.GetTransactionByHash(ctx, hash.Hash(), dbTx)
 		if errors.Is(err, state.ErrNotFound) {
@@ -841,14 +1202,33 @@ func (e *EthEndpoints) NewFilter(filter LogFilter) (interface{}, types.Error) {
 
 // internal
 func (e *EthEndpoints) newFilter(ctx context.Context, wsConn *concurrentWsConn, filter LogFilter, dbTx pgx.Tx) (interface{}, types.Error) {
+	if rpcErr := e.checkLogFilterBlockRange(ctx, filter); rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	return e.createLogFilter(wsConn, filter, false)
+}
+
+// checkLogFilterBlockRange checks the block range of the filter doesn't
+// exceed the max logs block range
+func (e *EthEndpoints) checkLogFilterBlockRange(ctx context.Context, filter LogFilter) types.Error {
 	if filter.ShouldFilterByBlockRange() {
 		_, _, rpcErr := filter.GetNumericBlockNumbers(ctx, e.cfg, e.state, e.etherman, nil)
 		if rpcErr != nil {
-			return nil, rpcErr
+			return rpcErr
 		}
 	}
+	return nil
+}
 
-	id, err := e.storage.NewLogFilter(wsConn, filter)
+// createLogFilter persists the log filter to the storage, the resumed filters
+// hold their notifications until the missed blocks are replayed
+func (e *EthEndpoints) createLogFilter(wsConn *concurrentWsConn, filter LogFilter, resumed bool) (interface{}, types.Error) {
+	newLogFilter := e.storage.NewLogFilter
+	if resumed {
+		newLogFilter = e.storage.NewResumedLogFilter
+	}
+	id, err := newLogFilter(wsConn, filter)
 	if errors.Is(err, ErrFilterInvalidPayload) {
 		return RPCErrorResponse(types.InvalidParamsErrorCode, err.Error(), nil, false)
 	} else if err != nil {
@@ -881,25 +1261,166 @@ func (e *EthEndpoints) newPendingTransactionFilter(wsConn *concurrentWsConn) (in
 // - for Non-Sequencer nodes it relays the Tx to the Sequencer node
 func (e *EthEndpoints) SendRawTransaction(httpRequest *http.Request, input string) (interface{}, types.Error) {
 	if e.cfg.SequencerNodeURI != "" {
-		return e.relayTxToSequencerNode(input)
+		return e.relayTxToSequencerNode("eth_sendRawTransaction", input)
 	} else {
-		ip := ""
-		ips := httpRequest.Header.Get("X-Forwarded-For")
+		return e.tryToAddTxToPool(input, getTxSenderIP(httpRequest), nil)
+	}
+}
+
+// SendRawTransactionConditional sends a raw transaction like eth_sendRawTransaction
+// that the sequencer only includes in a block meeting the given conditions: the
+// storage slots of the known accounts must hold the expected values and the
+// block number and timestamp must be in the given ranges. The transactions whose
+// conditions can't be met anymore by the next blocks are rejected right away.
+func (e *EthEndpoints) SendRawTransactionConditional(httpRequest *http.Request, input string, conditions types.TransactionConditions) (interface{}, types.Error) {
+	poolConditions := conditions.ToPoolConditions()
+	if rpcErr := e.checkTxConditions(poolConditions); rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	if e.cfg.SequencerNodeURI != "" {
+		return e.relayTxToSequencerNode("eth_sendRawTransactionConditional", input, conditions)
+	} else {
+		return e.tryToAddTxToPool(input, getTxSenderIP(httpRequest), poolConditions)
+	}
+}
+
+// checkTxConditions validates the conditions of a conditional tx and checks
+// they can still be met by the blocks after the latest one
+func (e *EthEndpoints) checkTxConditions(conditions *pool.TransactionConditions) types.Error {
+	if conditions.KnownSlotsCount() > maxConditionalTxKnownSlots {
+		_, rpcErr := RPCErrorResponse(types.InvalidParamsErrorCode, fmt.Sprintf("the conditions can't check more than %d storage slots", maxConditionalTxKnownSlots), nil, false)
+		return rpcErr
+	}
+	if conditions.BlockNumberMin != nil && conditions.BlockNumberMax != nil && *conditions.BlockNumberMin > *conditions.BlockNumberMax {
+		_, rpcErr := RPCErrorResponse(types.InvalidParamsErrorCode, "invalid block number range", nil, false)
+		return rpcErr
+	}
+	if conditions.TimestampMin != nil && conditions.TimestampMax != nil && *conditions.TimestampMin > *conditions.TimestampMax {
+		_, rpcErr := RPCErrorResponse(types.InvalidParamsErrorCode, "invalid timestamp range", nil, false)
+		return rpcErr
+	}
 
-		// TODO: this is temporary patch remove this log
-		realIp := httpRequest.Header.Get("X-Real-IP")
-		log.Infof("X-Forwarded-For: %s, X-Real-IP: %s", ips, realIp)
+	_, rpcErr := e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		lastBlock, err := e.state.GetLastL2Block(ctx, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get the last block number from state", err, true)
+		}
+
+		if conditions.BlockNumberMax != nil && *conditions.BlockNumberMax <= lastBlock.NumberU64() {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("%v: the max block number %d is not greater than the latest block number %d", pool.ErrTxConditionsNotMet, *conditions.BlockNumberMax, lastBlock.NumberU64()), nil, false)
+		}
+		if conditions.TimestampMax != nil && *conditions.TimestampMax < lastBlock.Time() {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("%v: the max timestamp %d is lower than the latest block timestamp %d", pool.ErrTxConditionsNotMet, *conditions.TimestampMax, lastBlock.Time()), nil, false)
+		}
 
-		if ips != "" {
-			ip = strings.Split(ips, ",")[0]
+		err = conditions.CheckKnownAccounts(func(address common.Address, slot common.Hash) (common.Hash, error) {
+			value, err := e.state.GetStorageAt(ctx, address, slot.Big(), lastBlock.Root())
+			if errors.Is(err, state.ErrNotFound) {
+				return common.Hash{}, nil
+			} else if err != nil {
+				return common.Hash{}, err
+			}
+			return common.BigToHash(value), nil
+		})
+		if errors.Is(err, pool.ErrTxConditionsNotMet) {
+			return RPCErrorResponse(types.DefaultErrorCode, err.Error(), nil, false)
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get storage value from state", err, true)
 		}
 
-		return e.tryToAddTxToPool(input, ip)
+		return nil, nil
+	})
+	return rpcErr
+}
+
+// getTxSenderIP returns the IP of the client sending a tx
+func getTxSenderIP(httpRequest *http.Request) string {
+	ip := ""
+	ips := httpRequest.Header.Get("X-Forwarded-For")
+
+	// TODO: this is temporary patch remove this log
+	realIp := httpRequest.Header.Get("X-Real-IP")
+	log.Infof("X-Forwarded-For: %s, X-Real-IP: %s", ips, realIp)
+
+	if ips != "" {
+		ip = strings.Split(ips, ",")[0]
 	}
+
+	return ip
 }
 
-func (e *EthEndpoints) relayTxToSequencerNode(input string) (interface{}, types.Error) {
-	res, err := client.JSONRPCCall(e.cfg.SequencerNodeURI, "eth_sendRawTransaction", input)
+// SendRawTransactionSync sends a raw transaction like eth_sendRawTransaction
+// and waits until it's included in a stored l2 block to return its receipt.
+// The optional timeout in milliseconds can only shorten the configured
+// SendRawTransactionSyncTimeout, when it elapses the receipt is returned if
+// the transaction was already stored, otherwise an error with its pool status.
+func (e *EthEndpoints) SendRawTransactionSync(httpRequest *http.Request, input string, timeout *types.ArgUint64) (interface{}, types.Error) {
+	tx, err := hexToTx(input)
+	if err != nil {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, "invalid tx input", err, false)
+	}
+
+	waitTimeout := e.cfg.SendRawTransactionSyncTimeout.Duration
+	if timeout != nil {
+		if *timeout == 0 {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, "timeout must be greater than zero", nil, false)
+		}
+		if requestedTimeout := time.Duration(*timeout) * time.Millisecond; requestedTimeout < waitTimeout {
+			waitTimeout = requestedTimeout
+		}
+	}
+
+	// the waiter is registered before the tx is sent to not miss the block
+	// event if the tx is included right away
+	included, cancel := e.txWaiters.wait(tx.Hash())
+	defer cancel()
+
+	if _, rpcErr := e.SendRawTransaction(httpRequest, input); rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	// the wait also ends when the request is cancelled, like when the timeout
+	// of its batch request is reached
+	timer := time.NewTimer(waitTimeout)
+	defer timer.Stop()
+	select {
+	case <-included:
+	case <-timer.C:
+	case <-httpRequest.Context().Done():
+	}
+
+	receipt, rpcErr := e.getTransactionReceipt(types.ArgHash(tx.Hash()))
+	if rpcErr != nil || receipt != nil {
+		return receipt, rpcErr
+	}
+
+	return e.sentTxNotIncludedError(tx.Hash(), waitTimeout)
+}
+
+// sentTxNotIncludedError builds the error returned by eth_sendRawTransactionSync
+// when the tx wasn't included in a block in time, naming its pool status
+func (e *EthEndpoints) sentTxNotIncludedError(txHash common.Hash, waitTimeout time.Duration) (interface{}, types.Error) {
+	poolTx, err := e.pool.GetTxByHash(context.Background(), txHash)
+	if errors.Is(err, pool.ErrNotFound) {
+		return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("transaction %v not included in a block after %v", txHash.String(), waitTimeout), nil, false)
+	} else if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get tx from pool", err, true)
+	}
+
+	if poolTx.Status == pool.TxStatusInvalid || poolTx.Status == pool.TxStatusFailed {
+		reason := "unknown reason"
+		if poolTx.FailedReason != nil {
+			reason = *poolTx.FailedReason
+		}
+		return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("transaction %v is %v in the pool: %v", txHash.String(), poolTx.Status, reason), nil, false)
+	}
+
+	return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("transaction %v not included in a block after %v, pool status: %v", txHash.String(), waitTimeout, poolTx.Status), nil, false)
+}
+
+func (e *EthEndpoints) relayTxToSequencerNode(method string, params ...interface{}) (interface{}, types.Error) {
+	res, err := client.JSONRPCCall(e.cfg.SequencerNodeURI, method, params...)
 	if err != nil {
 		return RPCErrorResponse(types.DefaultErrorCode, "failed to relay tx to the sequencer node", err, true)
 	}
@@ -913,13 +1434,18 @@ func (e *EthEndpoints) relayTxToSequencerNode(input string) (interface{}, types.
 	return txHash, nil
 }
 
-func (e *EthEndpoints) tryToAddTxToPool(input, ip string) (interface{}, types.Error) {
+func (e *EthEndpoints) tryToAddTxToPool(input, ip string, conditions *pool.TransactionConditions) (interface{}, types.Error) {
 	tx, err := hexToTx(input)
 	if err != nil {
 		return RPCErrorResponse(types.InvalidParamsErrorCode, "invalid tx input", err, false)
 	}
 	log.Infof("adding TX to the pool: %v", tx.Hash().Hex())
-	if err := e.pool.AddTx(context.Background(), *tx, ip); err != nil {
+	if conditions != nil {
+		err = e.pool.AddTxWithConditions(context.Background(), *tx, ip, conditions)
+	} else {
+		err = e.pool.AddTx(context.Background(), *tx, ip)
+	}
+	if err != nil {
 		// it's not needed to log the error here, because we check and log if needed
 		// for each specific case during the "pool.AddTx" internal steps
 		return RPCErrorResponse(types.DefaultErrorCode, err.Error(), nil, false)
@@ -1029,20 +1555,61 @@ func (e *EthEndpoints) updateFilterLastPoll(filterID string) types.Error {
 // The node will return a subscription id.
 // For each event that matches the subscription a notification with relevant
 // data is sent together with the subscription id.
+//
+// The newHeads and logs subscriptions accept a resumeFromBlock to resume the
+// subscription after a disconnection, the blocks from resumeFromBlock to the
+// latest block are replayed from the state before the new blocks are sent.
+// The fromBlock of the logs filter doesn't resume the subscription.
 func (e *EthEndpoints) Subscribe(wsConn *concurrentWsConn, name string, logFilter *LogFilter) (interface{}, types.Error) {
 	switch name {
 	case "newHeads":
-		return e.newBlockFilter(wsConn)
+		if logFilter == nil || logFilter.ResumeFromBlock == nil {
+			return e.newBlockFilter(wsConn)
+		}
+		return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+			resumeFromBlock, rpcErr := e.getResumeFromBlock(ctx, logFilter.ResumeFromBlock, dbTx)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+			if resumeFromBlock == nil {
+				return e.newBlockFilter(wsConn)
+			}
+			id, err := e.storage.NewResumedBlockFilter(wsConn)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, "failed to create new block filter", err, true)
+			}
+			return e.resumeSubscription(id, *resumeFromBlock)
+		})
 	case "logs":
 		return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
 			var lf LogFilter
 			if logFilter != nil {
 				lf = *logFilter
 			}
-			return e.newFilter(ctx, wsConn, lf, dbTx)
+			if rpcErr := e.checkLogFilterBlockRange(ctx, lf); rpcErr != nil {
+				return nil, rpcErr
+			}
+			resumeFromBlock, rpcErr := e.getResumeFromBlock(ctx, lf.ResumeFromBlock, dbTx)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+			if resumeFromBlock == nil {
+				return e.createLogFilter(wsConn, lf, false)
+			}
+			id, rpcErr := e.createLogFilter(wsConn, lf, true)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+			return e.resumeSubscription(id.(string), *resumeFromBlock)
 		})
 	case "pendingTransactions", "newPendingTransactions":
 		return e.newPendingTransactionFilter(wsConn)
+	case "newTrustedBatches":
+		return e.newBatchFilter(wsConn, state.BatchEventClosed)
+	case "newVirtualBatches":
+		return e.newBatchFilter(wsConn, state.BatchEventVirtualized)
+	case "newVerifiedBatches":
+		return e.newBatchFilter(wsConn, state.BatchEventVerified)
 	case "syncing":
 		return nil, types.NewRPCError(types.DefaultErrorCode, "not supported yet")
 	default:
@@ -1050,6 +1617,156 @@ func (e *EthEndpoints) Subscribe(wsConn *concurrentWsConn, name string, logFilte
 	}
 }
 
+// newBatchFilter creates a filter notified of the batch events of the given type
+func (e *EthEndpoints) newBatchFilter(wsConn *concurrentWsConn, eventType state.BatchEventType) (interface{}, types.Error) {
+	id, err := e.storage.NewBatchFilter(wsConn, eventType)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to create new batch filter", err, true)
+	}
+
+	return id, nil
+}
+
+// getResumeFromBlock returns the first block to replay for a subscription
+// resumed from the provided block, nil when there are no blocks to replay
+func (e *EthEndpoints) getResumeFromBlock(ctx context.Context, fromBlock *types.BlockNumber, dbTx pgx.Tx) (*uint64, types.Error) {
+	if fromBlock == nil {
+		return nil, nil
+	}
+
+	resumeFromBlock, rpcErr := fromBlock.GetNumericBlockNumber(ctx, e.state, e.etherman, dbTx)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	lastBlockNumber, err := e.state.GetLastL2BlockNumber(ctx, dbTx)
+	if err != nil {
+		_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, "failed to get the last block number from state", err, true)
+		return nil, rpcErr
+	}
+
+	if resumeFromBlock > lastBlockNumber {
+		return nil, nil
+	}
+
+	maxResumeBlockRange := e.cfg.WebSockets.MaxResumeBlockRange
+	if maxResumeBlockRange > 0 && lastBlockNumber-resumeFromBlock >= maxResumeBlockRange {
+		errMsg := fmt.Sprintf("subscriptions can only be resumed from the last %d blocks", maxResumeBlockRange)
+		_, rpcErr := RPCErrorResponse(types.InvalidParamsErrorCode, errMsg, nil, false)
+		return nil, rpcErr
+	}
+
+	return &resumeFromBlock, nil
+}
+
+// resumeSubscription replays the blocks the client missed in background, so
+// the subscription id is returned right away, and then lets the subscription
+// send the new blocks
+func (e *EthEndpoints) resumeSubscription(filterID string, resumeFromBlock uint64) (interface{}, types.Error) {
+	filter, err := e.storage.GetFilter(filterID)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get subscription filter", err, true)
+	}
+
+	go e.replaySubscription(filter, resumeFromBlock)
+	return filterID, nil
+}
+
+// replaySubscription enqueues the data of the blocks from the provided block to
+// the latest block, the client is disconnected when the blocks can't be
+// replayed, so it doesn't miss them silently. The replayed data waits for room
+// in the subscription queue instead of applying its overflow policy
+func (e *EthEndpoints) replaySubscription(filter *Filter, fromBlock uint64) {
+	ctx := context.Background()
+	start := time.Now()
+
+	toBlock, err := e.state.GetLastL2BlockNumber(ctx, nil)
+	if err == nil {
+		switch filter.Type {
+		case FilterTypeBlock:
+			err = e.replayNewHeads(ctx, filter, fromBlock, toBlock)
+		case FilterTypeLog:
+			err = e.replayLogs(ctx, filter, fromBlock, toBlock)
+		}
+	}
+	if err != nil {
+		log.Errorf("failed to resume subscription %v from block %v: %v", filter.ID, fromBlock, err)
+		filter.WsConn.disconnect(errSubscriptionResumeFailed)
+		return
+	}
+
+	filter.finishResume(toBlock)
+	log.Debugf("[replaySubscription] subscription %v resumed from block %v to block %v in %v", filter.ID, fromBlock, toBlock, time.Since(start))
+}
+
+// replayNewHeads enqueues the blocks in the provided range to the filter
+func (e *EthEndpoints) replayNewHeads(ctx context.Context, filter *Filter, fromBlock, toBlock uint64) error {
+	for blockNumber := fromBlock; blockNumber <= toBlock; blockNumber++ {
+		l2Block, err := e.state.GetL2BlockByNumber(ctx, blockNumber, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get block %v: %w", blockNumber, err)
+		}
+		b, err := types.NewBlock(l2Block, nil, false, false)
+		if err != nil {
+			return fmt.Errorf("failed to build block %v response: %w", blockNumber, err)
+		}
+		data, err := json.Marshal(b)
+		if err != nil {
+			return fmt.Errorf("failed to marshal block %v response: %w", blockNumber, err)
+		}
+		enqueued, err := filter.enqueueReplayedSubscriptionData(data)
+		if err != nil {
+			return fmt.Errorf("failed to build block %v notification: %w", blockNumber, err)
+		} else if !enqueued {
+			return nil
+		}
+	}
+	return nil
+}
+
+// replayLogs enqueues the logs matching the filter in the provided range
+func (e *EthEndpoints) replayLogs(ctx context.Context, filter *Filter, fromBlock, toBlock uint64) error {
+	logFilter := filter.Parameters.(LogFilter)
+	if logFilter.ToBlock != nil {
+		filterToBlock, rpcErr := logFilter.ToBlock.GetNumericBlockNumber(ctx, e.state, e.etherman, nil)
+		if rpcErr != nil {
+			return rpcErr
+		}
+		if filterToBlock < toBlock {
+			toBlock = filterToBlock
+		}
+	}
+	if fromBlock > toBlock {
+		return nil
+	}
+
+	cursor := LogsCursor{BlockNumber: fromBlock}
+	for {
+		logs, err := e.state.GetLogsPage(ctx, cursor.BlockNumber, cursor.LogIndex, toBlock, logFilter.Addresses, logFilter.Topics, resumeLogsPageSize, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get logs: %w", err)
+		}
+		for _, l := range logs {
+			data, err := json.Marshal(types.NewLog(*l))
+			if err != nil {
+				return fmt.Errorf("failed to marshal log response: %w", err)
+			}
+			enqueued, err := filter.enqueueReplayedSubscriptionData(data)
+			if err != nil {
+				return fmt.Errorf("failed to build log notification: %w", err)
+			} else if !enqueued {
+				return nil
+			}
+		}
+
+		if len(logs) < resumeLogsPageSize {
+			return nil
+		}
+		lastLog := logs[len(logs)-1]
+		cursor = LogsCursor{BlockNumber: lastLog.BlockNumber, LogIndex: uint64(lastLog.Index) + 1}
+	}
+}
+
 // Unsubscribe uninstalls the filter based on the provided filterID
 func (e *EthEndpoints) Unsubscribe(wsConn *concurrentWsConn, filterID string) (interface{}, types.Error) {
 	return e.UninstallFilter(filterID)
@@ -1065,6 +1782,12 @@ func (e *EthEndpoints) uninstallFilterByWSConn(wsConn *concurrentWsConn) error {
 func (e *EthEndpoints) onNewL2Block(event state.NewL2BlockEvent) {
 	log.Debugf("[onNewL2Block] new l2 block event detected for block %v", event.Block.NumberU64())
 	start := time.Now()
+
+	txHashes := make([]common.Hash, 0, len(event.Block.Transactions()))
+	for _, tx := range event.Block.Transactions() {
+		txHashes = append(txHashes, tx.Hash())
+	}
+	e.txWaiters.notify(txHashes)
 	wg := sync.WaitGroup{}
 
 	wg.Add(1)
@@ -1100,7 +1823,7 @@ func (e *EthEndpoints) notifyNewHeads(wg *sync.WaitGroup, event state.NewL2Block
 		for _, filter := range filters {
 			f := filter
 			start := time.Now()
-			f.EnqueueSubscriptionDataToBeSent(data)
+			f.EnqueueBlockSubscriptionDataToBeSent(event.Block.NumberU64(), data)
 			log.Debugf("[notifyNewHeads] took %v to enqueue new l2 block messages", time.Since(start))
 		}
 	})
@@ -1131,13 +1854,16 @@ func (e *EthEndpoints) notifyNewLogs(wg *sync.WaitGroup, event state.NewL2BlockE
 			log.Debugf("[notifyNewLogs] took %v to filter logs", time.Since(start))
 
 			start = time.Now()
+			data := make([][]byte, 0, len(logs))
 			for _, l := range logs {
-				data, err := json.Marshal(l)
+				d, err := json.Marshal(l)
 				if err != nil {
 					log.Errorf("failed to marshal ethLog response to subscription: %v", err)
+					continue
 				}
-				f.EnqueueSubscriptionDataToBeSent(data)
+				data = append(data, d)
 			}
+			f.EnqueueBlockSubscriptionDataToBeSent(event.Block.NumberU64(), data...)
 			log.Debugf("[notifyNewLogs] took %v to enqueue log messages", time.Since(start))
 		}
 	})
@@ -1145,6 +1871,33 @@ func (e *EthEndpoints) notifyNewLogs(wg *sync.WaitGroup, event state.NewL2BlockE
 	log.Debugf("[notifyNewLogs] new l2 block event for block %v took %v to send all the messages for log filters", event.Block.NumberU64(), time.Since(start))
 }
 
+// onNewBatch is triggered when the state triggers the event for a batch
+// reaching a new stage of its lifecycle
+func (e *EthEndpoints) onNewBatch(event state.NewBatchEvent) {
+	log.Debugf("[onNewBatch] new %v batch event detected for batch %v", event.Type, event.BatchNumber)
+	start := time.Now()
+
+	data, err := json.Marshal(types.NewBatchEvent(event))
+	if err != nil {
+		log.Errorf("failed to marshal batch event response to subscription: %v", err)
+		return
+	}
+
+	filters := e.storage.GetAllBatchFiltersWithWSConn()
+
+	const maxWorkers = 32
+	parallelize(maxWorkers, filters, func(worker int, filters []*Filter) {
+		for _, filter := range filters {
+			if filter.Parameters.(state.BatchEventType) != event.Type {
+				continue
+			}
+			filter.EnqueueSubscriptionDataToBeSent(data)
+		}
+	})
+
+	log.Debugf("[onNewBatch] new %v batch event for batch %v took %v to send all the messages for batch filters", event.Type, event.BatchNumber, time.Since(start))
+}
+
 // shouldSkipLogFilter checks if the log filter can be skipped while notifying new logs.
 // it checks the log filter information against the block in the event to decide if the
 // information in the event is required by the filter or can be ignored to save resources.
@@ -6,14 +6,18 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"net/http"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	configTypes "github.com/0xPolygonHermez/zkevm-node/config/types"
 	"github.com/0xPolygonHermez/zkevm-node/encoding"
 	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/mocks"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
 	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
@@ -171,7 +175,7 @@ func TestCall(t *testing.T) {
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumOneUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumOneUint64, true, state.StateOverride(nil), m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
@@ -215,7 +219,7 @@ func TestCall(t *testing.T) {
 				})
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumOneUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumOneUint64, true, state.StateOverride(nil), m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
@@ -257,7 +261,7 @@ func TestCall(t *testing.T) {
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, nilUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, nilUint64, true, state.StateOverride(nil), m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
@@ -299,7 +303,7 @@ func TestCall(t *testing.T) {
 				})
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumTenUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumTenUint64, true, state.StateOverride(nil), m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
@@ -339,7 +343,7 @@ func TestCall(t *testing.T) {
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumTenUint64, m.DbTx).Return(block, nil).Once()
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumTenUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumTenUint64, true, state.StateOverride(nil), m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
@@ -378,7 +382,7 @@ func TestCall(t *testing.T) {
 				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumOne, Root: blockRoot}))
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, common.HexToAddress(DefaultSenderAddress), nilUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, common.HexToAddress(DefaultSenderAddress), nilUint64, true, state.StateOverride(nil), m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
@@ -417,7 +421,7 @@ func TestCall(t *testing.T) {
 				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumOne, Root: blockRoot}))
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, common.HexToAddress(DefaultSenderAddress), nilUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, common.HexToAddress(DefaultSenderAddress), nilUint64, true, state.StateOverride(nil), m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
@@ -481,7 +485,7 @@ func TestCall(t *testing.T) {
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, nilUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, nilUint64, true, state.StateOverride(nil), m.DbTx).
 					Return(&runtime.ExecutionResult{Err: errors.New("failed to process unsigned transaction")}, nil).
 					Once()
 			},
@@ -523,11 +527,84 @@ func TestCall(t *testing.T) {
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, nilUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, nilUint64, true, state.StateOverride(nil), m.DbTx).
 					Return(&runtime.ExecutionResult{Err: runtime.ErrExecutionReverted}, nil).
 					Once()
 			},
 		},
+		{
+			name: "Transaction with state override",
+			params: []interface{}{
+				types.TxArgs{
+					From:     state.HexToAddressPtr("0x1"),
+					To:       state.HexToAddressPtr("0x2"),
+					Gas:      types.ArgUint64Ptr(24000),
+					GasPrice: types.ArgBytesPtr(big.NewInt(1).Bytes()),
+					Value:    types.ArgBytesPtr(big.NewInt(2).Bytes()),
+					Data:     types.ArgBytesPtr([]byte("data")),
+				},
+				map[string]interface{}{
+					types.BlockNumberKey: hex.EncodeBig(blockNumOne),
+				},
+				map[string]interface{}{
+					"0x0000000000000000000000000000000000000001": map[string]interface{}{
+						"balance": "0x64",
+						"nonce":   "0x7",
+					},
+					"0x0000000000000000000000000000000000000002": map[string]interface{}{
+						"code":      "0x6001",
+						"stateDiff": map[string]interface{}{common.HexToHash("0x1").String(): common.HexToHash("0x2").String()},
+					},
+				},
+			},
+			expectedResult: []byte("hello world"),
+			expectedError:  nil,
+			setupMocks: func(c Config, m *mocksWrapper, testCase *testCase) {
+				nonce := uint64(7)
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				txArgs := testCase.params[0].(types.TxArgs)
+				txMatchBy := mock.MatchedBy(func(tx *ethTypes.Transaction) bool {
+					return tx != nil && tx.To().Hex() == txArgs.To.Hex() && tx.Nonce() == nonce
+				})
+				overriddenNonce := uint64(7)
+				overriddenCode := []byte{0x60, 0x01}
+				overriddenStateDiff := map[common.Hash]common.Hash{common.HexToHash("0x1"): common.HexToHash("0x2")}
+				expectedStateOverride := state.StateOverride{
+					common.HexToAddress("0x1"): state.OverrideAccount{Nonce: &overriddenNonce, Balance: big.NewInt(100)},
+					common.HexToAddress("0x2"): state.OverrideAccount{Code: &overriddenCode, StateDiff: &overriddenStateDiff},
+				}
+				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumOne, Root: blockRoot}))
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
+				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
+				m.State.
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumOneUint64, true, expectedStateOverride, m.DbTx).
+					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
+					Once()
+			},
+		},
+		{
+			name: "Transaction with state override setting both state and stateDiff",
+			params: []interface{}{
+				types.TxArgs{
+					From: state.HexToAddressPtr("0x1"),
+					To:   state.HexToAddressPtr("0x2"),
+				},
+				latest,
+				map[string]interface{}{
+					"0x0000000000000000000000000000000000000002": map[string]interface{}{
+						"state":     map[string]interface{}{},
+						"stateDiff": map[string]interface{}{},
+					},
+				},
+			},
+			expectedResult: nil,
+			expectedError:  types.NewRPCError(types.InvalidParamsErrorCode, "account has both state and stateDiff overrides: 0x0000000000000000000000000000000000000002"),
+			setupMocks: func(c Config, m *mocksWrapper, testCase *testCase) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -629,6 +706,140 @@ func TestCoinbase(t *testing.T) {
 	}
 }
 
+func TestCreateAccessList(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	type testCase struct {
+		name           string
+		params         []interface{}
+		expectedResult *types.AccessListResult
+		expectedError  types.Error
+		setupMocks     func(*mocksWrapper, *testCase)
+	}
+
+	providedAccessList := ethTypes.AccessList{{Address: common.HexToAddress("0x3"), StorageKeys: []common.Hash{common.HexToHash("0x4")}}}
+	accessList := ethTypes.AccessList{
+		{Address: common.HexToAddress("0x3"), StorageKeys: []common.Hash{common.HexToHash("0x4")}},
+		{Address: common.HexToAddress("0x5"), StorageKeys: []common.Hash{}},
+	}
+
+	testCases := []testCase{
+		{
+			name: "Access list created from the latest block",
+			params: []interface{}{
+				types.AccessListTxArgs{
+					TxArgs: types.TxArgs{
+						From: state.HexToAddressPtr("0x1"),
+						To:   state.HexToAddressPtr("0x2"),
+						Gas:  types.ArgUint64Ptr(24000),
+						Data: types.ArgBytesPtr([]byte("data")),
+					},
+					AccessList: &providedAccessList,
+				},
+				latest,
+			},
+			expectedResult: &types.AccessListResult{
+				AccessList: accessList,
+				GasUsed:    types.ArgUint64(21000),
+			},
+			setupMocks: func(m *mocksWrapper, tc *testCase) {
+				nonce := uint64(7)
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumOne, Root: blockRoot}))
+				m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(blockNumOne.Uint64(), nil).Once()
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
+				m.State.On("GetNonce", context.Background(), common.HexToAddress("0x1"), blockRoot).Return(nonce, nil).Once()
+				txMatchBy := mock.MatchedBy(func(tx *ethTypes.Transaction) bool {
+					return tx != nil && tx.To().Hex() == common.HexToAddress("0x2").Hex() && tx.Gas() == 24000 && tx.Nonce() == nonce
+				})
+				m.State.
+					On("CreateAccessList", context.Background(), txMatchBy, common.HexToAddress("0x1"), nilUint64, providedAccessList, m.DbTx).
+					Return(&state.AccessListResult{AccessList: accessList, GasUsed: 21000}, nil).
+					Once()
+			},
+		},
+		{
+			name: "Reverted transaction reports the error with the access list",
+			params: []interface{}{
+				types.TxArgs{
+					From: state.HexToAddressPtr("0x1"),
+					To:   state.HexToAddressPtr("0x2"),
+					Gas:  types.ArgUint64Ptr(24000),
+				},
+				map[string]interface{}{
+					types.BlockNumberKey: hex.EncodeBig(blockNumOne),
+				},
+			},
+			expectedResult: &types.AccessListResult{
+				AccessList: ethTypes.AccessList{},
+				GasUsed:    types.ArgUint64(21000),
+				Error:      runtime.ErrExecutionReverted.Error(),
+			},
+			setupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumOne, Root: blockRoot}))
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
+				m.State.On("GetNonce", context.Background(), common.HexToAddress("0x1"), blockRoot).Return(uint64(0), nil).Once()
+				m.State.
+					On("CreateAccessList", context.Background(), mock.IsType(&ethTypes.Transaction{}), common.HexToAddress("0x1"), &blockNumOneUint64, ethTypes.AccessList(nil), m.DbTx).
+					Return(&state.AccessListResult{GasUsed: 21000, Err: runtime.ErrExecutionReverted}, nil).
+					Once()
+			},
+		},
+		{
+			name: "Failed to execute the transaction",
+			params: []interface{}{
+				types.TxArgs{
+					From: state.HexToAddressPtr("0x1"),
+					To:   state.HexToAddressPtr("0x2"),
+					Gas:  types.ArgUint64Ptr(24000),
+				},
+				latest,
+			},
+			expectedError: types.NewRPCError(types.DefaultErrorCode, "failed to create access list: failed to process unsigned transaction"),
+			setupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumOne, Root: blockRoot}))
+				m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(blockNumOne.Uint64(), nil).Once()
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
+				m.State.On("GetNonce", context.Background(), common.HexToAddress("0x1"), blockRoot).Return(uint64(0), nil).Once()
+				m.State.
+					On("CreateAccessList", context.Background(), mock.IsType(&ethTypes.Transaction{}), common.HexToAddress("0x1"), nilUint64, ethTypes.AccessList(nil), m.DbTx).
+					Return(nil, errors.New("failed to process unsigned transaction")).
+					Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			tc := testCase
+			tc.setupMocks(m, &tc)
+
+			res, err := s.JSONRPCCall("eth_createAccessList", tc.params...)
+			require.NoError(t, err)
+
+			if tc.expectedResult != nil {
+				require.Nil(t, res.Error)
+				var result types.AccessListResult
+				err = json.Unmarshal(res.Result, &result)
+				require.NoError(t, err)
+				assert.Equal(t, *tc.expectedResult, result)
+			}
+
+			if tc.expectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, tc.expectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.expectedError.Error(), res.Error.Message)
+			}
+		})
+	}
+}
+
 func TestEstimateGas(t *testing.T) {
 	s, m, _ := newSequencerMockedServer(t)
 	defer s.Stop()
@@ -685,7 +896,7 @@ func TestEstimateGas(t *testing.T) {
 					Return(nonce, nil).
 					Once()
 				m.State.
-					On("EstimateGas", txMatchBy, *txArgs.From, nilUint64, m.DbTx).
+					On("EstimateGas", txMatchBy, *txArgs.From, nilUint64, state.StateOverride(nil), m.DbTx).
 					Return(*testCase.expectedResult, nil, nil).
 					Once()
 			},
@@ -726,7 +937,7 @@ func TestEstimateGas(t *testing.T) {
 				m.State.On("GetLastL2Block", context.Background(), m.DbTx).Return(block, nil).Once()
 
 				m.State.
-					On("EstimateGas", txMatchBy, common.HexToAddress(DefaultSenderAddress), nilUint64, m.DbTx).
+					On("EstimateGas", txMatchBy, common.HexToAddress(DefaultSenderAddress), nilUint64, state.StateOverride(nil), m.DbTx).
 					Return(*testCase.expectedResult, nil, nil).
 					Once()
 			},
@@ -796,6 +1007,187 @@ func TestGasPrice(t *testing.T) {
 	}
 }
 
+func TestMaxPriorityFeePerGas(t *testing.T) {
+	s, m, c := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	type testCase struct {
+		name           string
+		expectedResult uint64
+		expectedError  types.Error
+		setupMocks     func(m *mocksWrapper)
+	}
+
+	txWithTip := func(nonce uint64, tip int64) *ethTypes.Transaction {
+		return ethTypes.NewTx(&ethTypes.DynamicFeeTx{Nonce: nonce, GasTipCap: big.NewInt(tip), GasFeeCap: big.NewInt(1000)})
+	}
+
+	testCases := []testCase{
+		{
+			name:           "suggests the percentile of the tips sampled from the last blocks",
+			expectedResult: 30,
+			setupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(uint64(2), nil).Once()
+				m.State.On("GetTxsByBlockNumber", context.Background(), uint64(2), m.DbTx).
+					Return([]*ethTypes.Transaction{txWithTip(0, 50), txWithTip(1, 30)}, nil).Once()
+				m.State.On("GetTxsByBlockNumber", context.Background(), uint64(1), m.DbTx).
+					Return([]*ethTypes.Transaction{txWithTip(2, 40), txWithTip(3, 20)}, nil).Once()
+				m.Pool.On("GetGasPrices", context.Background()).Return(pool.GasPrices{L2GasPrice: 10}, nil).Once()
+			},
+		},
+		{
+			name:           "suggests the l2 gas price when the sampled tips are lower",
+			expectedResult: 100,
+			setupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(uint64(1), nil).Once()
+				m.State.On("GetTxsByBlockNumber", context.Background(), uint64(1), m.DbTx).
+					Return([]*ethTypes.Transaction{txWithTip(0, 50)}, nil).Once()
+				m.Pool.On("GetGasPrices", context.Background()).Return(pool.GasPrices{L2GasPrice: 100}, nil).Once()
+			},
+		},
+		{
+			name:          "failed to get the txs of a block",
+			expectedError: types.NewRPCError(types.DefaultErrorCode, "failed to get txs of block 1"),
+			setupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(uint64(1), nil).Once()
+				m.State.On("GetTxsByBlockNumber", context.Background(), uint64(1), m.DbTx).
+					Return(nil, errors.New("failed to get txs")).Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.setupMocks(m)
+
+			tip, err := c.SuggestGasTipCap(context.Background())
+			if testCase.expectedError != nil {
+				rpcErr := err.(rpc.Error)
+				assert.Equal(t, testCase.expectedError.ErrorCode(), rpcErr.ErrorCode())
+				assert.Equal(t, testCase.expectedError.Error(), rpcErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedResult, tip.Uint64())
+		})
+	}
+}
+
+func TestFeeHistory(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	type testCase struct {
+		name           string
+		params         []interface{}
+		expectedResult string
+		expectedError  types.Error
+		setupMocks     func(m *mocksWrapper)
+	}
+
+	header := func(number, gasUsed uint64) *state.L2Header {
+		return state.NewL2Header(&ethTypes.Header{Number: new(big.Int).SetUint64(number), GasUsed: gasUsed, GasLimit: 100000})
+	}
+
+	testCases := []testCase{
+		{
+			name:           "fee history with reward percentiles",
+			params:         []interface{}{"0x2", "latest", []float64{10, 50, 90}},
+			expectedResult: `{"oldestBlock":"0x4","reward":[["0x0","0x0","0x0"],["0xa","0xa","0x1e"]],"baseFeePerGas":["0x0","0x0","0x0"],"gasUsedRatio":[0,0.5]}`,
+			setupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(uint64(5), nil).Once()
+				m.State.On("GetL2BlockHeaderByNumber", context.Background(), uint64(4), m.DbTx).Return(header(4, 0), nil).Once()
+				m.State.On("GetEffectiveGasPricesByL2BlockNumber", context.Background(), uint64(4), m.DbTx).
+					Return([]state.L2TxEffectiveGasPrice{}, nil).Once()
+				m.State.On("GetL2BlockHeaderByNumber", context.Background(), uint64(5), m.DbTx).Return(header(5, 50000), nil).Once()
+				m.State.On("GetEffectiveGasPricesByL2BlockNumber", context.Background(), uint64(5), m.DbTx).
+					Return([]state.L2TxEffectiveGasPrice{
+						{GasUsed: 20000, EffectiveGasPrice: big.NewInt(30)},
+						{GasUsed: 30000, EffectiveGasPrice: big.NewInt(10)},
+					}, nil).Once()
+			},
+		},
+		{
+			name:           "fee history without reward percentiles and block count as decimal number",
+			params:         []interface{}{2, "0x1"},
+			expectedResult: `{"oldestBlock":"0x0","baseFeePerGas":["0x0","0x0","0x0"],"gasUsedRatio":[0,0.25]}`,
+			setupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL2BlockHeaderByNumber", context.Background(), uint64(0), m.DbTx).Return(header(0, 0), nil).Once()
+				m.State.On("GetL2BlockHeaderByNumber", context.Background(), uint64(1), m.DbTx).Return(header(1, 25000), nil).Once()
+			},
+		},
+		{
+			name:           "block count capped to the available blocks",
+			params:         []interface{}{"0x10", "earliest"},
+			expectedResult: `{"oldestBlock":"0x0","baseFeePerGas":["0x0","0x0"],"gasUsedRatio":[0]}`,
+			setupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL2BlockHeaderByNumber", context.Background(), uint64(0), m.DbTx).Return(header(0, 0), nil).Once()
+			},
+		},
+		{
+			name:           "zero block count",
+			params:         []interface{}{"0x0", "latest"},
+			expectedResult: `{"oldestBlock":"0x0","gasUsedRatio":[]}`,
+			setupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+			},
+		},
+		{
+			name:          "invalid reward percentile",
+			params:        []interface{}{"0x1", "latest", []float64{101}},
+			expectedError: types.NewRPCError(types.InvalidParamsErrorCode, "invalid reward percentile: 101.000000"),
+			setupMocks:    func(m *mocksWrapper) {},
+		},
+		{
+			name:          "reward percentiles not in ascending order",
+			params:        []interface{}{"0x1", "latest", []float64{50, 10}},
+			expectedError: types.NewRPCError(types.InvalidParamsErrorCode, "invalid reward percentile: #0:50.000000 >= #1:10.000000"),
+			setupMocks:    func(m *mocksWrapper) {},
+		},
+		{
+			name:          "block not found",
+			params:        []interface{}{"0x1", "0x7"},
+			expectedError: types.NewRPCError(types.DefaultErrorCode, "block 7 not found"),
+			setupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL2BlockHeaderByNumber", context.Background(), uint64(7), m.DbTx).Return(nil, state.ErrNotFound).Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.setupMocks(m)
+
+			res, err := s.JSONRPCCall("eth_feeHistory", testCase.params...)
+			require.NoError(t, err)
+
+			if testCase.expectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, testCase.expectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, testCase.expectedError.Error(), res.Error.Message)
+				return
+			}
+			require.Nil(t, res.Error)
+			assert.JSONEq(t, testCase.expectedResult, string(res.Result))
+		})
+	}
+}
+
 func TestGetBalance(t *testing.T) {
 	s, m, _ := newSequencerMockedServer(t)
 	defer s.Stop()
@@ -1632,6 +2024,97 @@ func TestGetCode(t *testing.T) {
 	}
 }
 
+func TestGetProof(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	emptyTreeProof := func(key byte) *merkletree.Proof {
+		proof, err := merkletree.NewProof([]byte{key}, big.NewInt(0), nil, nil, nil)
+		require.NoError(t, err)
+		return proof
+	}
+	sibling := append(common.HexToHash("0x1").Bytes(), common.HexToHash("0x2").Bytes()...)
+	storageProof, err := merkletree.NewProof([]byte{4}, big.NewInt(5), [][]byte{sibling}, common.HexToHash("0x6").Bytes(), big.NewInt(7))
+	require.NoError(t, err)
+
+	accountProof := &state.AccountProof{
+		Balance:  emptyTreeProof(1),
+		Nonce:    emptyTreeProof(2),
+		CodeHash: emptyTreeProof(3),
+		Storage:  []*merkletree.Proof{storageProof},
+	}
+
+	block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumTen, Root: blockRoot}))
+
+	t.Run("get proof", func(t *testing.T) {
+		m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("GetLastL2Block", context.Background(), m.DbTx).Return(block, nil).Once()
+		m.State.On("GetAccountProof", context.Background(), addressArg, []*big.Int{keyArg.Big()}, blockRoot).Return(accountProof, nil).Once()
+
+		res, err := s.JSONRPCCall("eth_getProof", addressArg.String(), []string{keyArg.String()})
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+
+		var proof types.AccountProof
+		require.NoError(t, json.Unmarshal(res.Result, &proof))
+		assert.Equal(t, addressArg, proof.Address)
+		assert.Equal(t, uint64(0), (*big.Int)(&proof.Balance).Uint64())
+		assert.Equal(t, common.BytesToHash([]byte{1}), proof.BalanceProof.Key)
+		assert.Empty(t, proof.BalanceProof.Siblings)
+		assert.Nil(t, proof.BalanceProof.InsKey)
+
+		// the account is not in the empty tree
+		assert.NoError(t, proof.BalanceProof.Verify(common.Hash{}, big.NewInt(0)))
+		assert.NoError(t, proof.NonceProof.Verify(common.Hash{}, big.NewInt(0)))
+		assert.NoError(t, proof.CodeHashProof.Verify(common.Hash{}, big.NewInt(0)))
+		assert.ErrorIs(t, proof.BalanceProof.Verify(common.Hash{}, big.NewInt(1)), merkletree.ErrInvalidProof)
+		assert.ErrorIs(t, proof.BalanceProof.Verify(blockRoot, big.NewInt(0)), merkletree.ErrInvalidProof)
+
+		require.Len(t, proof.StorageProof, 1)
+		assert.Equal(t, keyArg, proof.StorageProof[0].Key)
+		assert.Equal(t, uint64(5), (*big.Int)(&proof.StorageProof[0].Value).Uint64())
+		assert.Equal(t, common.BytesToHash([]byte{4}), proof.StorageProof[0].Proof.Key)
+		require.Len(t, proof.StorageProof[0].Proof.Siblings, 1)
+		assert.Equal(t, sibling, []byte(proof.StorageProof[0].Proof.Siblings[0]))
+		assert.Equal(t, common.HexToHash("0x6"), *proof.StorageProof[0].Proof.InsKey)
+		assert.Equal(t, uint64(7), (*big.Int)(proof.StorageProof[0].Proof.InsValue).Uint64())
+	})
+
+	t.Run("get proof by block number without storage keys", func(t *testing.T) {
+		m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("GetL2BlockByNumber", context.Background(), blockNumTenUint64, m.DbTx).Return(block, nil).Once()
+		m.State.On("GetAccountProof", context.Background(), addressArg, []*big.Int{}, blockRoot).Return(&state.AccountProof{
+			Balance:  emptyTreeProof(1),
+			Nonce:    emptyTreeProof(2),
+			CodeHash: emptyTreeProof(3),
+		}, nil).Once()
+
+		res, err := s.JSONRPCCall("eth_getProof", addressArg.String(), []string{}, hex.EncodeBig(blockNumTen))
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+
+		var proof types.AccountProof
+		require.NoError(t, json.Unmarshal(res.Result, &proof))
+		assert.NotNil(t, proof.StorageProof)
+		assert.Empty(t, proof.StorageProof)
+	})
+
+	t.Run("failed to get proof from state", func(t *testing.T) {
+		m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("GetLastL2Block", context.Background(), m.DbTx).Return(block, nil).Once()
+		m.State.On("GetAccountProof", context.Background(), addressArg, []*big.Int{}, blockRoot).Return(nil, errors.New("failed")).Once()
+
+		res, err := s.JSONRPCCall("eth_getProof", addressArg.String(), []string{})
+		require.NoError(t, err)
+		require.NotNil(t, res.Error)
+		assert.Equal(t, types.DefaultErrorCode, res.Error.Code)
+		assert.Equal(t, "failed to get account proof from state", res.Error.Message)
+	})
+}
+
 func TestGetStorageAt(t *testing.T) {
 	s, m, _ := newSequencerMockedServer(t)
 	defer s.Stop()
@@ -3318,6 +3801,129 @@ func TestGetTransactionReceipt(t *testing.T) {
 	}
 }
 
+func TestGetBlockReceipts(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(0).SetUint64(s.ChainID()))
+	require.NoError(t, err)
+
+	signedTx, err := auth.Signer(auth.From, ethTypes.NewTransaction(1, common.HexToAddress("0x2"), big.NewInt(1), 21000, big.NewInt(1), []byte{}))
+	require.NoError(t, err)
+
+	header := &ethTypes.Header{Number: big.NewInt(1)}
+	block := state.NewL2Block(state.NewL2Header(header), []*ethTypes.Transaction{signedTx}, []*state.L2Header{}, []*ethTypes.Receipt{}, &trie.StackTrie{})
+
+	receipt := ethTypes.NewReceipt([]byte{}, false, 21000)
+	receipt.TxHash = signedTx.Hash()
+	receipt.GasUsed = 21000
+	receipt.BlockNumber = big.NewInt(1)
+	receipt.BlockHash = block.Hash()
+	receipt.EffectiveGasPrice = big.NewInt(1)
+	receipt.Logs = []*ethTypes.Log{{Address: common.HexToAddress("0x3"), TxHash: signedTx.Hash(), Topics: []common.Hash{}, Data: []byte{}}}
+
+	type testCase struct {
+		name           string
+		params         []interface{}
+		expectedResult []types.Receipt
+		expectedError  types.Error
+		setupMocks     func(*mocksWrapper, *testCase)
+	}
+
+	testCases := []*testCase{
+		{
+			name:   "get receipts by block number successfully",
+			params: []interface{}{"0x1"},
+			expectedResult: func() []types.Receipt {
+				r, err := types.NewReceipt(*signedTx, receipt)
+				require.NoError(t, err)
+				return []types.Receipt{r}
+			}(),
+			setupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL2BlockByNumber", context.Background(), uint64(1), m.DbTx).Return(block, nil).Once()
+				m.State.On("GetReceiptsByL2BlockNumber", context.Background(), uint64(1), m.DbTx).Return([]*ethTypes.Receipt{receipt}, nil).Once()
+			},
+		},
+		{
+			name:   "get receipts by block hash successfully",
+			params: []interface{}{block.Hash().String()},
+			expectedResult: func() []types.Receipt {
+				r, err := types.NewReceipt(*signedTx, receipt)
+				require.NoError(t, err)
+				return []types.Receipt{r}
+			}(),
+			setupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL2BlockByHash", context.Background(), block.Hash(), m.DbTx).Return(block, nil).Once()
+				m.State.On("GetReceiptsByL2BlockNumber", context.Background(), uint64(1), m.DbTx).Return([]*ethTypes.Receipt{receipt}, nil).Once()
+			},
+		},
+		{
+			name:   "block not found",
+			params: []interface{}{"0x2"},
+			setupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL2BlockByNumber", context.Background(), uint64(2), m.DbTx).Return(nil, state.ErrNotFound).Once()
+			},
+		},
+		{
+			name:          "receipt missing for a block tx",
+			params:        []interface{}{"0x1"},
+			expectedError: types.NewRPCError(types.DefaultErrorCode, fmt.Sprintf("couldn't load receipt for tx %v", signedTx.Hash().String())),
+			setupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL2BlockByNumber", context.Background(), uint64(1), m.DbTx).Return(block, nil).Once()
+				m.State.On("GetReceiptsByL2BlockNumber", context.Background(), uint64(1), m.DbTx).Return([]*ethTypes.Receipt{}, nil).Once()
+			},
+		},
+		{
+			name:          "failed to get receipts",
+			params:        []interface{}{"0x1"},
+			expectedError: types.NewRPCError(types.DefaultErrorCode, "couldn't load receipts for block 1"),
+			setupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL2BlockByNumber", context.Background(), uint64(1), m.DbTx).Return(block, nil).Once()
+				m.State.On("GetReceiptsByL2BlockNumber", context.Background(), uint64(1), m.DbTx).Return(nil, errors.New("failed to get receipts")).Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			tc := testCase
+			tc.setupMocks(m, tc)
+
+			res, err := s.JSONRPCCall("eth_getBlockReceipts", tc.params...)
+			require.NoError(t, err)
+
+			if tc.expectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, tc.expectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.expectedError.Error(), res.Error.Message)
+				return
+			}
+
+			require.Nil(t, res.Error)
+			if tc.expectedResult == nil {
+				assert.Equal(t, "null", string(res.Result))
+				return
+			}
+
+			expected, err := json.Marshal(tc.expectedResult)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(expected), string(res.Result))
+		})
+	}
+}
+
 func TestSendRawTransactionViaGeth(t *testing.T) {
 	s, m, c := newSequencerMockedServer(t)
 	defer s.Stop()
@@ -5196,3 +5802,503 @@ func TestParalelize(t *testing.T) {
 	assert.ElementsMatch(t, []int{13, 14, 15}, results[4])
 	assert.ElementsMatch(t, []int{16}, results[5])
 }
+
+func TestSubscribeBatchEvents(t *testing.T) {
+	storage := newStorageMock(t)
+	e := &EthEndpoints{storage: storage}
+	wsConn := &concurrentWsConn{}
+
+	subscriptions := map[string]state.BatchEventType{
+		"newTrustedBatches":  state.BatchEventClosed,
+		"newVirtualBatches":  state.BatchEventVirtualized,
+		"newVerifiedBatches": state.BatchEventVerified,
+	}
+	for name, eventType := range subscriptions {
+		storage.On("NewBatchFilter", wsConn, eventType).Return("0x"+name, nil).Once()
+		id, err := e.Subscribe(wsConn, name, nil)
+		require.Nil(t, err)
+		assert.Equal(t, "0x"+name, id)
+	}
+
+	storage.On("NewBatchFilter", wsConn, state.BatchEventVerified).Return("", errors.New("failed")).Once()
+	_, err := e.Subscribe(wsConn, "newVerifiedBatches", nil)
+	require.NotNil(t, err)
+	assert.Equal(t, "failed to create new batch filter", err.Error())
+}
+
+func TestOnNewBatch(t *testing.T) {
+	storage := newStorageMock(t)
+	e := &EthEndpoints{storage: storage}
+
+	newFilter := func(eventType state.BatchEventType) *Filter {
+		return &Filter{
+			ID:         string(eventType),
+			Type:       FilterTypeBatch,
+			Parameters: eventType,
+			WsConn:     &concurrentWsConn{subscriptionQueue: newSubscriptionQueue(0, "")},
+		}
+	}
+	popData := func(t *testing.T, f *Filter) []byte {
+		messages := f.WsConn.subscriptionQueue.popAll()
+		require.Len(t, messages, 1)
+		var res types.SubscriptionResponse
+		require.NoError(t, json.Unmarshal(messages[0], &res))
+		assert.Equal(t, f.ID, res.Params.Subscription)
+		return res.Params.Result
+	}
+	closedFilter := newFilter(state.BatchEventClosed)
+	virtualizedFilter := newFilter(state.BatchEventVirtualized)
+	verifiedFilter := newFilter(state.BatchEventVerified)
+	storage.On("GetAllBatchFiltersWithWSConn").Return([]*Filter{closedFilter, virtualizedFilter, verifiedFilter}).Twice()
+
+	e.onNewBatch(state.NewBatchEvent{Type: state.BatchEventClosed, BatchNumber: 7})
+	e.onNewBatch(state.NewBatchEvent{Type: state.BatchEventVerified, BatchNumber: 5, TxHash: common.HexToHash("0x1"), BlockNumber: 100})
+
+	data := popData(t, closedFilter)
+	assert.JSONEq(t, `{"number":"0x7","status":"closed"}`, string(data))
+
+	data = popData(t, verifiedFilter)
+	assert.JSONEq(t, `{"number":"0x5","status":"verified","l1TxHash":"0x0000000000000000000000000000000000000000000000000000000000000001","l1BlockNumber":"0x64"}`, string(data))
+
+	for _, f := range []*Filter{closedFilter, virtualizedFilter, verifiedFilter} {
+		assert.Zero(t, f.WsConn.subscriptionQueue.len())
+	}
+}
+
+func TestSubscribeResume(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.WebSockets.MaxResumeBlockRange = 10
+
+	newBlock := func(number uint64) *state.L2Block {
+		return state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: new(big.Int).SetUint64(number)}))
+	}
+	newLog := func(blockNumber uint64, index uint) *ethTypes.Log {
+		return &ethTypes.Log{Address: common.HexToAddress("0x1"), BlockNumber: blockNumber, Index: index, Topics: []common.Hash{}, Data: []byte{}}
+	}
+	// results returns the results of the notifications sent to the connection
+	results := func(t *testing.T, wsConn *concurrentWsConn, count int) []json.RawMessage {
+		require.Eventually(t, func() bool { return wsConn.subscriptionQueue.len() >= count }, 5*time.Second, 10*time.Millisecond)
+		messages := wsConn.subscriptionQueue.popAll()
+		results := make([]json.RawMessage, 0, len(messages))
+		for _, message := range messages {
+			var res types.SubscriptionResponse
+			require.NoError(t, json.Unmarshal(message, &res))
+			results = append(results, res.Params.Result)
+		}
+		return results
+	}
+
+	t.Run("newHeads", func(t *testing.T) {
+		st := mocks.NewStateMock(t)
+		dbTx := mocks.NewDBTxMock(t)
+		e := &EthEndpoints{cfg: cfg, state: st, storage: NewStorage()}
+		wsConn := &concurrentWsConn{subscriptionQueue: newSubscriptionQueue(0, "")}
+
+		dbTx.On("Commit", context.Background()).Return(nil).Once()
+		st.On("BeginStateTransaction", context.Background()).Return(dbTx, nil).Once()
+		st.On("GetLastL2BlockNumber", context.Background(), dbTx).Return(uint64(3), nil).Once()
+		// a block is added while the subscription is created
+		st.On("GetLastL2BlockNumber", context.Background(), nil).Return(uint64(4), nil).Once()
+		for _, number := range []uint64{2, 3, 4} {
+			st.On("GetL2BlockByNumber", context.Background(), number, nil).Return(newBlock(number), nil).Once()
+		}
+
+		resumeFromBlock := types.BlockNumber(2)
+		id, rpcErr := e.Subscribe(wsConn, "newHeads", &LogFilter{ResumeFromBlock: &resumeFromBlock})
+		require.Nil(t, rpcErr)
+		assert.NotEmpty(t, id)
+
+		// the live block already replayed is sent once
+		e.onNewL2Block(state.NewL2BlockEvent{Block: *newBlock(4)})
+		e.onNewL2Block(state.NewL2BlockEvent{Block: *newBlock(5)})
+
+		blocks := results(t, wsConn, 4)
+		require.Len(t, blocks, 4)
+		for i, data := range blocks {
+			var b types.Block
+			require.NoError(t, json.Unmarshal(data, &b))
+			assert.Equal(t, uint64(i+2), uint64(b.Number))
+		}
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		st := mocks.NewStateMock(t)
+		dbTx := mocks.NewDBTxMock(t)
+		e := &EthEndpoints{cfg: cfg, state: st, storage: NewStorage()}
+		wsConn := &concurrentWsConn{subscriptionQueue: newSubscriptionQueue(0, "")}
+
+		dbTx.On("Commit", context.Background()).Return(nil).Once()
+		st.On("BeginStateTransaction", context.Background()).Return(dbTx, nil).Once()
+		// the last block is read to replay the logs
+		st.On("GetLastL2BlockNumber", context.Background(), nil).Return(uint64(3), nil).Once()
+		st.On("GetLastL2BlockNumber", context.Background(), dbTx).Return(uint64(3), nil).Once()
+		st.On("GetLogsPage", context.Background(), uint64(2), uint64(0), uint64(3), []common.Address(nil), [][]common.Hash(nil), uint64(resumeLogsPageSize), nil).
+			Return([]*ethTypes.Log{newLog(2, 0), newLog(3, 0)}, nil).
+			Once()
+
+		resumeFromBlock := types.BlockNumber(2)
+		id, rpcErr := e.Subscribe(wsConn, "logs", &LogFilter{ResumeFromBlock: &resumeFromBlock})
+		require.Nil(t, rpcErr)
+		assert.NotEmpty(t, id)
+
+		e.onNewL2Block(state.NewL2BlockEvent{Block: *newBlock(3), Logs: []*ethTypes.Log{newLog(3, 0)}})
+		e.onNewL2Block(state.NewL2BlockEvent{Block: *newBlock(4), Logs: []*ethTypes.Log{newLog(4, 0), newLog(4, 1)}})
+
+		logs := results(t, wsConn, 4)
+		require.Len(t, logs, 4)
+		expected := [][2]uint64{{2, 0}, {3, 0}, {4, 0}, {4, 1}}
+		for i, data := range logs {
+			var l types.Log
+			require.NoError(t, json.Unmarshal(data, &l))
+			assert.Equal(t, expected[i], [2]uint64{uint64(l.BlockNumber), uint64(l.LogIndex)})
+		}
+	})
+
+	t.Run("logs from a block without resume", func(t *testing.T) {
+		st := mocks.NewStateMock(t)
+		dbTx := mocks.NewDBTxMock(t)
+		storage := NewStorage()
+		e := &EthEndpoints{cfg: cfg, state: st, storage: storage}
+		wsConn := &concurrentWsConn{subscriptionQueue: newSubscriptionQueue(0, "")}
+
+		dbTx.On("Commit", context.Background()).Return(nil).Once()
+		st.On("BeginStateTransaction", context.Background()).Return(dbTx, nil).Once()
+		st.On("GetLastL2BlockNumber", context.Background(), nil).Return(uint64(20), nil).Once()
+
+		// the fromBlock only filters the logs, so no blocks are replayed
+		fromBlock := types.EarliestBlockNumber
+		id, rpcErr := e.Subscribe(wsConn, "logs", &LogFilter{FromBlock: &fromBlock})
+		require.Nil(t, rpcErr)
+		filter, err := storage.GetFilter(id.(string))
+		require.NoError(t, err)
+		assert.Nil(t, filter.resume)
+	})
+
+	t.Run("max resume block range exceeded", func(t *testing.T) {
+		st := mocks.NewStateMock(t)
+		dbTx := mocks.NewDBTxMock(t)
+		storage := NewStorage()
+		e := &EthEndpoints{cfg: cfg, state: st, storage: storage}
+		wsConn := &concurrentWsConn{subscriptionQueue: newSubscriptionQueue(0, "")}
+
+		dbTx.On("Rollback", context.Background()).Return(nil).Once()
+		st.On("BeginStateTransaction", context.Background()).Return(dbTx, nil).Once()
+		st.On("GetLastL2BlockNumber", context.Background(), dbTx).Return(uint64(20), nil).Once()
+
+		resumeFromBlock := types.BlockNumber(10)
+		_, rpcErr := e.Subscribe(wsConn, "newHeads", &LogFilter{ResumeFromBlock: &resumeFromBlock})
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, types.InvalidParamsErrorCode, rpcErr.ErrorCode())
+		assert.Equal(t, "subscriptions can only be resumed from the last 10 blocks", rpcErr.Error())
+		assert.Empty(t, storage.GetAllBlockFiltersWithWSConn())
+	})
+}
+
+func TestSendRawTransactionSync(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.SendRawTransactionSyncTimeout = configTypes.NewDuration(10 * time.Second)
+
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix("0x28b2b0318721be8c8339199172cd7cc8f5e273800a35616ec893083a4b32c02e", "0x"))
+	require.NoError(t, err)
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(1))
+	require.NoError(t, err)
+	signedTx, err := auth.Signer(auth.From, ethTypes.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), uint64(1), big.NewInt(1), []byte{}))
+	require.NoError(t, err)
+	txBinary, err := signedTx.MarshalBinary()
+	require.NoError(t, err)
+	input := hex.EncodeToHex(txBinary)
+
+	httpRequest := &http.Request{Header: http.Header{}}
+	txMatchByHash := mock.MatchedBy(func(tx ethTypes.Transaction) bool {
+		return tx.Hash() == signedTx.Hash()
+	})
+	timeout := types.ArgUint64(50)
+
+	t.Run("included in a block", func(t *testing.T) {
+		st := mocks.NewStateMock(t)
+		p := mocks.NewPoolMock(t)
+		dbTx := mocks.NewDBTxMock(t)
+		e := &EthEndpoints{cfg: cfg, pool: p, state: st, storage: NewStorage(), txWaiters: newTxWaiters()}
+
+		receipt := ethTypes.NewReceipt([]byte{}, false, 0)
+		receipt.TxHash = signedTx.Hash()
+		block := state.NewL2Block(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(1)}), []*ethTypes.Transaction{signedTx}, []*state.L2Header{}, []*ethTypes.Receipt{receipt}, &trie.StackTrie{})
+
+		// the block event is notified once the tx is added to the pool
+		p.On("AddTx", context.Background(), txMatchByHash, "").
+			Run(func(args mock.Arguments) { go e.onNewL2Block(state.NewL2BlockEvent{Block: *block}) }).
+			Return(nil).
+			Once()
+		dbTx.On("Commit", context.Background()).Return(nil).Once()
+		st.On("BeginStateTransaction", context.Background()).Return(dbTx, nil).Once()
+		st.On("GetTransactionByHash", context.Background(), signedTx.Hash(), dbTx).Return(signedTx, nil).Once()
+		st.On("GetTransactionReceipt", context.Background(), signedTx.Hash(), dbTx).Return(receipt, nil).Once()
+
+		start := time.Now()
+		res, rpcErr := e.SendRawTransactionSync(httpRequest, input, nil)
+		require.Nil(t, rpcErr)
+		assert.Less(t, time.Since(start), cfg.SendRawTransactionSyncTimeout.Duration)
+
+		r, ok := res.(types.Receipt)
+		require.True(t, ok)
+		assert.Equal(t, signedTx.Hash(), r.TxHash)
+		assert.Empty(t, e.txWaiters.waiters)
+	})
+
+	t.Run("timeout with the tx failed in the pool", func(t *testing.T) {
+		st := mocks.NewStateMock(t)
+		p := mocks.NewPoolMock(t)
+		dbTx := mocks.NewDBTxMock(t)
+		e := &EthEndpoints{cfg: cfg, pool: p, state: st, storage: NewStorage(), txWaiters: newTxWaiters()}
+
+		failedReason := "out of counters"
+		p.On("AddTx", context.Background(), txMatchByHash, "").Return(nil).Once()
+		dbTx.On("Commit", context.Background()).Return(nil).Once()
+		st.On("BeginStateTransaction", context.Background()).Return(dbTx, nil).Once()
+		st.On("GetTransactionByHash", context.Background(), signedTx.Hash(), dbTx).Return(nil, state.ErrNotFound).Once()
+		p.On("GetTxByHash", context.Background(), signedTx.Hash()).
+			Return(&pool.Transaction{Transaction: *signedTx, Status: pool.TxStatusFailed, FailedReason: &failedReason}, nil).
+			Once()
+
+		_, rpcErr := e.SendRawTransactionSync(httpRequest, input, &timeout)
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, types.DefaultErrorCode, rpcErr.ErrorCode())
+		assert.Equal(t, fmt.Sprintf("transaction %v is failed in the pool: out of counters", signedTx.Hash().String()), rpcErr.Error())
+		assert.Empty(t, e.txWaiters.waiters)
+	})
+
+	t.Run("timeout with the tx pending in the pool", func(t *testing.T) {
+		st := mocks.NewStateMock(t)
+		p := mocks.NewPoolMock(t)
+		dbTx := mocks.NewDBTxMock(t)
+		e := &EthEndpoints{cfg: cfg, pool: p, state: st, storage: NewStorage(), txWaiters: newTxWaiters()}
+
+		p.On("AddTx", context.Background(), txMatchByHash, "").Return(nil).Once()
+		dbTx.On("Commit", context.Background()).Return(nil).Once()
+		st.On("BeginStateTransaction", context.Background()).Return(dbTx, nil).Once()
+		st.On("GetTransactionByHash", context.Background(), signedTx.Hash(), dbTx).Return(nil, state.ErrNotFound).Once()
+		p.On("GetTxByHash", context.Background(), signedTx.Hash()).
+			Return(&pool.Transaction{Transaction: *signedTx, Status: pool.TxStatusPending}, nil).
+			Once()
+
+		_, rpcErr := e.SendRawTransactionSync(httpRequest, input, &timeout)
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, types.DefaultErrorCode, rpcErr.ErrorCode())
+		assert.Equal(t, fmt.Sprintf("transaction %v not included in a block after 50ms, pool status: pending", signedTx.Hash().String()), rpcErr.Error())
+	})
+
+	t.Run("request cancelled", func(t *testing.T) {
+		st := mocks.NewStateMock(t)
+		p := mocks.NewPoolMock(t)
+		dbTx := mocks.NewDBTxMock(t)
+		e := &EthEndpoints{cfg: cfg, pool: p, state: st, storage: NewStorage(), txWaiters: newTxWaiters()}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		cancelledRequest := httpRequest.WithContext(ctx)
+
+		p.On("AddTx", context.Background(), txMatchByHash, "").Return(nil).Once()
+		dbTx.On("Commit", context.Background()).Return(nil).Once()
+		st.On("BeginStateTransaction", context.Background()).Return(dbTx, nil).Once()
+		st.On("GetTransactionByHash", context.Background(), signedTx.Hash(), dbTx).Return(nil, state.ErrNotFound).Once()
+		p.On("GetTxByHash", context.Background(), signedTx.Hash()).
+			Return(&pool.Transaction{Transaction: *signedTx, Status: pool.TxStatusPending}, nil).
+			Once()
+
+		start := time.Now()
+		_, rpcErr := e.SendRawTransactionSync(cancelledRequest, input, nil)
+		require.NotNil(t, rpcErr)
+		assert.Less(t, time.Since(start), cfg.SendRawTransactionSyncTimeout.Duration)
+		assert.Empty(t, e.txWaiters.waiters)
+	})
+
+	t.Run("failed to add to the pool", func(t *testing.T) {
+		p := mocks.NewPoolMock(t)
+		e := &EthEndpoints{cfg: cfg, pool: p, storage: NewStorage(), txWaiters: newTxWaiters()}
+
+		p.On("AddTx", context.Background(), txMatchByHash, "").Return(errors.New("nonce too low")).Once()
+
+		_, rpcErr := e.SendRawTransactionSync(httpRequest, input, nil)
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, "nonce too low", rpcErr.Error())
+		assert.Empty(t, e.txWaiters.waiters)
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		e := &EthEndpoints{cfg: cfg, txWaiters: newTxWaiters()}
+
+		zero := types.ArgUint64(0)
+		_, rpcErr := e.SendRawTransactionSync(httpRequest, input, &zero)
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, types.InvalidParamsErrorCode, rpcErr.ErrorCode())
+	})
+}
+
+func TestSendRawTransactionConditional(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	tx := ethTypes.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), uint64(1), big.NewInt(1), []byte{})
+	txBinary, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	input := hex.EncodeToHex(txBinary)
+
+	account := common.HexToAddress("0x2")
+	slot := common.HexToHash("0x1")
+	root := common.HexToHash("0x3")
+	lastBlock := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(10), Time: 100, Root: root}))
+
+	type testCase struct {
+		Name            string
+		Conditions      string
+		ExpectedResult  *common.Hash
+		ExpectedError   types.Error
+		ExpectedMessage string
+		SetupMocks      func(m *mocksWrapper)
+	}
+
+	tooManySlots := map[common.Hash]common.Hash{}
+	for i := 0; i <= maxConditionalTxKnownSlots; i++ {
+		tooManySlots[common.BigToHash(big.NewInt(int64(i)))] = common.Hash{}
+	}
+	tooManySlotsConditions, err := json.Marshal(map[string]interface{}{"knownAccounts": map[common.Address]interface{}{account: tooManySlots}})
+	require.NoError(t, err)
+
+	// the db tx is rolled back when the conditions are not met
+	setupLastBlockMocks := func(m *mocksWrapper, dbTxEnd string) {
+		m.DbTx.On(dbTxEnd, context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("GetLastL2Block", context.Background(), m.DbTx).Return(lastBlock, nil).Once()
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "Send TX successfully",
+			Conditions:     `{"knownAccounts":{"0x0000000000000000000000000000000000000002":{"0x0000000000000000000000000000000000000000000000000000000000000001":"0x0000000000000000000000000000000000000000000000000000000000000002"}},"blockNumberMax":"0xb","timestampMin":"0x64"}`,
+			ExpectedResult: state.HashPtr(tx.Hash()),
+			SetupMocks: func(m *mocksWrapper) {
+				setupLastBlockMocks(m, "Commit")
+				m.State.On("GetStorageAt", context.Background(), account, slot.Big(), root).Return(big.NewInt(2), nil).Once()
+
+				blockNumberMax, timestampMin := uint64(11), uint64(100)
+				expectedConditions := &pool.TransactionConditions{
+					KnownAccounts:  map[common.Address]map[common.Hash]common.Hash{account: {slot: common.HexToHash("0x2")}},
+					BlockNumberMax: &blockNumberMax,
+					TimestampMin:   &timestampMin,
+				}
+				m.Pool.On("AddTxWithConditions", context.Background(), mock.IsType(ethTypes.Transaction{}), "", expectedConditions).Return(nil).Once()
+			},
+		},
+		{
+			Name:            "Storage slot holds another value",
+			Conditions:      `{"knownAccounts":{"0x0000000000000000000000000000000000000002":{"0x0000000000000000000000000000000000000000000000000000000000000001":"0x0000000000000000000000000000000000000000000000000000000000000002"}}}`,
+			ExpectedError:   types.NewRPCError(types.DefaultErrorCode, ""),
+			ExpectedMessage: "transaction conditions not met: storage slot 0x0000000000000000000000000000000000000000000000000000000000000001 of account 0x0000000000000000000000000000000000000002 holds 0x0000000000000000000000000000000000000000000000000000000000000003 instead of 0x0000000000000000000000000000000000000000000000000000000000000002",
+			SetupMocks: func(m *mocksWrapper) {
+				setupLastBlockMocks(m, "Rollback")
+				m.State.On("GetStorageAt", context.Background(), account, slot.Big(), root).Return(big.NewInt(3), nil).Once()
+			},
+		},
+		{
+			Name:            "Max block number already passed",
+			Conditions:      `{"blockNumberMax":"0xa"}`,
+			ExpectedError:   types.NewRPCError(types.DefaultErrorCode, ""),
+			ExpectedMessage: "transaction conditions not met: the max block number 10 is not greater than the latest block number 10",
+			SetupMocks:      func(m *mocksWrapper) { setupLastBlockMocks(m, "Rollback") },
+		},
+		{
+			Name:            "Max timestamp already passed",
+			Conditions:      `{"timestampMax":"0x63"}`,
+			ExpectedError:   types.NewRPCError(types.DefaultErrorCode, ""),
+			ExpectedMessage: "transaction conditions not met: the max timestamp 99 is lower than the latest block timestamp 100",
+			SetupMocks:      func(m *mocksWrapper) { setupLastBlockMocks(m, "Rollback") },
+		},
+		{
+			Name:            "Too many storage slots",
+			Conditions:      string(tooManySlotsConditions),
+			ExpectedError:   types.NewRPCError(types.InvalidParamsErrorCode, ""),
+			ExpectedMessage: "the conditions can't check more than 32 storage slots",
+			SetupMocks:      func(m *mocksWrapper) {},
+		},
+		{
+			Name:            "Invalid block number range",
+			Conditions:      `{"blockNumberMin":"0xc","blockNumberMax":"0xb"}`,
+			ExpectedError:   types.NewRPCError(types.InvalidParamsErrorCode, ""),
+			ExpectedMessage: "invalid block number range",
+			SetupMocks:      func(m *mocksWrapper) {},
+		},
+		{
+			Name:          "Storage root condition",
+			Conditions:    `{"knownAccounts":{"0x0000000000000000000000000000000000000002":"0x0000000000000000000000000000000000000000000000000000000000000001"}}`,
+			ExpectedError: types.NewRPCError(types.InvalidParamsErrorCode, ""),
+			SetupMocks:    func(m *mocksWrapper) {},
+		},
+		{
+			Name:            "Failed to add TX to the pool",
+			Conditions:      `{}`,
+			ExpectedError:   types.NewRPCError(types.DefaultErrorCode, ""),
+			ExpectedMessage: "nonce too low",
+			SetupMocks: func(m *mocksWrapper) {
+				setupLastBlockMocks(m, "Commit")
+				m.Pool.On("AddTxWithConditions", context.Background(), mock.IsType(ethTypes.Transaction{}), "", &pool.TransactionConditions{}).Return(errors.New("nonce too low")).Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("eth_sendRawTransactionConditional", input, json.RawMessage(tc.Conditions))
+			require.NoError(t, err)
+
+			if tc.ExpectedResult != nil {
+				require.Nil(t, res.Error)
+				var result common.Hash
+				require.NoError(t, json.Unmarshal(res.Result, &result))
+				assert.Equal(t, *tc.ExpectedResult, result)
+			}
+
+			if tc.ExpectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				if tc.ExpectedMessage != "" {
+					assert.Equal(t, tc.ExpectedMessage, res.Error.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestSendRawTransactionConditionalForNonSequencerNode(t *testing.T) {
+	sequencerServer, sequencerMocks, _ := newSequencerMockedServer(t)
+	defer sequencerServer.Stop()
+	nonSequencerServer, nonSequencerMocks, _ := newNonSequencerMockedServer(t, sequencerServer.ServerURL)
+	defer nonSequencerServer.Stop()
+
+	tx := ethTypes.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), uint64(1), big.NewInt(1), []byte{})
+	txBinary, err := tx.MarshalBinary()
+	require.NoError(t, err)
+
+	lastBlock := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(10), Time: 100}))
+	// both nodes check the conditions
+	for _, m := range []*mocksWrapper{nonSequencerMocks, sequencerMocks} {
+		m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("GetLastL2Block", context.Background(), m.DbTx).Return(lastBlock, nil).Once()
+	}
+	blockNumberMax := uint64(11)
+	sequencerMocks.Pool.
+		On("AddTxWithConditions", context.Background(), mock.IsType(ethTypes.Transaction{}), "", &pool.TransactionConditions{BlockNumberMax: &blockNumberMax}).
+		Return(nil).
+		Once()
+
+	res, err := nonSequencerServer.JSONRPCCall("eth_sendRawTransactionConditional", hex.EncodeToHex(txBinary), json.RawMessage(`{"blockNumberMax":"0xb"}`))
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	var result common.Hash
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	assert.Equal(t, tx.Hash(), result)
+}
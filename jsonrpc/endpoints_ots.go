@@ -0,0 +1,721 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// otsAPILevel is the level of the ots api implemented, Otterscan checks it
+	// to know if the node supports all the methods it needs
+	otsAPILevel = 8
+
+	// otsMethodSelectorLength is the length of the input of the transactions
+	// returned by ots_getBlockTransactions
+	otsMethodSelectorLength = 4
+
+	// callTracer is the tracer that builds the tree of calls of a transaction
+	callTracer = "callTracer"
+)
+
+// types of the internal operations returned by ots_getInternalOperations
+const (
+	otsOperationTransfer     = 0
+	otsOperationSelfDestruct = 1
+	otsOperationCreate       = 2
+	otsOperationCreate2      = 3
+)
+
+// onlyTopCallTracerConfig avoids tracing the internal calls when only the
+// result of the transaction is needed
+var onlyTopCallTracerConfig = json.RawMessage(`{"onlyTopCall":true}`)
+
+// OtsEndpoints is the ots jsonrpc endpoint, it implements the methods
+// needed by the Otterscan block explorer
+type OtsEndpoints struct {
+	cfg      Config
+	state    types.StateInterface
+	etherman types.EthermanInterface
+	txMan    DBTxManager
+}
+
+// NewOtsEndpoints returns OtsEndpoints
+func NewOtsEndpoints(cfg Config, state types.StateInterface, etherman types.EthermanInterface) *OtsEndpoints {
+	return &OtsEndpoints{
+		cfg:      cfg,
+		state:    state,
+		etherman: etherman,
+	}
+}
+
+type otsInternalOperation struct {
+	Type  int            `json:"type"`
+	From  common.Address `json:"from"`
+	To    common.Address `json:"to"`
+	Value types.ArgBig   `json:"value"`
+}
+
+// otsCallFrame has the fields of the frames of the call tracer needed
+// to find the internal operations of a transaction
+type otsCallFrame struct {
+	Type   string          `json:"type"`
+	From   common.Address  `json:"from"`
+	To     *common.Address `json:"to"`
+	Value  *types.ArgBig   `json:"value"`
+	Input  types.ArgBytes  `json:"input"`
+	Output types.ArgBytes  `json:"output"`
+	Calls  []otsCallFrame  `json:"calls"`
+}
+
+// otsTraceEntry is a call of the list returned by ots_traceTransaction
+type otsTraceEntry struct {
+	Type   string          `json:"type"`
+	Depth  int             `json:"depth"`
+	From   common.Address  `json:"from"`
+	To     *common.Address `json:"to"`
+	Value  *types.ArgBig   `json:"value"`
+	Input  types.ArgBytes  `json:"input"`
+	Output types.ArgBytes  `json:"output"`
+}
+
+type otsIssuance struct {
+	BlockReward types.ArgBig `json:"blockReward"`
+	UncleReward types.ArgBig `json:"uncleReward"`
+	Issuance    types.ArgBig `json:"issuance"`
+}
+
+// otsBlock is the block returned by ots_getBlockDetails, the transactions
+// and the logs bloom are removed and the transaction count is added
+type otsBlock struct {
+	*types.Block
+	Transactions     []types.TransactionOrHash `json:"transactions,omitempty"`
+	LogsBloom        *ethTypes.Bloom           `json:"logsBloom"`
+	TransactionCount types.ArgUint64           `json:"transactionCount"`
+}
+
+// otsBlockTransactionsReceipt is a receipt returned by ots_getBlockTransactions,
+// the logs and the logs bloom are removed
+type otsBlockTransactionsReceipt struct {
+	types.Receipt
+	Logs      []*ethTypes.Log `json:"logs"`
+	LogsBloom *ethTypes.Bloom `json:"logsBloom"`
+}
+
+type otsBlockTransactions struct {
+	FullBlock otsBlock                      `json:"fullblock"`
+	Receipts  []otsBlockTransactionsReceipt `json:"receipts"`
+}
+
+type otsBlockDetails struct {
+	Block     otsBlock     `json:"block"`
+	Issuance  otsIssuance  `json:"issuance"`
+	TotalFees types.ArgBig `json:"totalFees"`
+}
+
+// otsReceipt is a receipt along with the timestamp of its block
+type otsReceipt struct {
+	types.Receipt
+	Timestamp uint64 `json:"timestamp"`
+}
+
+type otsTransactionsPage struct {
+	Txs       []*types.Transaction `json:"txs"`
+	Receipts  []otsReceipt         `json:"receipts"`
+	FirstPage bool                 `json:"firstPage"`
+	LastPage  bool                 `json:"lastPage"`
+}
+
+type otsContractCreator struct {
+	Hash    common.Hash    `json:"hash"`
+	Creator common.Address `json:"creator"`
+}
+
+// GetApiLevel returns the level of the ots api implemented by the node
+func (o *OtsEndpoints) GetApiLevel() (interface{}, types.Error) { //nolint:revive
+	return otsAPILevel, nil
+}
+
+// HasCode returns if the given address has code at the given block
+func (o *OtsEndpoints) HasCode(address types.ArgAddress, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		block, rpcErr := getBlockByArg(ctx, o.state, o.etherman, blockArg, false, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		code, err := o.state.GetCode(ctx, address.Address(), block.Root())
+		if errors.Is(err, state.ErrNotFound) {
+			return false, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get code", err, true)
+		}
+
+		return len(code) > 0, nil
+	})
+}
+
+// GetInternalOperations returns the value transfers, contract creations and
+// self destructs made by the internal calls of the given transaction
+func (o *OtsEndpoints) GetInternalOperations(hash types.ArgHash) (interface{}, types.Error) {
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		frame, err := o.traceCalls(ctx, hash.Hash(), nil, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			errMsg := fmt.Sprintf("failed to get trace: %v", err.Error())
+			return RPCErrorResponse(types.DefaultErrorCode, errMsg, nil, false)
+		}
+
+		operations := []otsInternalOperation{}
+		var walk func(calls []otsCallFrame)
+		walk = func(calls []otsCallFrame) {
+			for _, call := range calls {
+				if operation, ok := call.internalOperation(); ok {
+					operations = append(operations, operation)
+				}
+				walk(call.Calls)
+			}
+		}
+		walk(frame.Calls)
+
+		return operations, nil
+	})
+}
+
+// TraceTransaction returns the calls made by the given transaction, in the
+// order they were made, along with their depth
+func (o *OtsEndpoints) TraceTransaction(hash types.ArgHash) (interface{}, types.Error) {
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		frame, err := o.traceCalls(ctx, hash.Hash(), nil, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			errMsg := fmt.Sprintf("failed to get trace: %v", err.Error())
+			return RPCErrorResponse(types.DefaultErrorCode, errMsg, nil, false)
+		}
+
+		entries := []otsTraceEntry{}
+		var walk func(call otsCallFrame, depth int)
+		walk = func(call otsCallFrame, depth int) {
+			entries = append(entries, otsTraceEntry{
+				Type:   call.Type,
+				Depth:  depth,
+				From:   call.From,
+				To:     call.To,
+				Value:  call.Value,
+				Input:  call.Input,
+				Output: call.Output,
+			})
+			for _, internalCall := range call.Calls {
+				walk(internalCall, depth+1)
+			}
+		}
+		walk(*frame, 0)
+
+		return entries, nil
+	})
+}
+
+// GetTransactionError returns the revert data of the given transaction,
+// it's empty if the transaction succeeded
+func (o *OtsEndpoints) GetTransactionError(hash types.ArgHash) (interface{}, types.Error) {
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		receipt, err := o.state.GetTransactionReceipt(ctx, hash.Hash(), dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get tx receipt from state", err, true)
+		}
+
+		if receipt.Status == ethTypes.ReceiptStatusSuccessful {
+			return types.ArgBytes{}, nil
+		}
+
+		tracer := callTracer
+		traceConfig := state.TraceConfig{
+			Tracer:       &tracer,
+			TracerConfig: onlyTopCallTracerConfig,
+		}
+		result, err := o.state.DebugTransaction(ctx, hash.Hash(), traceConfig, dbTx)
+		if err != nil {
+			errMsg := fmt.Sprintf("failed to get trace: %v", err.Error())
+			return RPCErrorResponse(types.DefaultErrorCode, errMsg, nil, false)
+		}
+
+		return types.ArgBytes(result.ReturnValue), nil
+	})
+}
+
+// GetBlockDetails returns the given block without its transactions, along
+// with the fees paid by them. There are no block rewards in the L2 so the
+// issuance is always zero
+func (o *OtsEndpoints) GetBlockDetails(number types.BlockNumber) (interface{}, types.Error) {
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		blockNumber, rpcErr := number.GetNumericBlockNumber(ctx, o.state, o.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		block, err := o.state.GetL2BlockByNumber(ctx, blockNumber, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load block from state by number %v", blockNumber), err, true)
+		}
+
+		receipts, err := o.state.GetReceiptsByL2BlockNumber(ctx, blockNumber, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load receipts for block %v", blockNumber), err, true)
+		}
+
+		txs := make(map[common.Hash]*ethTypes.Transaction, len(block.Transactions()))
+		for _, tx := range block.Transactions() {
+			txs[tx.Hash()] = tx
+		}
+
+		totalFees := big.NewInt(0)
+		for _, receipt := range receipts {
+			gasPrice := receipt.EffectiveGasPrice
+			if gasPrice == nil {
+				tx, found := txs[receipt.TxHash]
+				if !found {
+					continue
+				}
+				gasPrice = tx.GasPrice()
+			}
+			fee := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(receipt.GasUsed))
+			totalFees.Add(totalFees, fee)
+		}
+
+		rpcBlock, err := types.NewBlock(block, nil, false, false)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "couldn't build block response", err, true)
+		}
+
+		return otsBlockDetails{
+			Block: otsBlock{
+				Block:            rpcBlock,
+				TransactionCount: types.ArgUint64(len(block.Transactions())),
+			},
+			TotalFees: types.ArgBig(*totalFees),
+		}, nil
+	})
+}
+
+// GetBlockTransactions returns a page of the transactions of the given block
+// along with their receipts, the pages are counted from the last transaction.
+// The input of the transactions is cropped to the method selector and the
+// logs of the receipts are removed
+func (o *OtsEndpoints) GetBlockTransactions(number types.BlockNumber, pageNumber uint64, pageSize uint64) (interface{}, types.Error) {
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		blockNumber, rpcErr := number.GetNumericBlockNumber(ctx, o.state, o.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		block, err := o.state.GetL2BlockByNumber(ctx, blockNumber, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load block from state by number %v", blockNumber), err, true)
+		}
+
+		receipts, err := o.state.GetReceiptsByL2BlockNumber(ctx, blockNumber, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load receipts for block %v", blockNumber), err, true)
+		}
+		receiptsByHash := make(map[common.Hash]*ethTypes.Receipt, len(receipts))
+		for _, receipt := range receipts {
+			receiptsByHash[receipt.TxHash] = receipt
+		}
+
+		rpcBlock, err := types.NewBlock(block, nil, false, false)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "couldn't build block response", err, true)
+		}
+
+		txs := block.Transactions()
+		pageEnd := uint64(len(txs))
+		if pageNumber*pageSize < pageEnd {
+			pageEnd -= pageNumber * pageSize
+		} else {
+			pageEnd = 0
+		}
+		var pageStart uint64
+		if pageSize < pageEnd {
+			pageStart = pageEnd - pageSize
+		}
+
+		result := otsBlockTransactions{
+			FullBlock: otsBlock{
+				Block:            rpcBlock,
+				Transactions:     make([]types.TransactionOrHash, 0, pageEnd-pageStart),
+				TransactionCount: types.ArgUint64(len(txs)),
+			},
+			Receipts: make([]otsBlockTransactionsReceipt, 0, pageEnd-pageStart),
+		}
+		for _, tx := range txs[pageStart:pageEnd] {
+			receipt, found := receiptsByHash[tx.Hash()]
+			if !found {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load receipt for tx %v", tx.Hash().String()), nil, true)
+			}
+
+			rpcTx, err := types.NewTransaction(*tx, receipt, false)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, "failed to build transaction response", err, true)
+			}
+			if len(rpcTx.Input) > otsMethodSelectorLength {
+				rpcTx.Input = rpcTx.Input[:otsMethodSelectorLength]
+			}
+
+			rpcReceipt, err := types.NewReceipt(*tx, receipt)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, "failed to build the receipt response", err, true)
+			}
+
+			result.FullBlock.Transactions = append(result.FullBlock.Transactions, types.TransactionOrHash{Tx: rpcTx})
+			result.Receipts = append(result.Receipts, otsBlockTransactionsReceipt{Receipt: rpcReceipt})
+		}
+
+		return result, nil
+	})
+}
+
+// SearchTransactionsBefore returns the transactions sent by, sent to or
+// creating the given address in the blocks before the given block, from the
+// most recent one. The block number zero means searching from the last block.
+// Only the top level transactions are indexed, the internal calls to the address aren't found.
+// All the transactions of a block are returned, so the page can exceed the page size.
+// The transactions stored before the address index was created are found
+// once the node has backfilled the index
+func (o *OtsEndpoints) SearchTransactionsBefore(address types.ArgAddress, blockNumber uint64, pageSize uint64) (interface{}, types.Error) {
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		firstPage := blockNumber == 0
+		if firstPage {
+			lastBlockNumber, err := o.state.GetLastL2BlockNumber(ctx, dbTx)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, "failed to get the last block number from state", err, true)
+			}
+			blockNumber = lastBlockNumber + 1
+		}
+
+		txHashes, hasMore, err := o.state.GetL2TxHashesByAddressBefore(ctx, address.Address(), blockNumber, pageSize, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get transactions from state", err, true)
+		}
+
+		page := &otsTransactionsPage{FirstPage: firstPage, LastPage: !hasMore}
+		if rpcErr := o.fillTransactionsPage(ctx, page, txHashes, dbTx); rpcErr != nil {
+			return nil, rpcErr
+		}
+		return page, nil
+	})
+}
+
+// SearchTransactionsAfter returns the transactions sent by, sent to or
+// creating the given address in the blocks after the given block, from the
+// oldest one, but sorted from the most recent one like the other pages.
+// The block number zero means searching from the first block.
+// Only the top level transactions are indexed, the internal calls to the address aren't found.
+// All the transactions of a block are returned, so the page can exceed the page size.
+// The transactions stored before the address index was created are found
+// once the node has backfilled the index
+func (o *OtsEndpoints) SearchTransactionsAfter(address types.ArgAddress, blockNumber uint64, pageSize uint64) (interface{}, types.Error) {
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		txHashes, hasMore, err := o.state.GetL2TxHashesByAddressAfter(ctx, address.Address(), blockNumber, pageSize, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get transactions from state", err, true)
+		}
+
+		// the pages are always sorted from the most recent transaction
+		for i, j := 0, len(txHashes)-1; i < j; i, j = i+1, j-1 {
+			txHashes[i], txHashes[j] = txHashes[j], txHashes[i]
+		}
+
+		page := &otsTransactionsPage{FirstPage: !hasMore, LastPage: blockNumber == 0}
+		if rpcErr := o.fillTransactionsPage(ctx, page, txHashes, dbTx); rpcErr != nil {
+			return nil, rpcErr
+		}
+		return page, nil
+	})
+}
+
+// GetTransactionBySenderAndNonce returns the hash of the transaction sent by
+// the given address with the given nonce, or null if it doesn't exist. The
+// block of the transaction is found by looking for the first block where the
+// nonce of the sender is greater than the given nonce
+func (o *OtsEndpoints) GetTransactionBySenderAndNonce(sender types.ArgAddress, nonce uint64) (interface{}, types.Error) {
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		lastBlockNumber, err := o.state.GetLastL2BlockNumber(ctx, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get the last block number from state", err, true)
+		}
+
+		lastNonce, rpcErr := o.nonceAt(ctx, sender.Address(), lastBlockNumber, dbTx)
+		if rpcErr != nil || lastNonce <= nonce {
+			return nil, rpcErr
+		}
+
+		low, high := uint64(0), lastBlockNumber
+		for low < high {
+			middle := low + (high-low)/2 //nolint:gomnd
+			middleNonce, rpcErr := o.nonceAt(ctx, sender.Address(), middle, dbTx)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+			if middleNonce > nonce {
+				high = middle
+			} else {
+				low = middle + 1
+			}
+		}
+
+		block, err := o.state.GetL2BlockByNumber(ctx, low, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load block from state by number %v", low), err, true)
+		}
+		for _, tx := range block.Transactions() {
+			if tx.Nonce() != nonce {
+				continue
+			}
+			txSender, err := state.GetSender(*tx)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, "failed to get tx sender", err, true)
+			}
+			if txSender == sender.Address() {
+				return tx.Hash(), nil
+			}
+		}
+
+		// the nonce of the genesis accounts isn't set by transactions
+		return nil, nil
+	})
+}
+
+// GetContractCreator returns the transaction that created the given contract
+// and the address that created it, or null if the address isn't a contract.
+// The contracts created by internal calls are found by looking for the first
+// block where the contract has code and tracing its transactions
+func (o *OtsEndpoints) GetContractCreator(address types.ArgAddress) (interface{}, types.Error) {
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		lastBlockNumber, err := o.state.GetLastL2BlockNumber(ctx, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get the last block number from state", err, true)
+		}
+
+		isContract, rpcErr := o.hasCode(ctx, address.Address(), lastBlockNumber, dbTx)
+		if rpcErr != nil || !isContract {
+			return nil, rpcErr
+		}
+
+		txHash, err := o.state.GetTxHashByContractAddress(ctx, address.Address(), dbTx)
+		if err == nil {
+			tx, err := o.state.GetTransactionByHash(ctx, txHash, dbTx)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, "failed to get tx from state", err, true)
+			}
+			creator, err := state.GetSender(*tx)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, "failed to get tx sender", err, true)
+			}
+			return otsContractCreator{Hash: txHash, Creator: creator}, nil
+		} else if !errors.Is(err, state.ErrNotFound) {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get the contract creation tx from state", err, true)
+		}
+
+		// the contract was created by an internal call, look for the first block
+		// where it has code, the contracts of the genesis have no creator
+		low, high := uint64(0), lastBlockNumber
+		for low < high {
+			middle := low + (high-low)/2 //nolint:gomnd
+			isContract, rpcErr := o.hasCode(ctx, address.Address(), middle, dbTx)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+			if isContract {
+				high = middle
+			} else {
+				low = middle + 1
+			}
+		}
+		if low == 0 {
+			return nil, nil
+		}
+
+		txs, err := o.state.GetTxsByBlockNumber(ctx, low, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load txs of block %v", low), err, true)
+		}
+		for _, tx := range txs {
+			frame, err := o.traceCalls(ctx, tx.Hash(), nil, dbTx)
+			if err != nil {
+				errMsg := fmt.Sprintf("failed to get trace for transaction %v: %v", tx.Hash().String(), err.Error())
+				return RPCErrorResponse(types.DefaultErrorCode, errMsg, nil, false)
+			}
+			if creator, found := frame.findCreator(address.Address()); found {
+				return otsContractCreator{Hash: tx.Hash(), Creator: creator}, nil
+			}
+		}
+
+		return nil, nil
+	})
+}
+
+// fillTransactionsPage adds the transactions with the given hashes and
+// their receipts to the page
+func (o *OtsEndpoints) fillTransactionsPage(ctx context.Context, page *otsTransactionsPage, txHashes []common.Hash, dbTx pgx.Tx) types.Error {
+	page.Txs = make([]*types.Transaction, 0, len(txHashes))
+	page.Receipts = make([]otsReceipt, 0, len(txHashes))
+	timestamps := map[uint64]uint64{}
+	for _, txHash := range txHashes {
+		tx, err := o.state.GetTransactionByHash(ctx, txHash, dbTx)
+		if err != nil {
+			_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, "failed to get tx from state", err, true)
+			return rpcErr
+		}
+
+		receipt, err := o.state.GetTransactionReceipt(ctx, txHash, dbTx)
+		if err != nil {
+			_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, "failed to get tx receipt from state", err, true)
+			return rpcErr
+		}
+
+		blockNumber := receipt.BlockNumber.Uint64()
+		timestamp, found := timestamps[blockNumber]
+		if !found {
+			header, err := o.state.GetL2BlockHeaderByNumber(ctx, blockNumber, dbTx)
+			if err != nil {
+				_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load block header from state by number %v", blockNumber), err, true)
+				return rpcErr
+			}
+			timestamp = header.Time
+			timestamps[blockNumber] = timestamp
+		}
+
+		rpcTx, err := types.NewTransaction(*tx, receipt, false)
+		if err != nil {
+			_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, "failed to build transaction response", err, true)
+			return rpcErr
+		}
+
+		rpcReceipt, err := types.NewReceipt(*tx, receipt)
+		if err != nil {
+			_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, "failed to build the receipt response", err, true)
+			return rpcErr
+		}
+
+		page.Txs = append(page.Txs, rpcTx)
+		page.Receipts = append(page.Receipts, otsReceipt{Receipt: rpcReceipt, Timestamp: timestamp})
+	}
+	return nil
+}
+
+// hasCode returns if the given address has code at the given block
+func (o *OtsEndpoints) hasCode(ctx context.Context, address common.Address, blockNumber uint64, dbTx pgx.Tx) (bool, types.Error) {
+	header, err := o.state.GetL2BlockHeaderByNumber(ctx, blockNumber, dbTx)
+	if err != nil {
+		_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load block header from state by number %v", blockNumber), err, true)
+		return false, rpcErr
+	}
+
+	code, err := o.state.GetCode(ctx, address, header.Root)
+	if errors.Is(err, state.ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, "failed to get code", err, true)
+		return false, rpcErr
+	}
+
+	return len(code) > 0, nil
+}
+
+// nonceAt returns the nonce of the given address at the given block
+func (o *OtsEndpoints) nonceAt(ctx context.Context, address common.Address, blockNumber uint64, dbTx pgx.Tx) (uint64, types.Error) {
+	header, err := o.state.GetL2BlockHeaderByNumber(ctx, blockNumber, dbTx)
+	if err != nil {
+		_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load block header from state by number %v", blockNumber), err, true)
+		return 0, rpcErr
+	}
+
+	nonce, err := o.state.GetNonce(ctx, address, header.Root)
+	if errors.Is(err, state.ErrNotFound) {
+		return 0, nil
+	} else if err != nil {
+		_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, "failed to get nonce", err, true)
+		return 0, rpcErr
+	}
+
+	return nonce, nil
+}
+
+// traceCalls traces the transaction with the call tracer and returns
+// its top call frame
+func (o *OtsEndpoints) traceCalls(ctx context.Context, hash common.Hash, tracerConfig json.RawMessage, dbTx pgx.Tx) (*otsCallFrame, error) {
+	tracer := callTracer
+	traceConfig := state.TraceConfig{
+		Tracer:       &tracer,
+		TracerConfig: tracerConfig,
+	}
+	result, err := o.state.DebugTransaction(ctx, hash, traceConfig, dbTx)
+	if err != nil {
+		return nil, err
+	}
+
+	var frame otsCallFrame
+	if err := json.Unmarshal(result.TraceResult, &frame); err != nil {
+		return nil, fmt.Errorf("failed to parse the call trace: %w", err)
+	}
+	return &frame, nil
+}
+
+// internalOperation returns the internal operation made by the call, if any
+func (f otsCallFrame) internalOperation() (otsInternalOperation, bool) {
+	operation := otsInternalOperation{From: f.From}
+	if f.To != nil {
+		operation.To = *f.To
+	}
+	if f.Value != nil {
+		operation.Value = *f.Value
+	}
+
+	switch f.Type {
+	case "CALL":
+		value := big.Int(operation.Value)
+		if value.Sign() == 0 {
+			return otsInternalOperation{}, false
+		}
+		operation.Type = otsOperationTransfer
+	case "SELFDESTRUCT":
+		operation.Type = otsOperationSelfDestruct
+	case "CREATE":
+		operation.Type = otsOperationCreate
+	case "CREATE2":
+		operation.Type = otsOperationCreate2
+	default:
+		return otsInternalOperation{}, false
+	}
+	return operation, true
+}
+
+// findCreator returns the address that created the given contract in
+// this call or its internal calls
+func (f otsCallFrame) findCreator(contract common.Address) (common.Address, bool) {
+	if (f.Type == "CREATE" || f.Type == "CREATE2") && f.To != nil && *f.To == contract {
+		return f.From, true
+	}
+	for _, call := range f.Calls {
+		if creator, found := call.findCreator(contract); found {
+			return creator, true
+		}
+	}
+	return common.Address{}, false
+}
@@ -0,0 +1,608 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOtsGetApiLevel(t *testing.T) {
+	s, _, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	res, err := s.JSONRPCCall("ots_getApiLevel")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.Equal(t, "8", string(res.Result))
+}
+
+func TestOtsGetInternalOperations(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	tracer := callTracer
+	traceCfg := state.TraceConfig{Tracer: &tracer}
+	txHash := common.HexToHash("0x1")
+	addrA, addrB, addrC, addrD := common.HexToAddress("0xa"), common.HexToAddress("0xb"), common.HexToAddress("0xc"), common.HexToAddress("0xd")
+
+	// A calls B, which sends value to C that creates D, calls A without value and self destructs
+	trace := fmt.Sprintf(`{"type":"CALL","from":"%[1]s","to":"%[2]s","value":"0x5","calls":[
+		{"type":"CALL","from":"%[2]s","to":"%[3]s","value":"0x1","calls":[
+			{"type":"CREATE2","from":"%[3]s","to":"%[4]s","value":"0x0"}
+		]},
+		{"type":"CALL","from":"%[2]s","to":"%[1]s","value":"0x0"},
+		{"type":"STATICCALL","from":"%[2]s","to":"%[1]s"},
+		{"type":"SELFDESTRUCT","from":"%[2]s","to":"%[1]s","value":"0x4"}
+	]}`, addrA.String(), addrB.String(), addrC.String(), addrD.String())
+
+	t.Run("internal operations of the tx", func(t *testing.T) {
+		m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("DebugTransaction", context.Background(), txHash, traceCfg, m.DbTx).
+			Return(&runtime.ExecutionResult{TraceResult: json.RawMessage(trace)}, nil).Once()
+
+		res, err := s.JSONRPCCall("ots_getInternalOperations", txHash.String())
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+
+		expected := fmt.Sprintf(`[
+			{"type":0,"from":"%[2]s","to":"%[3]s","value":"0x1"},
+			{"type":3,"from":"%[3]s","to":"%[4]s","value":"0x0"},
+			{"type":1,"from":"%[2]s","to":"%[1]s","value":"0x4"}
+		]`, addrA.String(), addrB.String(), addrC.String(), addrD.String())
+		assert.JSONEq(t, strings.ToLower(expected), string(res.Result))
+	})
+
+	t.Run("tx not found", func(t *testing.T) {
+		m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("DebugTransaction", context.Background(), txHash, traceCfg, m.DbTx).Return(nil, state.ErrNotFound).Once()
+
+		res, err := s.JSONRPCCall("ots_getInternalOperations", txHash.String())
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+		assert.Equal(t, "null", string(res.Result))
+	})
+}
+
+func TestOtsGetTransactionError(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	tracer := callTracer
+	traceCfg := state.TraceConfig{Tracer: &tracer, TracerConfig: onlyTopCallTracerConfig}
+	txHash := common.HexToHash("0x1")
+
+	type testCase struct {
+		Name           string
+		ExpectedResult string
+		SetupMocks     func(m *mocksWrapper)
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "tx succeeded",
+			ExpectedResult: `"0x"`,
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetTransactionReceipt", context.Background(), txHash, m.DbTx).
+					Return(&ethTypes.Receipt{Status: ethTypes.ReceiptStatusSuccessful}, nil).Once()
+			},
+		},
+		{
+			Name:           "tx reverted",
+			ExpectedResult: `"0x08c379a0"`,
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetTransactionReceipt", context.Background(), txHash, m.DbTx).
+					Return(&ethTypes.Receipt{Status: ethTypes.ReceiptStatusFailed}, nil).Once()
+				m.State.On("DebugTransaction", context.Background(), txHash, traceCfg, m.DbTx).
+					Return(&runtime.ExecutionResult{ReturnValue: common.Hex2Bytes("08c379a0")}, nil).Once()
+			},
+		},
+		{
+			Name:           "tx not found",
+			ExpectedResult: "null",
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetTransactionReceipt", context.Background(), txHash, m.DbTx).Return(nil, state.ErrNotFound).Once()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("ots_getTransactionError", txHash.String())
+			require.NoError(t, err)
+			require.Nil(t, res.Error)
+			assert.Equal(t, tc.ExpectedResult, string(res.Result))
+		})
+	}
+}
+
+func TestOtsGetBlockDetails(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	tx1 := ethTypes.NewTransaction(0, common.HexToAddress("0x3"), big.NewInt(0), 21000, big.NewInt(10), nil)
+	tx2 := ethTypes.NewTransaction(1, common.HexToAddress("0x4"), big.NewInt(0), 30000, big.NewInt(20), nil)
+	receipts := []*ethTypes.Receipt{
+		{TxHash: tx1.Hash(), GasUsed: 21000, EffectiveGasPrice: big.NewInt(5)},
+		{TxHash: tx2.Hash(), GasUsed: 25000},
+	}
+	block := state.NewL2Block(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(1)}), []*ethTypes.Transaction{tx1, tx2}, nil, receipts, &trie.StackTrie{})
+
+	t.Run("block details", func(t *testing.T) {
+		m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("GetL2BlockByNumber", context.Background(), uint64(1), m.DbTx).Return(block, nil).Once()
+		m.State.On("GetReceiptsByL2BlockNumber", context.Background(), uint64(1), m.DbTx).Return(receipts, nil).Once()
+
+		res, err := s.JSONRPCCall("ots_getBlockDetails", 1)
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+
+		var result map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(res.Result, &result))
+		// 21000 * 5 + 25000 * 20
+		assert.Equal(t, `"0x93b48"`, string(result["totalFees"]))
+		assert.JSONEq(t, `{"blockReward":"0x0","uncleReward":"0x0","issuance":"0x0"}`, string(result["issuance"]))
+
+		var rpcBlock map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(result["block"], &rpcBlock))
+		assert.Equal(t, `"0x2"`, string(rpcBlock["transactionCount"]))
+		assert.Equal(t, "null", string(rpcBlock["logsBloom"]))
+		assert.Equal(t, fmt.Sprintf(`"%s"`, block.Hash().String()), string(rpcBlock["hash"]))
+		assert.NotContains(t, rpcBlock, "transactions")
+	})
+
+	t.Run("block not found", func(t *testing.T) {
+		m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("GetL2BlockByNumber", context.Background(), uint64(2), m.DbTx).Return(nil, state.ErrNotFound).Once()
+
+		res, err := s.JSONRPCCall("ots_getBlockDetails", 2)
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+		assert.Equal(t, "null", string(res.Result))
+	})
+}
+
+func TestOtsSearchTransactions(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	privateKey, err := crypto.HexToECDSA("28b2b0318721be8c8339199172cd7cc8f5e273800a35616ec893083a4b32c02e")
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(privateKey.PublicKey)
+	signer := ethTypes.NewEIP155Signer(new(big.Int).SetUint64(chainID))
+
+	txs := []*ethTypes.Transaction{}
+	receipts := []*ethTypes.Receipt{}
+	for i := 0; i < 2; i++ {
+		to := common.HexToAddress("0x1")
+		tx, err := ethTypes.SignTx(ethTypes.NewTx(&ethTypes.LegacyTx{Nonce: uint64(i), To: &to, Gas: 21000, GasPrice: big.NewInt(1), Value: big.NewInt(0)}), signer, privateKey)
+		require.NoError(t, err)
+		txs = append(txs, tx)
+		receipts = append(receipts, &ethTypes.Receipt{TxHash: tx.Hash(), BlockNumber: big.NewInt(int64(i) + 1), Status: ethTypes.ReceiptStatusSuccessful})
+	}
+
+	setupTxMocks := func(m *mocksWrapper) {
+		for i, tx := range txs {
+			m.State.On("GetTransactionByHash", context.Background(), tx.Hash(), m.DbTx).Return(tx, nil).Once()
+			m.State.On("GetTransactionReceipt", context.Background(), tx.Hash(), m.DbTx).Return(receipts[i], nil).Once()
+			header := state.NewL2Header(&ethTypes.Header{Number: big.NewInt(int64(i) + 1), Time: uint64(i) + 100})
+			m.State.On("GetL2BlockHeaderByNumber", context.Background(), uint64(i)+1, m.DbTx).Return(header, nil).Once()
+		}
+	}
+
+	type page struct {
+		Txs       []types.Transaction `json:"txs"`
+		Receipts  []otsReceipt        `json:"receipts"`
+		FirstPage bool                `json:"firstPage"`
+		LastPage  bool                `json:"lastPage"`
+	}
+
+	checkPage := func(t *testing.T, res types.Response, firstPage, lastPage bool) {
+		require.Nil(t, res.Error)
+		var result page
+		require.NoError(t, json.Unmarshal(res.Result, &result))
+		assert.Equal(t, firstPage, result.FirstPage)
+		assert.Equal(t, lastPage, result.LastPage)
+
+		// the pages are sorted from the most recent tx
+		require.Len(t, result.Txs, 2)
+		require.Len(t, result.Receipts, 2)
+		for i, tx := range []*ethTypes.Transaction{txs[1], txs[0]} {
+			assert.Equal(t, tx.Hash(), result.Txs[i].Hash)
+			assert.Equal(t, sender, result.Txs[i].From)
+			assert.Equal(t, tx.Hash(), result.Receipts[i].TxHash)
+			assert.Equal(t, tx.Nonce()+100, result.Receipts[i].Timestamp)
+		}
+	}
+
+	t.Run("search before the last block", func(t *testing.T) {
+		m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(uint64(10), nil).Once()
+		m.State.On("GetL2TxHashesByAddressBefore", context.Background(), sender, uint64(11), uint64(25), m.DbTx).
+			Return([]common.Hash{txs[1].Hash(), txs[0].Hash()}, true, nil).Once()
+		setupTxMocks(m)
+
+		res, err := s.JSONRPCCall("ots_searchTransactionsBefore", sender.String(), 0, 25)
+		require.NoError(t, err)
+		checkPage(t, res, true, false)
+	})
+
+	t.Run("search after the first block", func(t *testing.T) {
+		m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("GetL2TxHashesByAddressAfter", context.Background(), sender, uint64(0), uint64(25), m.DbTx).
+			Return([]common.Hash{txs[0].Hash(), txs[1].Hash()}, false, nil).Once()
+		setupTxMocks(m)
+
+		res, err := s.JSONRPCCall("ots_searchTransactionsAfter", sender.String(), 0, 25)
+		require.NoError(t, err)
+		checkPage(t, res, true, true)
+	})
+
+}
+
+func TestOtsGetContractCreator(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	privateKey, err := crypto.HexToECDSA("28b2b0318721be8c8339199172cd7cc8f5e273800a35616ec893083a4b32c02e")
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(privateKey.PublicKey)
+	signer := ethTypes.NewEIP155Signer(new(big.Int).SetUint64(chainID))
+	tx, err := ethTypes.SignTx(ethTypes.NewTx(&ethTypes.LegacyTx{Gas: 21000, GasPrice: big.NewInt(1), Value: big.NewInt(0)}), signer, privateKey)
+	require.NoError(t, err)
+
+	contract := common.HexToAddress("0x123")
+	factory := common.HexToAddress("0x456")
+	tracer := callTracer
+	traceCfg := state.TraceConfig{Tracer: &tracer}
+
+	setupCodeMock := func(m *mocksWrapper, blockNumber uint64, code []byte) {
+		root := common.BigToHash(new(big.Int).SetUint64(blockNumber))
+		header := state.NewL2Header(&ethTypes.Header{Number: new(big.Int).SetUint64(blockNumber), Root: root})
+		m.State.On("GetL2BlockHeaderByNumber", context.Background(), blockNumber, m.DbTx).Return(header, nil).Once()
+		m.State.On("GetCode", context.Background(), contract, root).Return(code, nil).Once()
+	}
+
+	type testCase struct {
+		Name           string
+		ExpectedResult string
+		SetupMocks     func(m *mocksWrapper)
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "address is not a contract",
+			ExpectedResult: "null",
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(uint64(4), nil).Once()
+				setupCodeMock(m, 4, []byte{})
+			},
+		},
+		{
+			Name:           "contract deployed by a tx",
+			ExpectedResult: fmt.Sprintf(`{"hash":"%s","creator":"%s"}`, tx.Hash().String(), sender.String()),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(uint64(4), nil).Once()
+				setupCodeMock(m, 4, []byte{0x1})
+				m.State.On("GetTxHashByContractAddress", context.Background(), contract, m.DbTx).Return(tx.Hash(), nil).Once()
+				m.State.On("GetTransactionByHash", context.Background(), tx.Hash(), m.DbTx).Return(tx, nil).Once()
+			},
+		},
+		{
+			Name:           "contract created by an internal call",
+			ExpectedResult: fmt.Sprintf(`{"hash":"%s","creator":"%s"}`, tx.Hash().String(), factory.String()),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(uint64(4), nil).Once()
+				setupCodeMock(m, 4, []byte{0x1})
+				m.State.On("GetTxHashByContractAddress", context.Background(), contract, m.DbTx).Return(common.Hash{}, state.ErrNotFound).Once()
+				// the contract is created in the block 3
+				setupCodeMock(m, 2, []byte{})
+				setupCodeMock(m, 3, []byte{0x1})
+				m.State.On("GetTxsByBlockNumber", context.Background(), uint64(3), m.DbTx).Return([]*ethTypes.Transaction{tx}, nil).Once()
+				trace := fmt.Sprintf(`{"type":"CALL","from":"%s","to":"%s","calls":[{"type":"CREATE2","from":"%s","to":"%s"}]}`,
+					sender.String(), factory.String(), factory.String(), contract.String())
+				m.State.On("DebugTransaction", context.Background(), tx.Hash(), traceCfg, m.DbTx).
+					Return(&runtime.ExecutionResult{TraceResult: json.RawMessage(trace)}, nil).Once()
+			},
+		},
+		{
+			Name:           "contract of the genesis",
+			ExpectedResult: "null",
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(uint64(1), nil).Once()
+				setupCodeMock(m, 1, []byte{0x1})
+				m.State.On("GetTxHashByContractAddress", context.Background(), contract, m.DbTx).Return(common.Hash{}, state.ErrNotFound).Once()
+				setupCodeMock(m, 0, []byte{0x1})
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("ots_getContractCreator", contract.String())
+			require.NoError(t, err)
+			require.Nil(t, res.Error)
+			if tc.ExpectedResult == "null" {
+				assert.Equal(t, "null", string(res.Result))
+				return
+			}
+			assert.JSONEq(t, strings.ToLower(tc.ExpectedResult), string(res.Result))
+		})
+	}
+}
+
+func TestOtsHasCode(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	address := common.HexToAddress("0x123")
+	root := common.HexToHash("0x1")
+	block := state.NewL2Block(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(1), Root: root}), nil, nil, nil, &trie.StackTrie{})
+
+	type testCase struct {
+		Name           string
+		ExpectedResult string
+		SetupMocks     func(m *mocksWrapper)
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "address with code",
+			ExpectedResult: "true",
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL2BlockByNumber", context.Background(), uint64(1), m.DbTx).Return(block, nil).Once()
+				m.State.On("GetCode", context.Background(), address, root).Return([]byte{0x1}, nil).Once()
+			},
+		},
+		{
+			Name:           "address without code",
+			ExpectedResult: "false",
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL2BlockByNumber", context.Background(), uint64(1), m.DbTx).Return(block, nil).Once()
+				m.State.On("GetCode", context.Background(), address, root).Return(nil, state.ErrNotFound).Once()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("ots_hasCode", address.String(), "0x1")
+			require.NoError(t, err)
+			require.Nil(t, res.Error)
+			assert.Equal(t, tc.ExpectedResult, string(res.Result))
+		})
+	}
+}
+
+func TestOtsTraceTransaction(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	tracer := callTracer
+	traceCfg := state.TraceConfig{Tracer: &tracer}
+	txHash := common.HexToHash("0x1")
+	addrA, addrB, addrC := common.HexToAddress("0xa"), common.HexToAddress("0xb"), common.HexToAddress("0xc")
+
+	// A calls B, which calls C and then delegates to C
+	trace := fmt.Sprintf(`{"type":"CALL","from":"%[1]s","to":"%[2]s","value":"0x5","input":"0x01","output":"0x02","calls":[
+		{"type":"CALL","from":"%[2]s","to":"%[3]s","value":"0x1","input":"0x03","calls":[
+			{"type":"STATICCALL","from":"%[3]s","to":"%[1]s","input":"0x04","output":"0x05"}
+		]},
+		{"type":"DELEGATECALL","from":"%[2]s","to":"%[3]s","input":"0x06"}
+	]}`, addrA.String(), addrB.String(), addrC.String())
+
+	t.Run("calls of the tx", func(t *testing.T) {
+		m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("DebugTransaction", context.Background(), txHash, traceCfg, m.DbTx).
+			Return(&runtime.ExecutionResult{TraceResult: json.RawMessage(trace)}, nil).Once()
+
+		res, err := s.JSONRPCCall("ots_traceTransaction", txHash.String())
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+
+		expected := fmt.Sprintf(`[
+			{"type":"CALL","depth":0,"from":"%[1]s","to":"%[2]s","value":"0x5","input":"0x01","output":"0x02"},
+			{"type":"CALL","depth":1,"from":"%[2]s","to":"%[3]s","value":"0x1","input":"0x03","output":"0x"},
+			{"type":"STATICCALL","depth":2,"from":"%[3]s","to":"%[1]s","value":null,"input":"0x04","output":"0x05"},
+			{"type":"DELEGATECALL","depth":1,"from":"%[2]s","to":"%[3]s","value":null,"input":"0x06","output":"0x"}
+		]`, addrA.String(), addrB.String(), addrC.String())
+		assert.JSONEq(t, strings.ToLower(expected), strings.ToLower(string(res.Result)))
+	})
+
+	t.Run("tx not found", func(t *testing.T) {
+		m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("DebugTransaction", context.Background(), txHash, traceCfg, m.DbTx).Return(nil, state.ErrNotFound).Once()
+
+		res, err := s.JSONRPCCall("ots_traceTransaction", txHash.String())
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+		assert.Equal(t, "null", string(res.Result))
+	})
+}
+
+func TestOtsGetBlockTransactions(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	privateKey, err := crypto.HexToECDSA("28b2b0318721be8c8339199172cd7cc8f5e273800a35616ec893083a4b32c02e")
+	require.NoError(t, err)
+	signer := ethTypes.NewEIP155Signer(new(big.Int).SetUint64(chainID))
+
+	txs := []*ethTypes.Transaction{}
+	receipts := []*ethTypes.Receipt{}
+	for i := 0; i < 3; i++ {
+		to := common.HexToAddress("0x3")
+		tx, err := ethTypes.SignTx(ethTypes.NewTx(&ethTypes.LegacyTx{Nonce: uint64(i), To: &to, Gas: 30000, GasPrice: big.NewInt(10), Value: big.NewInt(0), Data: []byte{0x1, 0x2, 0x3, 0x4, 0x5, 0x6}}), signer, privateKey)
+		require.NoError(t, err)
+		txs = append(txs, tx)
+		receipts = append(receipts, &ethTypes.Receipt{
+			TxHash:      tx.Hash(),
+			GasUsed:     21000,
+			Logs:        []*ethTypes.Log{{Address: common.HexToAddress("0x3"), TxHash: tx.Hash()}},
+			BlockNumber: big.NewInt(1),
+		})
+	}
+	block := state.NewL2Block(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(1)}), txs, nil, receipts, &trie.StackTrie{})
+
+	type testCase struct {
+		Name          string
+		PageNumber    uint64
+		PageSize      uint64
+		ExpectedNonce []string
+	}
+
+	testCases := []testCase{
+		{Name: "first page", PageNumber: 0, PageSize: 2, ExpectedNonce: []string{"0x1", "0x2"}},
+		{Name: "last page", PageNumber: 1, PageSize: 2, ExpectedNonce: []string{"0x0"}},
+		{Name: "page out of range", PageNumber: 2, PageSize: 2, ExpectedNonce: []string{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+			m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+			m.State.On("GetL2BlockByNumber", context.Background(), uint64(1), m.DbTx).Return(block, nil).Once()
+			m.State.On("GetReceiptsByL2BlockNumber", context.Background(), uint64(1), m.DbTx).Return(receipts, nil).Once()
+
+			res, err := s.JSONRPCCall("ots_getBlockTransactions", 1, tc.PageNumber, tc.PageSize)
+			require.NoError(t, err)
+			require.Nil(t, res.Error)
+
+			var result struct {
+				FullBlock struct {
+					TransactionCount string                       `json:"transactionCount"`
+					LogsBloom        json.RawMessage              `json:"logsBloom"`
+					Transactions     []map[string]json.RawMessage `json:"transactions"`
+				} `json:"fullblock"`
+				Receipts []map[string]json.RawMessage `json:"receipts"`
+			}
+			require.NoError(t, json.Unmarshal(res.Result, &result))
+			assert.Equal(t, "0x3", result.FullBlock.TransactionCount)
+			assert.Equal(t, "null", string(result.FullBlock.LogsBloom))
+			require.Len(t, result.FullBlock.Transactions, len(tc.ExpectedNonce))
+			require.Len(t, result.Receipts, len(tc.ExpectedNonce))
+			for i, nonce := range tc.ExpectedNonce {
+				assert.Equal(t, fmt.Sprintf(`"%s"`, nonce), string(result.FullBlock.Transactions[i]["nonce"]))
+				assert.Equal(t, `"0x01020304"`, string(result.FullBlock.Transactions[i]["input"]))
+				assert.Equal(t, "null", string(result.Receipts[i]["logs"]))
+				assert.Equal(t, "null", string(result.Receipts[i]["logsBloom"]))
+				assert.Equal(t, string(result.FullBlock.Transactions[i]["hash"]), string(result.Receipts[i]["transactionHash"]))
+			}
+		})
+	}
+}
+
+func TestOtsGetTransactionBySenderAndNonce(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	privateKey, err := crypto.HexToECDSA("28b2b0318721be8c8339199172cd7cc8f5e273800a35616ec893083a4b32c02e")
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(privateKey.PublicKey)
+	signer := ethTypes.NewEIP155Signer(new(big.Int).SetUint64(chainID))
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	otherTx, err := ethTypes.SignTx(ethTypes.NewTx(&ethTypes.LegacyTx{Nonce: 1, Gas: 21000, GasPrice: big.NewInt(1), Value: big.NewInt(0)}), signer, otherKey)
+	require.NoError(t, err)
+	tx, err := ethTypes.SignTx(ethTypes.NewTx(&ethTypes.LegacyTx{Nonce: 1, Gas: 21000, GasPrice: big.NewInt(1), Value: big.NewInt(0)}), signer, privateKey)
+	require.NoError(t, err)
+
+	setupNonceMock := func(m *mocksWrapper, blockNumber uint64, nonce uint64) {
+		root := common.BigToHash(new(big.Int).SetUint64(blockNumber))
+		header := state.NewL2Header(&ethTypes.Header{Number: new(big.Int).SetUint64(blockNumber), Root: root})
+		m.State.On("GetL2BlockHeaderByNumber", context.Background(), blockNumber, m.DbTx).Return(header, nil).Once()
+		m.State.On("GetNonce", context.Background(), sender, root).Return(nonce, nil).Once()
+	}
+
+	type testCase struct {
+		Name           string
+		Nonce          uint64
+		ExpectedResult string
+		SetupMocks     func(m *mocksWrapper)
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "nonce not used yet",
+			Nonce:          2,
+			ExpectedResult: "null",
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(uint64(4), nil).Once()
+				setupNonceMock(m, 4, 2)
+			},
+		},
+		{
+			Name:           "tx found",
+			Nonce:          1,
+			ExpectedResult: fmt.Sprintf(`"%s"`, tx.Hash().String()),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(uint64(4), nil).Once()
+				setupNonceMock(m, 4, 2)
+				// the nonce 1 is used in the block 3
+				setupNonceMock(m, 2, 1)
+				setupNonceMock(m, 3, 2)
+				block := state.NewL2Block(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(3)}), []*ethTypes.Transaction{otherTx, tx}, nil, nil, &trie.StackTrie{})
+				m.State.On("GetL2BlockByNumber", context.Background(), uint64(3), m.DbTx).Return(block, nil).Once()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("ots_getTransactionBySenderAndNonce", sender.String(), tc.Nonce)
+			require.NoError(t, err)
+			require.Nil(t, res.Error)
+			assert.Equal(t, tc.ExpectedResult, string(res.Result))
+		})
+	}
+}
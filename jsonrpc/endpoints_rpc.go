@@ -0,0 +1,24 @@
+package jsonrpc
+
+import (
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+)
+
+// RPCEndpoints contains implementations for the "rpc" RPC endpoints, that
+// describe the server itself
+type RPCEndpoints struct {
+	handler *Handler
+}
+
+// NewRPCEndpoints returns RPCEndpoints
+func NewRPCEndpoints(handler *Handler) *RPCEndpoints {
+	return &RPCEndpoints{
+		handler: handler,
+	}
+}
+
+// Discover returns the OpenRPC document describing the methods of all the
+// apis enabled in the server
+func (r *RPCEndpoints) Discover() (interface{}, types.Error) {
+	return r.handler.openRPCDocument(), nil
+}
@@ -0,0 +1,117 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscover(t *testing.T) {
+	s, _, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	res, err := s.JSONRPCCall("rpc_discover")
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(1), res.ID)
+	assert.Equal(t, "2.0", res.JSONRPC)
+	require.Nil(t, res.Error)
+
+	var result OpenRPCDocument
+	err = json.Unmarshal(res.Result, &result)
+	require.NoError(t, err)
+
+	assert.Equal(t, openRPCVersion, result.OpenRPC)
+	assert.Equal(t, zkevm.Version, result.Info.Version)
+
+	methods := map[string]OpenRPCMethod{}
+	for _, method := range result.Methods {
+		methods[method.Name] = method
+	}
+	expectedMethodsCount := 0
+	for serviceName, service := range s.Server.handler.serviceMap {
+		for funcName := range service.funcMap {
+			assert.Contains(t, methods, serviceName+"_"+funcName)
+			expectedMethodsCount++
+		}
+	}
+	assert.Equal(t, expectedMethodsCount, len(result.Methods))
+
+	getBalance := methods["eth_getBalance"]
+	require.Len(t, getBalance.Params, 2)
+	assert.Equal(t, "address", getBalance.Params[0].Name)
+	assert.True(t, getBalance.Params[0].Required)
+	assert.Equal(t, "#/components/schemas/ArgAddress", getBalance.Params[0].Schema["$ref"])
+	assert.Equal(t, "block", getBalance.Params[1].Name)
+	assert.False(t, getBalance.Params[1].Required)
+	assert.Equal(t, "#/components/schemas/BlockNumberOrHash", getBalance.Params[1].Schema["$ref"])
+	assert.Equal(t, "#/components/schemas/ArgBig", getBalance.Result.Schema["$ref"])
+
+	// the parameters injected by the server aren't described
+	sendRawTransaction := methods["eth_sendRawTransaction"]
+	require.Len(t, sendRawTransaction.Params, 1)
+	assert.Equal(t, "string", sendRawTransaction.Params[0].Schema["type"])
+	subscribe := methods["eth_subscribe"]
+	require.Len(t, subscribe.Params, 2)
+	assert.Equal(t, "#/components/schemas/LogFilter", subscribe.Params[1].Schema["$ref"])
+
+	// the results whose format depends on the params accept any value
+	assert.Empty(t, methods["debug_traceTransaction"].Result.Schema)
+	assert.Equal(t, "#/components/schemas/statusResponse", methods["txpool_status"].Result.Schema["$ref"])
+
+	schemas := result.Components.Schemas
+	assert.Equal(t, hexAddressPattern, schemas["ArgAddress"]["pattern"])
+	assert.Equal(t, hexQuantityPattern, schemas["ArgUint64"]["pattern"])
+	assert.Contains(t, schemas, "TxArgs")
+	txArgsProperties, ok := schemas["TxArgs"]["properties"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"$ref": "#/components/schemas/Address"}, txArgsProperties["from"])
+	assert.Equal(t, map[string]interface{}{"$ref": "#/components/schemas/ArgUint64"}, txArgsProperties["gas"])
+}
+
+func TestNewOpenRPCDocument(t *testing.T) {
+	document := NewOpenRPCDocument([]Service{
+		{Name: APINet, Service: &NetEndpoints{}},
+		{Name: APIWeb3, Service: &Web3Endpoints{}},
+	})
+
+	names := []string{}
+	for _, method := range document.Methods {
+		names = append(names, method.Name)
+	}
+	assert.Equal(t, []string{"net_version", "rpc_discover", "web3_clientVersion", "web3_sha3"}, names)
+
+	sha3 := document.Methods[3]
+	require.Len(t, sha3.Params, 1)
+	assert.Equal(t, "quantity", sha3.Params[0].Name)
+	assert.Equal(t, OpenRPCSchema{"$ref": "#/components/schemas/ArgBig"}, sha3.Params[0].Schema)
+	assert.Equal(t, OpenRPCSchema{"$ref": "#/components/schemas/ArgBytes"}, sha3.Result.Schema)
+	assert.Equal(t, hexQuantityPattern, document.Components.Schemas["ArgBig"]["pattern"])
+}
+
+func TestOpenRPCResultTypes(t *testing.T) {
+	document := NewOpenRPCDocument([]Service{
+		{Name: APIEth, Service: &EthEndpoints{}},
+		{Name: APINet, Service: &NetEndpoints{}},
+		{Name: APIZKEVM, Service: &ZKEVMEndpoints{}},
+		{Name: APITxPool, Service: &TxPoolEndpoints{}},
+		{Name: APIDebug, Service: &DebugEndpoints{}},
+		{Name: APIWeb3, Service: &Web3Endpoints{}},
+		{Name: APITrace, Service: &TraceEndpoints{}},
+		{Name: APIOts, Service: &OtsEndpoints{}},
+	})
+
+	// every method declares its result type and every result type belongs
+	// to a registered method
+	methods := map[string]bool{}
+	for _, method := range document.Methods {
+		methods[method.Name] = true
+		assert.Contains(t, openRPCResultTypes, method.Name, "missing result type of %s", method.Name)
+	}
+	for name := range openRPCResultTypes {
+		assert.Contains(t, methods, name, "result type of unknown method %s", name)
+	}
+}
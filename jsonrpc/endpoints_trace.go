@@ -0,0 +1,265 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// flatCallTracer is the tracer that builds the traces in the parity format
+	flatCallTracer = "flatCallTracer"
+
+	// traceTypeTrace is the only trace type supported by trace_replayBlockTransactions
+	traceTypeTrace = "trace"
+)
+
+// flatCallTracerConfig converts the errors of the traces to the parity format
+var flatCallTracerConfig = json.RawMessage(`{"convertParityErrors":true}`)
+
+// TraceEndpoints is the trace jsonrpc endpoint, it implements the
+// Parity/OpenEthereum trace_* methods on top of the flat call tracer
+type TraceEndpoints struct {
+	cfg      Config
+	state    types.StateInterface
+	etherman types.EthermanInterface
+	txMan    DBTxManager
+}
+
+// NewTraceEndpoints returns TraceEndpoints
+func NewTraceEndpoints(cfg Config, state types.StateInterface, etherman types.EthermanInterface) *TraceEndpoints {
+	return &TraceEndpoints{
+		cfg:      cfg,
+		state:    state,
+		etherman: etherman,
+	}
+}
+
+type traceReplayTransactionResponse struct {
+	Output          types.ArgBytes    `json:"output"`
+	StateDiff       interface{}       `json:"stateDiff"`
+	Trace           []json.RawMessage `json:"trace"`
+	VMTrace         interface{}       `json:"vmTrace"`
+	TransactionHash common.Hash       `json:"transactionHash"`
+}
+
+// flatTraceAddresses are the addresses of a flat trace used to filter it
+type flatTraceAddresses struct {
+	Action struct {
+		From          *common.Address `json:"from"`
+		To            *common.Address `json:"to"`
+		Address       *common.Address `json:"address"`
+		RefundAddress *common.Address `json:"refundAddress"`
+	} `json:"action"`
+	Result *struct {
+		Address *common.Address `json:"address"`
+	} `json:"result"`
+}
+
+// fromTo returns the from and to addresses of the trace: the created contract
+// is the to address of a create and the refund address is the to address of
+// a self destruct
+func (a flatTraceAddresses) fromTo() (*common.Address, *common.Address) {
+	from, to := a.Action.From, a.Action.To
+	if from == nil {
+		from = a.Action.Address
+	}
+	if to == nil && a.Action.RefundAddress != nil {
+		to = a.Action.RefundAddress
+	} else if to == nil && a.Result != nil {
+		to = a.Result.Address
+	}
+	return from, to
+}
+
+// Transaction creates a response for trace_transaction request.
+// See https://openethereum.github.io/JSONRPC-trace-module#trace_transaction
+func (t *TraceEndpoints) Transaction(hash types.ArgHash) (interface{}, types.Error) {
+	return t.txMan.NewDbTxScope(t.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		traces, _, err := t.traceTransaction(ctx, hash.Hash(), dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			errMsg := fmt.Sprintf("failed to get trace: %v", err.Error())
+			return RPCErrorResponse(types.DefaultErrorCode, errMsg, nil, false)
+		}
+
+		return traces, nil
+	})
+}
+
+// Block creates a response for trace_block request.
+// See https://openethereum.github.io/JSONRPC-trace-module#trace_block
+func (t *TraceEndpoints) Block(number types.BlockNumber) (interface{}, types.Error) {
+	return t.txMan.NewDbTxScope(t.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		block, rpcErr := t.getBlockByNumber(ctx, number, dbTx)
+		if rpcErr != nil || block == nil {
+			return nil, rpcErr
+		}
+
+		traces := []json.RawMessage{}
+		for _, tx := range block.Transactions() {
+			txTraces, _, rpcErr := t.buildTraceTransaction(ctx, tx.Hash(), dbTx)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+			traces = append(traces, txTraces...)
+		}
+
+		return traces, nil
+	})
+}
+
+// ReplayBlockTransactions creates a response for trace_replayBlockTransactions request,
+// only the trace type "trace" is supported.
+// See https://openethereum.github.io/JSONRPC-trace-module#trace_replayblocktransactions
+func (t *TraceEndpoints) ReplayBlockTransactions(number types.BlockNumber, traceTypes []string) (interface{}, types.Error) {
+	return t.txMan.NewDbTxScope(t.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		includeTrace := false
+		for _, traceType := range traceTypes {
+			if traceType != traceTypeTrace {
+				errMsg := fmt.Sprintf("trace type %v is not supported", traceType)
+				return RPCErrorResponse(types.InvalidParamsErrorCode, errMsg, nil, false)
+			}
+			includeTrace = true
+		}
+
+		block, rpcErr := t.getBlockByNumber(ctx, number, dbTx)
+		if rpcErr != nil || block == nil {
+			return nil, rpcErr
+		}
+
+		replays := []traceReplayTransactionResponse{}
+		for _, tx := range block.Transactions() {
+			txTraces, output, rpcErr := t.buildTraceTransaction(ctx, tx.Hash(), dbTx)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+			replay := traceReplayTransactionResponse{
+				Output:          output,
+				Trace:           []json.RawMessage{},
+				TransactionHash: tx.Hash(),
+			}
+			if includeTrace {
+				replay.Trace = txTraces
+			}
+			replays = append(replays, replay)
+		}
+
+		return replays, nil
+	})
+}
+
+// Filter creates a response for trace_filter request.
+// The transactions of the block range whose sender, recipient or created
+// contract is one of the from and to addresses are picked from the address
+// index, then they are traced and their calls are filtered by the addresses,
+// so the internal calls of the picked transactions are matched too.
+// The number of transactions to trace is limited by MaxTraceFilterTxs.
+// See https://openethereum.github.io/JSONRPC-trace-module#trace_filter
+func (t *TraceEndpoints) Filter(filter TraceFilter) (interface{}, types.Error) {
+	return t.txMan.NewDbTxScope(t.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		fromBlock, toBlock, rpcErr := filter.GetNumericBlockNumbers(ctx, t.cfg, t.state, t.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		addresses := append(append([]common.Address{}, filter.FromAddress...), filter.ToAddress...)
+		txHashes, err := t.state.GetL2TxHashesInRange(ctx, fromBlock, toBlock, addresses, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get transactions from state", err, true)
+		}
+		if t.cfg.MaxTraceFilterTxs > 0 && uint64(len(txHashes)) > t.cfg.MaxTraceFilterTxs {
+			errMsg := fmt.Sprintf(state.ErrMaxTraceFilterTxsLimitExceeded.Error(), t.cfg.MaxTraceFilterTxs)
+			return RPCErrorResponse(types.InvalidParamsErrorCode, errMsg, nil, false)
+		}
+
+		var skip uint64
+		if filter.After != nil {
+			skip = *filter.After
+		}
+
+		traces := []json.RawMessage{}
+		for _, txHash := range txHashes {
+			txTraces, _, rpcErr := t.buildTraceTransaction(ctx, txHash, dbTx)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+
+			for _, trace := range txTraces {
+				var addresses flatTraceAddresses
+				if err := json.Unmarshal(trace, &addresses); err != nil {
+					return RPCErrorResponse(types.DefaultErrorCode, "failed to parse trace", err, true)
+				}
+				if !filter.Match(addresses.fromTo()) {
+					continue
+				}
+				if skip > 0 {
+					skip--
+					continue
+				}
+				traces = append(traces, trace)
+				if filter.Count != nil && uint64(len(traces)) >= *filter.Count {
+					return traces, nil
+				}
+			}
+		}
+
+		return traces, nil
+	})
+}
+
+// getBlockByNumber returns the l2 block with the given number, or nil if it doesn't exist
+func (t *TraceEndpoints) getBlockByNumber(ctx context.Context, number types.BlockNumber, dbTx pgx.Tx) (*state.L2Block, types.Error) {
+	blockNumber, rpcErr := number.GetNumericBlockNumber(ctx, t.state, t.etherman, dbTx)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	block, err := t.state.GetL2BlockByNumber(ctx, blockNumber, dbTx)
+	if errors.Is(err, state.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, "failed to get block by number", err, true)
+		return nil, rpcErr
+	}
+
+	return block, nil
+}
+
+// buildTraceTransaction returns the traces of a transaction of a block
+func (t *TraceEndpoints) buildTraceTransaction(ctx context.Context, hash common.Hash, dbTx pgx.Tx) ([]json.RawMessage, []byte, types.Error) {
+	traces, output, err := t.traceTransaction(ctx, hash, dbTx)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to get trace for transaction %v: %v", hash.String(), err.Error())
+		_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, errMsg, err, true)
+		return nil, nil, rpcErr
+	}
+	return traces, output, nil
+}
+
+// traceTransaction traces the transaction with the flat call tracer and
+// returns the traces along with the output of the transaction
+func (t *TraceEndpoints) traceTransaction(ctx context.Context, hash common.Hash, dbTx pgx.Tx) ([]json.RawMessage, []byte, error) {
+	tracer := flatCallTracer
+	traceConfig := state.TraceConfig{
+		Tracer:       &tracer,
+		TracerConfig: flatCallTracerConfig,
+	}
+	result, err := t.state.DebugTransaction(ctx, hash, traceConfig, dbTx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	traces := []json.RawMessage{}
+	if err := json.Unmarshal(result.TraceResult, &traces); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the flat call trace: %w", err)
+	}
+	return traces, result.ReturnValue, nil
+}
@@ -0,0 +1,278 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func flatCallTraceForTest(from, to common.Address, traceAddress string) string {
+	return fmt.Sprintf(`{"action":{"callType":"call","from":"%s","to":"%s","gas":"0x0","input":"0x","value":"0x0"},"result":{"gasUsed":"0x0","output":"0x"},"subtraces":0,"traceAddress":%s,"type":"call"}`,
+		from.String(), to.String(), traceAddress)
+}
+
+func TestTraceTransaction(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	tracer := flatCallTracer
+	traceCfg := state.TraceConfig{Tracer: &tracer, TracerConfig: flatCallTracerConfig}
+	txHash := common.HexToHash("0x1")
+	trace := flatCallTraceForTest(common.HexToAddress("0x2"), common.HexToAddress("0x3"), "[]")
+
+	type testCase struct {
+		Name           string
+		ExpectedResult string
+		ExpectedError  types.Error
+		SetupMocks     func(m *mocksWrapper)
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "transaction traced",
+			ExpectedResult: "[" + trace + "]",
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("DebugTransaction", context.Background(), txHash, traceCfg, m.DbTx).
+					Return(&runtime.ExecutionResult{TraceResult: json.RawMessage("[" + trace + "]")}, nil).Once()
+			},
+		},
+		{
+			Name:           "transaction not found",
+			ExpectedResult: "null",
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("DebugTransaction", context.Background(), txHash, traceCfg, m.DbTx).Return(nil, state.ErrNotFound).Once()
+			},
+		},
+		{
+			Name:          "failed to trace the transaction",
+			ExpectedError: types.NewRPCError(types.DefaultErrorCode, "failed to get trace: failed to trace"),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("DebugTransaction", context.Background(), txHash, traceCfg, m.DbTx).Return(nil, errors.New("failed to trace")).Once()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("trace_transaction", txHash.String())
+			require.NoError(t, err)
+
+			if tc.ExpectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+				return
+			}
+
+			require.Nil(t, res.Error)
+			assert.JSONEq(t, tc.ExpectedResult, string(res.Result))
+		})
+	}
+}
+
+func TestTraceBlock(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	tracer := flatCallTracer
+	traceCfg := state.TraceConfig{Tracer: &tracer, TracerConfig: flatCallTracerConfig}
+	tx1 := ethTypes.NewTransaction(0, common.HexToAddress("0x3"), big.NewInt(0), 0, big.NewInt(0), nil)
+	tx2 := ethTypes.NewTransaction(1, common.HexToAddress("0x4"), big.NewInt(0), 0, big.NewInt(0), nil)
+	block := state.NewL2Block(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(1)}), []*ethTypes.Transaction{tx1, tx2}, nil, []*ethTypes.Receipt{ethTypes.NewReceipt([]byte{}, false, 0), ethTypes.NewReceipt([]byte{}, false, 0)}, &trie.StackTrie{})
+	trace1 := flatCallTraceForTest(common.HexToAddress("0x2"), common.HexToAddress("0x3"), "[]")
+	trace2 := flatCallTraceForTest(common.HexToAddress("0x2"), common.HexToAddress("0x4"), "[]")
+	trace3 := flatCallTraceForTest(common.HexToAddress("0x4"), common.HexToAddress("0x5"), "[0]")
+
+	setupBlockMocks := func(m *mocksWrapper) {
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("GetL2BlockByNumber", context.Background(), uint64(1), m.DbTx).Return(block, nil).Once()
+		m.State.On("DebugTransaction", context.Background(), tx1.Hash(), traceCfg, m.DbTx).
+			Return(&runtime.ExecutionResult{TraceResult: json.RawMessage("[" + trace1 + "]"), ReturnValue: []byte{0x1}}, nil).Once()
+		m.State.On("DebugTransaction", context.Background(), tx2.Hash(), traceCfg, m.DbTx).
+			Return(&runtime.ExecutionResult{TraceResult: json.RawMessage("[" + trace2 + "," + trace3 + "]")}, nil).Once()
+	}
+
+	t.Run("trace_block", func(t *testing.T) {
+		m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+		setupBlockMocks(m)
+
+		res, err := s.JSONRPCCall("trace_block", "0x1")
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+		assert.JSONEq(t, "["+trace1+","+trace2+","+trace3+"]", string(res.Result))
+	})
+
+	t.Run("trace_block of a block not found", func(t *testing.T) {
+		m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("GetL2BlockByNumber", context.Background(), uint64(2), m.DbTx).Return(nil, state.ErrNotFound).Once()
+
+		res, err := s.JSONRPCCall("trace_block", "0x2")
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+		assert.Equal(t, "null", string(res.Result))
+	})
+
+	t.Run("trace_replayBlockTransactions", func(t *testing.T) {
+		m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+		setupBlockMocks(m)
+
+		res, err := s.JSONRPCCall("trace_replayBlockTransactions", "0x1", []string{"trace"})
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+		expected := fmt.Sprintf(`[
+			{"output":"0x01","stateDiff":null,"trace":[%s],"vmTrace":null,"transactionHash":"%s"},
+			{"output":"0x","stateDiff":null,"trace":[%s,%s],"vmTrace":null,"transactionHash":"%s"}
+		]`, trace1, tx1.Hash().String(), trace2, trace3, tx2.Hash().String())
+		assert.JSONEq(t, expected, string(res.Result))
+	})
+
+	t.Run("trace_replayBlockTransactions with unsupported trace type", func(t *testing.T) {
+		m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+
+		res, err := s.JSONRPCCall("trace_replayBlockTransactions", "0x1", []string{"trace", "vmTrace"})
+		require.NoError(t, err)
+		require.NotNil(t, res.Error)
+		assert.Equal(t, types.InvalidParamsErrorCode, res.Error.Code)
+		assert.Equal(t, "trace type vmTrace is not supported", res.Error.Message)
+	})
+}
+
+func TestTraceFilter(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.MaxTraceFilterBlockRange = 10
+	cfg.MaxTraceFilterTxs = 2
+	s, m, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	tracer := flatCallTracer
+	traceCfg := state.TraceConfig{Tracer: &tracer, TracerConfig: flatCallTracerConfig}
+	addrA, addrB, addrC := common.HexToAddress("0xa"), common.HexToAddress("0xb"), common.HexToAddress("0xc")
+	tx1, tx2 := common.HexToHash("0x1"), common.HexToHash("0x2")
+	// tx1 calls B from A, then B calls C; tx2 calls B from C
+	trace1 := flatCallTraceForTest(addrA, addrB, "[]")
+	trace2 := flatCallTraceForTest(addrB, addrC, "[0]")
+	trace3 := flatCallTraceForTest(addrC, addrB, "[]")
+
+	type testCase struct {
+		Name           string
+		Filter         map[string]interface{}
+		ExpectedResult string
+		ExpectedError  types.Error
+		SetupMocks     func(m *mocksWrapper)
+	}
+
+	setupTxMocks := func(m *mocksWrapper, addresses []common.Address) {
+		m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("GetL2TxHashesInRange", context.Background(), uint64(1), uint64(5), addresses, m.DbTx).Return([]common.Hash{tx1, tx2}, nil).Once()
+		m.State.On("DebugTransaction", context.Background(), tx1, traceCfg, m.DbTx).
+			Return(&runtime.ExecutionResult{TraceResult: json.RawMessage("[" + trace1 + "," + trace2 + "]")}, nil).Once()
+		m.State.On("DebugTransaction", context.Background(), tx2, traceCfg, m.DbTx).
+			Return(&runtime.ExecutionResult{TraceResult: json.RawMessage("[" + trace3 + "]")}, nil).Maybe()
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "all the traces of the range",
+			Filter:         map[string]interface{}{"fromBlock": "0x1", "toBlock": "0x5"},
+			ExpectedResult: "[" + trace1 + "," + trace2 + "," + trace3 + "]",
+			SetupMocks: func(m *mocksWrapper) {
+				setupTxMocks(m, []common.Address{})
+			},
+		},
+		{
+			Name:           "traces to an address",
+			Filter:         map[string]interface{}{"fromBlock": "0x1", "toBlock": "0x5", "toAddress": []common.Address{addrB}},
+			ExpectedResult: "[" + trace1 + "," + trace3 + "]",
+			SetupMocks: func(m *mocksWrapper) {
+				setupTxMocks(m, []common.Address{addrB})
+			},
+		},
+		{
+			Name:           "traces from and to addresses",
+			Filter:         map[string]interface{}{"fromBlock": "0x1", "toBlock": "0x5", "fromAddress": []common.Address{addrB}, "toAddress": []common.Address{addrC}},
+			ExpectedResult: "[" + trace2 + "]",
+			SetupMocks: func(m *mocksWrapper) {
+				setupTxMocks(m, []common.Address{addrB, addrC})
+			},
+		},
+		{
+			Name:           "traces paginated",
+			Filter:         map[string]interface{}{"fromBlock": "0x1", "toBlock": "0x5", "after": 1, "count": 1},
+			ExpectedResult: "[" + trace2 + "]",
+			SetupMocks: func(m *mocksWrapper) {
+				setupTxMocks(m, []common.Address{})
+			},
+		},
+		{
+			Name:          "block range bigger than allowed",
+			Filter:        map[string]interface{}{"fromBlock": "0x1", "toBlock": "0x20"},
+			ExpectedError: types.NewRPCError(types.InvalidParamsErrorCode, "traces are limited to a 10 block range"),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+			},
+		},
+		{
+			Name:          "more transactions than allowed",
+			Filter:        map[string]interface{}{"fromBlock": "0x1", "toBlock": "0x5"},
+			ExpectedError: types.NewRPCError(types.InvalidParamsErrorCode, "traces are limited to 2 transactions, reduce the block range"),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL2TxHashesInRange", context.Background(), uint64(1), uint64(5), []common.Address{}, m.DbTx).Return([]common.Hash{tx1, tx2, common.HexToHash("0x3")}, nil).Once()
+			},
+		},
+		{
+			Name:          "failed to get the transactions",
+			Filter:        map[string]interface{}{"fromBlock": "0x1", "toBlock": "0x5"},
+			ExpectedError: types.NewRPCError(types.DefaultErrorCode, "failed to get transactions from state"),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL2TxHashesInRange", context.Background(), uint64(1), uint64(5), []common.Address{}, m.DbTx).Return(nil, errors.New("failed to get txs")).Once()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("trace_filter", tc.Filter)
+			require.NoError(t, err)
+
+			if tc.ExpectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+				return
+			}
+
+			require.Nil(t, res.Error)
+			assert.JSONEq(t, tc.ExpectedResult, string(res.Result))
+		})
+	}
+}
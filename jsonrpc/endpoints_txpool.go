@@ -1,39 +1,314 @@
 package jsonrpc
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/client"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
 )
 
 // TxPoolEndpoints is the txpool jsonrpc endpoint
-type TxPoolEndpoints struct{}
+type TxPoolEndpoints struct {
+	cfg   Config
+	pool  types.PoolInterface
+	state types.StateInterface
+	txMan DBTxManager
+}
+
+// NewTxPoolEndpoints returns TxPoolEndpoints
+func NewTxPoolEndpoints(cfg Config, p types.PoolInterface, st types.StateInterface) *TxPoolEndpoints {
+	return &TxPoolEndpoints{
+		cfg:   cfg,
+		pool:  p,
+		state: st,
+	}
+}
 
 type contentResponse struct {
-	Pending map[common.Address]map[uint64]*txPoolTransaction `json:"pending"`
-	Queued  map[common.Address]map[uint64]*txPoolTransaction `json:"queued"`
+	Pending map[common.Address]map[uint64]*types.Transaction `json:"pending"`
+	Queued  map[common.Address]map[uint64]*types.Transaction `json:"queued"`
+}
+
+type contentFromResponse struct {
+	Pending map[uint64]*types.Transaction `json:"pending"`
+	Queued  map[uint64]*types.Transaction `json:"queued"`
+}
+
+type inspectResponse struct {
+	Pending map[common.Address]map[uint64]string `json:"pending"`
+	Queued  map[common.Address]map[uint64]string `json:"queued"`
 }
 
-type txPoolTransaction struct {
-	Nonce       types.ArgUint64 `json:"nonce"`
-	GasPrice    types.ArgBig    `json:"gasPrice"`
-	Gas         types.ArgUint64 `json:"gas"`
-	To          *common.Address `json:"to"`
-	Value       types.ArgBig    `json:"value"`
-	Input       types.ArgBytes  `json:"input"`
-	Hash        common.Hash     `json:"hash"`
-	From        common.Address  `json:"from"`
-	BlockHash   common.Hash     `json:"blockHash"`
-	BlockNumber interface{}     `json:"blockNumber"`
-	TxIndex     interface{}     `json:"transactionIndex"`
+type statusResponse struct {
+	Pending types.ArgUint64 `json:"pending"`
+	Queued  types.ArgUint64 `json:"queued"`
 }
 
+// poolTxsByNonce contains the pool txs of a single sender indexed by nonce
+type poolTxsByNonce map[uint64]*ethTypes.Transaction
+
 // Content creates a response for txpool_content request.
-// See https://geth.ethereum.org/docs/rpc/ns-txpool#txpool_content.
-func (e *TxPoolEndpoints) Content() (interface{}, types.Error) {
-	resp := contentResponse{
-		Pending: make(map[common.Address]map[uint64]*txPoolTransaction),
-		Queued:  make(map[common.Address]map[uint64]*txPoolTransaction),
+// See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-txpool#txpool-content
+func (t *TxPoolEndpoints) Content() (interface{}, types.Error) {
+	if t.cfg.SequencerNodeURI != "" {
+		return t.relayToSequencerNode("txpool_content")
+	}
+
+	return t.txMan.NewDbTxScope(t.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		pending, queued, rpcErr := t.getPendingAndQueuedTxs(ctx, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		resp := contentResponse{
+			Pending: make(map[common.Address]map[uint64]*types.Transaction, len(pending)),
+			Queued:  make(map[common.Address]map[uint64]*types.Transaction, len(queued)),
+		}
+		for from, txs := range pending {
+			if resp.Pending[from], rpcErr = newTxPoolTransactions(txs); rpcErr != nil {
+				return nil, rpcErr
+			}
+		}
+		for from, txs := range queued {
+			if resp.Queued[from], rpcErr = newTxPoolTransactions(txs); rpcErr != nil {
+				return nil, rpcErr
+			}
+		}
+
+		return resp, nil
+	})
+}
+
+// ContentFrom creates a response for txpool_contentFrom request.
+// See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-txpool#txpool-contentfrom
+func (t *TxPoolEndpoints) ContentFrom(address types.ArgAddress) (interface{}, types.Error) {
+	if t.cfg.SequencerNodeURI != "" {
+		return t.relayToSequencerNode("txpool_contentFrom", address.Address().String())
+	}
+
+	return t.txMan.NewDbTxScope(t.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		root, rpcErr := t.getLastStateRoot(ctx, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		from := address.Address()
+		stateNonce, err := t.state.GetNonce(ctx, from, root)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get account nonce from state", err, true)
+		}
+		poolTxs, err := t.pool.GetPendingTxsByFrom(ctx, from)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get txs from pool", err, true)
+		}
+
+		txs := make(poolTxsByNonce, len(poolTxs))
+		for _, poolTx := range poolTxs {
+			tx := poolTx.Transaction
+			txs[tx.Nonce()] = &tx
+		}
+
+		pending, queued := splitPendingAndQueuedTxs(txs, stateNonce)
+
+		resp := contentFromResponse{}
+		if resp.Pending, rpcErr = newTxPoolTransactions(pending); rpcErr != nil {
+			return nil, rpcErr
+		}
+		if resp.Queued, rpcErr = newTxPoolTransactions(queued); rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		return resp, nil
+	})
+}
+
+// Inspect creates a response for txpool_inspect request.
+// See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-txpool#txpool-inspect
+func (t *TxPoolEndpoints) Inspect() (interface{}, types.Error) {
+	if t.cfg.SequencerNodeURI != "" {
+		return t.relayToSequencerNode("txpool_inspect")
+	}
+
+	return t.txMan.NewDbTxScope(t.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		pending, queued, rpcErr := t.getPendingAndQueuedTxs(ctx, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		resp := inspectResponse{
+			Pending: make(map[common.Address]map[uint64]string, len(pending)),
+			Queued:  make(map[common.Address]map[uint64]string, len(queued)),
+		}
+		for from, txs := range pending {
+			resp.Pending[from] = inspectTxs(txs)
+		}
+		for from, txs := range queued {
+			resp.Queued[from] = inspectTxs(txs)
+		}
+
+		return resp, nil
+	})
+}
+
+// Status creates a response for txpool_status request. The txs are split into
+// pending and queued by the account nonces like the txpool_content ones.
+// See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-txpool#txpool-status
+func (t *TxPoolEndpoints) Status() (interface{}, types.Error) {
+	if t.cfg.SequencerNodeURI != "" {
+		return t.relayToSequencerNode("txpool_status")
+	}
+
+	return t.txMan.NewDbTxScope(t.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		pending, queued, rpcErr := t.getPendingAndQueuedTxs(ctx, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		resp := statusResponse{}
+		for _, txs := range pending {
+			resp.Pending += types.ArgUint64(len(txs))
+		}
+		for _, txs := range queued {
+			resp.Queued += types.ArgUint64(len(txs))
+		}
+
+		return resp, nil
+	})
+}
+
+// getPendingAndQueuedTxs loads the pending txs from the pool and splits them by
+// sender into the txs that can be executed right away and the txs that are
+// waiting for a nonce gap to be filled
+func (t *TxPoolEndpoints) getPendingAndQueuedTxs(ctx context.Context, dbTx pgx.Tx) (map[common.Address]poolTxsByNonce, map[common.Address]poolTxsByNonce, types.Error) {
+	poolTxs, err := t.pool.GetTxsByStatus(ctx, pool.TxStatusPending, 0)
+	if err != nil {
+		_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, "failed to get pending txs from pool", err, true)
+		return nil, nil, rpcErr
 	}
 
-	return resp, nil
+	txsBySender := map[common.Address]poolTxsByNonce{}
+	for _, poolTx := range poolTxs {
+		tx := poolTx.Transaction
+		from, err := state.GetSender(tx)
+		if err != nil {
+			_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("failed to get sender of tx %v", tx.Hash().String()), err, true)
+			return nil, nil, rpcErr
+		}
+		if _, found := txsBySender[from]; !found {
+			txsBySender[from] = poolTxsByNonce{}
+		}
+		txsBySender[from][tx.Nonce()] = &tx
+	}
+
+	pending := make(map[common.Address]poolTxsByNonce, len(txsBySender))
+	queued := make(map[common.Address]poolTxsByNonce)
+	if len(txsBySender) == 0 {
+		return pending, queued, nil
+	}
+
+	root, rpcErr := t.getLastStateRoot(ctx, dbTx)
+	if rpcErr != nil {
+		return nil, nil, rpcErr
+	}
+
+	for from, txs := range txsBySender {
+		stateNonce, err := t.state.GetNonce(ctx, from, root)
+		if err != nil {
+			_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, "failed to get account nonce from state", err, true)
+			return nil, nil, rpcErr
+		}
+		senderPending, senderQueued := splitPendingAndQueuedTxs(txs, stateNonce)
+		if len(senderPending) > 0 {
+			pending[from] = senderPending
+		}
+		if len(senderQueued) > 0 {
+			queued[from] = senderQueued
+		}
+	}
+
+	return pending, queued, nil
+}
+
+func (t *TxPoolEndpoints) getLastStateRoot(ctx context.Context, dbTx pgx.Tx) (common.Hash, types.Error) {
+	lastBlock, err := t.state.GetLastL2Block(ctx, dbTx)
+	if err != nil {
+		_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, "failed to get the last block from state", err, true)
+		return common.Hash{}, rpcErr
+	}
+	return lastBlock.Root(), nil
+}
+
+func (t *TxPoolEndpoints) relayToSequencerNode(method string, params ...interface{}) (interface{}, types.Error) {
+	res, err := client.JSONRPCCall(t.cfg.SequencerNodeURI, method, params...)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get txpool data from sequencer node", err, true)
+	}
+
+	if res.Error != nil {
+		return RPCErrorResponse(res.Error.Code, res.Error.Message, nil, false)
+	}
+
+	return json.RawMessage(res.Result), nil
+}
+
+// splitPendingAndQueuedTxs splits the txs of a single sender into the txs
+// with consecutive nonces starting at the current account nonce, which can be
+// executed right away, and the txs after a nonce gap. Txs with a nonce lower
+// than the account nonce were already mined and are discarded
+func splitPendingAndQueuedTxs(txs poolTxsByNonce, stateNonce uint64) (poolTxsByNonce, poolTxsByNonce) {
+	nonces := make([]uint64, 0, len(txs))
+	for nonce := range txs {
+		nonces = append(nonces, nonce)
+	}
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+
+	pending, queued := poolTxsByNonce{}, poolTxsByNonce{}
+	expectedNonce := stateNonce
+	for _, nonce := range nonces {
+		if nonce < stateNonce {
+			continue
+		}
+		if nonce == expectedNonce && len(queued) == 0 {
+			pending[nonce] = txs[nonce]
+			expectedNonce++
+		} else {
+			queued[nonce] = txs[nonce]
+		}
+	}
+
+	return pending, queued
+}
+
+func newTxPoolTransactions(txs poolTxsByNonce) (map[uint64]*types.Transaction, types.Error) {
+	res := make(map[uint64]*types.Transaction, len(txs))
+	for nonce, tx := range txs {
+		rpcTx, err := types.NewTransaction(*tx, nil, false)
+		if err != nil {
+			_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, "failed to build transaction response", err, true)
+			return nil, rpcErr
+		}
+		res[nonce] = rpcTx
+	}
+	return res, nil
+}
+
+// inspectTxs summarizes the given txs the same way geth does for txpool_inspect
+func inspectTxs(txs poolTxsByNonce) map[uint64]string {
+	res := make(map[uint64]string, len(txs))
+	for nonce, tx := range txs {
+		if to := tx.To(); to != nil {
+			res[nonce] = fmt.Sprintf("%s: %v wei + %v gas × %v wei", to.Hex(), tx.Value(), tx.Gas(), tx.GasPrice())
+		} else {
+			res[nonce] = fmt.Sprintf("contract creation: %v wei + %v gas × %v wei", tx.Value(), tx.Gas(), tx.GasPrice())
+		}
+	}
+	return res
 }
@@ -0,0 +1,199 @@
+package jsonrpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type txPoolTestAccount struct {
+	key        *ecdsa.PrivateKey
+	address    common.Address
+	stateNonce uint64
+	txs        map[uint64]*ethTypes.Transaction
+}
+
+func newTxPoolTestAccount(t *testing.T, chainID uint64, stateNonce uint64, nonces ...uint64) *txPoolTestAccount {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	account := &txPoolTestAccount{
+		key:        key,
+		address:    crypto.PubkeyToAddress(key.PublicKey),
+		stateNonce: stateNonce,
+		txs:        map[uint64]*ethTypes.Transaction{},
+	}
+	signer := ethTypes.NewEIP155Signer(big.NewInt(0).SetUint64(chainID))
+	for _, nonce := range nonces {
+		tx, err := ethTypes.SignNewTx(key, signer, &ethTypes.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: big.NewInt(1),
+			Gas:      21000,
+			To:       state.HexToAddressPtr("0x1"),
+			Value:    big.NewInt(10),
+		})
+		require.NoError(t, err)
+		account.txs[nonce] = tx
+	}
+	return account
+}
+
+func (a *txPoolTestAccount) poolTxs() []pool.Transaction {
+	poolTxs := make([]pool.Transaction, 0, len(a.txs))
+	for _, tx := range a.txs {
+		poolTxs = append(poolTxs, *pool.NewTransaction(*tx, "", false))
+	}
+	return poolTxs
+}
+
+func TestTxPoolStatusContentAndInspect(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	// account with a stale tx (nonce 0), two executable txs and a tx after a nonce gap
+	accountA := newTxPoolTestAccount(t, s.ChainID(), 1, 0, 1, 2, 4)
+	// account with a single executable tx
+	accountB := newTxPoolTestAccount(t, s.ChainID(), 0, 0)
+
+	blockRoot := common.HexToHash("0xabc")
+	block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(1), Root: blockRoot}))
+
+	setupMocks := func() {
+		m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.Pool.On("GetTxsByStatus", context.Background(), pool.TxStatusPending, uint64(0)).
+			Return(append(accountA.poolTxs(), accountB.poolTxs()...), nil).Once()
+		m.State.On("GetLastL2Block", context.Background(), m.DbTx).Return(block, nil).Once()
+		m.State.On("GetNonce", context.Background(), accountA.address, blockRoot).Return(accountA.stateNonce, nil).Once()
+		m.State.On("GetNonce", context.Background(), accountB.address, blockRoot).Return(accountB.stateNonce, nil).Once()
+	}
+
+	t.Run("status", func(t *testing.T) {
+		setupMocks()
+
+		res, err := s.JSONRPCCall("txpool_status")
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+		assert.JSONEq(t, `{"pending":"0x3","queued":"0x1"}`, string(res.Result))
+	})
+
+	t.Run("status fails to get txs from pool", func(t *testing.T) {
+		m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.Pool.On("GetTxsByStatus", context.Background(), pool.TxStatusPending, uint64(0)).Return(nil, errors.New("failed")).Once()
+
+		res, err := s.JSONRPCCall("txpool_status")
+		require.NoError(t, err)
+		require.NotNil(t, res.Error)
+		assert.Equal(t, types.DefaultErrorCode, res.Error.Code)
+		assert.Equal(t, "failed to get pending txs from pool", res.Error.Message)
+	})
+
+	t.Run("content", func(t *testing.T) {
+		setupMocks()
+
+		res, err := s.JSONRPCCall("txpool_content")
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+
+		var content map[string]map[common.Address]map[string]types.Transaction
+		require.NoError(t, json.Unmarshal(res.Result, &content))
+
+		require.Len(t, content["pending"], 2)
+		require.Len(t, content["pending"][accountA.address], 2)
+		assert.Equal(t, accountA.txs[1].Hash(), content["pending"][accountA.address]["1"].Hash)
+		assert.Equal(t, accountA.txs[2].Hash(), content["pending"][accountA.address]["2"].Hash)
+		assert.Equal(t, accountA.address, content["pending"][accountA.address]["2"].From)
+		require.Len(t, content["pending"][accountB.address], 1)
+		assert.Equal(t, accountB.txs[0].Hash(), content["pending"][accountB.address]["0"].Hash)
+
+		require.Len(t, content["queued"], 1)
+		require.Len(t, content["queued"][accountA.address], 1)
+		assert.Equal(t, accountA.txs[4].Hash(), content["queued"][accountA.address]["4"].Hash)
+	})
+
+	t.Run("inspect", func(t *testing.T) {
+		setupMocks()
+
+		res, err := s.JSONRPCCall("txpool_inspect")
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+
+		var inspect map[string]map[common.Address]map[string]string
+		require.NoError(t, json.Unmarshal(res.Result, &inspect))
+
+		summary := "0x0000000000000000000000000000000000000001: 10 wei + 21000 gas × 1 wei"
+		assert.Equal(t, map[string]string{"1": summary, "2": summary}, inspect["pending"][accountA.address])
+		assert.Equal(t, map[string]string{"0": summary}, inspect["pending"][accountB.address])
+		assert.Equal(t, map[string]string{"4": summary}, inspect["queued"][accountA.address])
+	})
+
+	t.Run("content fails to get txs from pool", func(t *testing.T) {
+		m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.Pool.On("GetTxsByStatus", context.Background(), pool.TxStatusPending, uint64(0)).Return(nil, errors.New("failed")).Once()
+
+		res, err := s.JSONRPCCall("txpool_content")
+		require.NoError(t, err)
+		require.NotNil(t, res.Error)
+		assert.Equal(t, types.DefaultErrorCode, res.Error.Code)
+		assert.Equal(t, "failed to get pending txs from pool", res.Error.Message)
+	})
+}
+
+func TestTxPoolContentFrom(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	account := newTxPoolTestAccount(t, s.ChainID(), 3, 3, 5)
+
+	blockRoot := common.HexToHash("0xabc")
+	block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(1), Root: blockRoot}))
+
+	m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+	m.State.On("GetLastL2Block", context.Background(), m.DbTx).Return(block, nil).Once()
+	m.State.On("GetNonce", context.Background(), account.address, blockRoot).Return(account.stateNonce, nil).Once()
+	m.Pool.On("GetPendingTxsByFrom", context.Background(), account.address).Return(account.poolTxs(), nil).Once()
+
+	res, err := s.JSONRPCCall("txpool_contentFrom", account.address.String())
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	var content map[string]map[string]types.Transaction
+	require.NoError(t, json.Unmarshal(res.Result, &content))
+
+	require.Len(t, content["pending"], 1)
+	assert.Equal(t, account.txs[3].Hash(), content["pending"]["3"].Hash)
+	require.Len(t, content["queued"], 1)
+	assert.Equal(t, account.txs[5].Hash(), content["queued"]["5"].Hash)
+}
+
+func TestTxPoolRelayToSequencerNode(t *testing.T) {
+	sequencer, sequencerMocks, _ := newSequencerMockedServer(t)
+	defer sequencer.Stop()
+
+	nonSequencer, _, _ := newNonSequencerMockedServer(t, sequencer.ServerURL)
+	defer nonSequencer.Stop()
+
+	sequencerMocks.DbTx.On("Commit", context.Background()).Return(nil).Once()
+	sequencerMocks.State.On("BeginStateTransaction", context.Background()).Return(sequencerMocks.DbTx, nil).Once()
+	sequencerMocks.Pool.On("GetTxsByStatus", context.Background(), pool.TxStatusPending, uint64(0)).Return([]pool.Transaction{}, nil).Once()
+
+	res, err := nonSequencer.JSONRPCCall("txpool_status")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.JSONEq(t, `{"pending":"0x0","queued":"0x0"}`, string(res.Result))
+}
@@ -4,30 +4,44 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
+	"github.com/ethereum/go-ethereum/common"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/jackc/pgx/v4"
 )
 
+// defaultLogsPageSize is the number of logs returned by zkevm_getLogsPaginated
+// when the limit is not provided
+const defaultLogsPageSize = 1000
+
 // ZKEVMEndpoints contains implementations for the "zkevm" RPC endpoints
 type ZKEVMEndpoints struct {
 	cfg      Config
+	chainID  uint64
+	pool     types.PoolInterface
 	state    types.StateInterface
 	etherman types.EthermanInterface
+	cache    *ConsolidatedCache
 	txMan    DBTxManager
 }
 
 // NewZKEVMEndpoints returns ZKEVMEndpoints
-func NewZKEVMEndpoints(cfg Config, state types.StateInterface, etherman types.EthermanInterface) *ZKEVMEndpoints {
+func NewZKEVMEndpoints(cfg Config, chainID uint64, pool types.PoolInterface, state types.StateInterface, etherman types.EthermanInterface, cache *ConsolidatedCache) *ZKEVMEndpoints {
 	return &ZKEVMEndpoints{
 		cfg:      cfg,
+		chainID:  chainID,
+		pool:     pool,
 		state:    state,
 		etherman: etherman,
+		cache:    cache,
 	}
 }
 
@@ -126,7 +140,20 @@ func (z *ZKEVMEndpoints) VerifiedBatchNumber() (interface{}, types.Error) {
 
 // GetBatchByNumber returns information about a batch by batch number
 func (z *ZKEVMEndpoints) GetBatchByNumber(batchNumber types.BatchNumber, fullTx bool) (interface{}, types.Error) {
-	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+	if batchNumber < 0 {
+		batch, _, rpcErr := z.getBatchByNumber(batchNumber, fullTx)
+		return batch, rpcErr
+	}
+	return z.cache.cached("zkevm_getBatchByNumber", fmt.Sprintf("%d/%t", batchNumber, fullTx), func() (interface{}, uint64, types.Error) {
+		return z.getBatchByNumber(batchNumber, fullTx)
+	})
+}
+
+// getBatchByNumber returns the batch and the number of its last L2 block, which
+// is the max uint64 when the batch has no blocks yet
+func (z *ZKEVMEndpoints) getBatchByNumber(batchNumber types.BatchNumber, fullTx bool) (interface{}, uint64, types.Error) {
+	lastBlockNumber := uint64(math.MaxUint64)
+	batch, rpcErr := z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
 		var err error
 		batchNumber, rpcErr := batchNumber.GetNumericBatchNumber(ctx, z.state, z.etherman, dbTx)
 		if rpcErr != nil {
@@ -175,6 +202,11 @@ func (z *ZKEVMEndpoints) GetBatchByNumber(batchNumber types.BatchNumber, fullTx
 		if err != nil {
 			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load blocks associated to the batch %v", batchNumber), err, true)
 		}
+		for i, block := range blocks {
+			if i == 0 || block.NumberU64() > lastBlockNumber {
+				lastBlockNumber = block.NumberU64()
+			}
+		}
 
 		batch.Transactions = txs
 		rpcBatch, err := types.NewBatch(batch, virtualBatch, verifiedBatch, blocks, receipts, fullTx, true, ger)
@@ -183,6 +215,7 @@ func (z *ZKEVMEndpoints) GetBatchByNumber(batchNumber types.BatchNumber, fullTx
 		}
 		return rpcBatch, nil
 	})
+	return batch, lastBlockNumber, rpcErr
 }
 
 // GetFullBlockByNumber returns information about a block by block number
@@ -288,3 +321,283 @@ func (z *ZKEVMEndpoints) GetNativeBlockHashesInRange(filter NativeBlockHashBlock
 		return nativeBlockHashes, nil
 	})
 }
+
+// EstimateCounters returns the zk counters used by the transaction when
+// executed on top of the given block, along with the limits of the counters
+// and the out of counters error the transaction would get, if any.
+// The transaction will not be added to the blockchain.
+func (z *ZKEVMEndpoints) EstimateCounters(arg *types.TxArgs, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		if arg == nil {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument 0", nil, false)
+		}
+
+		block, blockToProcess, respErr := getBlockToProcessByArg(ctx, z.state, z.etherman, blockArg, dbTx)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		defaultSenderAddress := common.HexToAddress(DefaultSenderAddress)
+		sender, tx, err := arg.ToTransaction(ctx, z.state, z.cfg.MaxCumulativeGasUsed, block.Root(), defaultSenderAddress, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to convert arguments into an unsigned transaction", err, false)
+		}
+
+		counters, result, err := z.state.EstimateZKCounters(ctx, tx, sender, blockToProcess, dbTx)
+		if err != nil {
+			errMsg := fmt.Sprintf("failed to estimate the zk counters: %v", err.Error())
+			return RPCErrorResponse(types.DefaultErrorCode, errMsg, nil, !runtime.IsOutOfCounterError(err))
+		}
+
+		return types.NewZKCountersResponse(counters, z.cfg.ZKCountersLimits, result), nil
+	})
+}
+
+// GetL1InfoTreeLeaf returns the leaf of the L1 info tree with the given index
+// or global exit root
+func (z *ZKEVMEndpoints) GetL1InfoTreeLeaf(arg types.IndexOrHash) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		var leaf state.L1InfoTreeExitRootStorageEntry
+		var err error
+		if arg.Hash() != nil {
+			leaf, err = z.state.GetL1InfoRootLeafByGlobalExitRoot(ctx, arg.Hash().Hash(), dbTx)
+		} else if arg.Index() != nil && *arg.Index() <= math.MaxUint32 {
+			leaf, err = z.state.GetL1InfoRootLeafByIndex(ctx, uint32(*arg.Index()), dbTx)
+		} else {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, "invalid index or global exit root", nil, false)
+		}
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get l1 info tree leaf from state", err, true)
+		}
+
+		return types.NewL1InfoTreeLeaf(leaf), nil
+	})
+}
+
+// GetL1InfoTreeProof returns the merkle proof of the leaf of the L1 info tree
+// with the given index, computed against the root of the tree after adding
+// the leaf with the root index, or against the current root if the root index
+// is not provided
+func (z *ZKEVMEndpoints) GetL1InfoTreeProof(index types.ArgUint64, rootIndex *types.ArgUint64) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		if index > math.MaxUint32 || (rootIndex != nil && *rootIndex > math.MaxUint32) {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, "index out of range", nil, false)
+		}
+
+		var rootIndexToProve *uint32
+		if rootIndex != nil {
+			r := uint32(*rootIndex)
+			rootIndexToProve = &r
+		}
+
+		proof, err := z.state.GetL1InfoTreeProof(ctx, uint32(index), rootIndexToProve, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if errors.Is(err, state.ErrInvalidL1InfoTreeRootIndex) {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, err.Error(), nil, false)
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get l1 info tree proof from state", err, true)
+		}
+
+		return types.NewL1InfoTreeProof(*proof), nil
+	})
+}
+
+// GetForkId returns the fork id of the given batch, or of the latest batch
+// if the batch number is not provided
+func (z *ZKEVMEndpoints) GetForkId(batchNumber *types.BatchNumber) (interface{}, types.Error) { //nolint:revive
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		numericBatchNumber, rpcErr := batchNumber.GetNumericBatchNumber(ctx, z.state, z.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		return hex.EncodeUint64(z.state.GetForkIDByBatchNumber(numericBatchNumber)), nil
+	})
+}
+
+// GetForks returns the fork id intervals of the network, with the range of
+// batches each fork id applies to
+func (z *ZKEVMEndpoints) GetForks() (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		forkIDs, err := z.state.GetForkIDs(ctx, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get fork ids from state", err, true)
+		}
+
+		forks := make([]types.ForkIDInterval, 0, len(forkIDs))
+		for _, forkID := range forkIDs {
+			forks = append(forks, types.NewForkIDInterval(forkID))
+		}
+		return forks, nil
+	})
+}
+
+// GetChainConfig returns the configuration of the chain, so the clients can
+// configure themselves: the chain ids, the rollup id, the addresses of the
+// L1 contracts and the batch constraints
+func (z *ZKEVMEndpoints) GetChainConfig() (interface{}, types.Error) {
+	return types.ChainConfig{
+		ChainID:   types.ArgUint64(z.chainID),
+		L1ChainID: types.ArgUint64(z.cfg.Network.L1ChainID),
+		RollupID:  types.ArgUint64(z.cfg.Network.RollupID),
+		Contracts: types.ChainContracts{
+			ZkEVMAddr:                 z.cfg.Network.ZkEVMAddr,
+			RollupManagerAddr:         z.cfg.Network.RollupManagerAddr,
+			PolAddr:                   z.cfg.Network.PolAddr,
+			GlobalExitRootManagerAddr: z.cfg.Network.GlobalExitRootManagerAddr,
+		},
+		BatchConstraints: types.NewBatchConstraints(z.cfg.ZKCountersLimits),
+	}, nil
+}
+
+// GetLogsPaginated returns a page of the logs that match the filter, starting
+// at the cursor returned with the previous page or at the beginning of the
+// filter range when the cursor is not provided, the cursor returned is null
+// when there are no more logs in the range.
+//
+// The filter range isn't limited by MaxLogsBlockRange, instead each page
+// scans at most MaxLogsBlockRange blocks, so a page can be empty and still
+// return a cursor to continue, and the number of logs of each page is limited
+// by MaxLogsCount.
+func (z *ZKEVMEndpoints) GetLogsPaginated(filter LogFilter, cursor *string, limit *types.ArgUint64) (interface{}, types.Error) {
+	pageSize := uint64(defaultLogsPageSize)
+	if z.cfg.MaxLogsCount > 0 && pageSize > z.cfg.MaxLogsCount {
+		pageSize = z.cfg.MaxLogsCount
+	}
+	if limit != nil {
+		pageSize = uint64(*limit)
+	}
+	if pageSize == 0 {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, "limit must be greater than zero", nil, false)
+	}
+	if z.cfg.MaxLogsCount > 0 && pageSize > z.cfg.MaxLogsCount {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, fmt.Sprintf("limit must be less than or equal to %d", z.cfg.MaxLogsCount), nil, false)
+	}
+	if err := filter.Validate(); err != nil {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, err.Error(), nil, false)
+	}
+
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		page := types.LogsPage{Logs: []types.Log{}}
+
+		var fromBlock, toBlock uint64
+		if filter.BlockHash != nil {
+			block, err := z.state.GetL2BlockByHash(ctx, *filter.BlockHash, dbTx)
+			if errors.Is(err, state.ErrNotFound) {
+				return page, nil
+			} else if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, "failed to get block by hash from state", err, true)
+			}
+			fromBlock, toBlock = block.NumberU64(), block.NumberU64()
+		} else {
+			var rpcErr types.Error
+			fromBlock, toBlock, rpcErr = getNumericBlockNumbers(ctx, z.state, z.etherman, filter.FromBlock, filter.ToBlock, 0, nil, dbTx)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+		}
+
+		position := LogsCursor{BlockNumber: fromBlock}
+		if cursor != nil {
+			var err error
+			position, err = DecodeLogsCursor(*cursor)
+			if err != nil || position.BlockNumber < fromBlock {
+				return RPCErrorResponse(types.InvalidParamsErrorCode, "invalid cursor", nil, false)
+			}
+			if position.BlockNumber > toBlock {
+				return page, nil
+			}
+		}
+
+		// each page scans at most MaxLogsBlockRange blocks, both ends included
+		pageToBlock := toBlock
+		if z.cfg.MaxLogsBlockRange > 0 && toBlock-position.BlockNumber >= z.cfg.MaxLogsBlockRange {
+			pageToBlock = position.BlockNumber + z.cfg.MaxLogsBlockRange - 1
+		}
+
+		// an extra log is requested to know where the next page starts
+		logs, err := z.state.GetLogsPage(ctx, position.BlockNumber, position.LogIndex, pageToBlock, filter.Addresses, filter.Topics, pageSize+1, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get logs from state", err, true)
+		}
+
+		if uint64(len(logs)) > pageSize {
+			next := LogsCursor{BlockNumber: logs[pageSize].BlockNumber, LogIndex: uint64(logs[pageSize].Index)}.Encode()
+			page.Cursor = &next
+			logs = logs[:pageSize]
+		} else if pageToBlock < toBlock {
+			next := LogsCursor{BlockNumber: pageToBlock + 1}.Encode()
+			page.Cursor = &next
+		}
+
+		for _, l := range logs {
+			page.Logs = append(page.Logs, types.NewLog(*l))
+		}
+		return page, nil
+	})
+}
+
+// GetTransactionStatus returns the status of a transaction: the status and the
+// failed reason in the pool, the history of the pool status transitions and,
+// once the transaction is included in a block, the block and batch numbers and
+// whether the block is virtualized and verified. Returns null when the
+// transaction is unknown.
+func (z *ZKEVMEndpoints) GetTransactionStatus(hash types.ArgHash) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		status := types.TransactionStatus{Hash: hash.Hash(), History: []types.TransactionStatusTransition{}}
+
+		poolTx, err := z.pool.GetTxByHash(ctx, hash.Hash())
+		if err != nil && !errors.Is(err, pool.ErrNotFound) {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get tx from pool", err, true)
+		}
+		if poolTx != nil {
+			poolStatus := poolTx.Status.String()
+			status.PoolStatus = &poolStatus
+			status.FailedReason = poolTx.FailedReason
+		}
+
+		history, err := z.pool.GetTxStatusHistory(ctx, hash.Hash())
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get tx status history from pool", err, true)
+		}
+		for _, transition := range history {
+			status.History = append(status.History, types.TransactionStatusTransition{
+				Status:       transition.Status.String(),
+				FailedReason: transition.FailedReason,
+				ChangedAt:    types.ArgUint64(transition.ChangedAt.Unix()),
+			})
+		}
+
+		receipt, err := z.state.GetTransactionReceipt(ctx, hash.Hash(), dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			if poolTx == nil && len(history) == 0 {
+				return nil, nil
+			}
+			return status, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get tx receipt from state", err, true)
+		}
+
+		blockNumber := receipt.BlockNumber.Uint64()
+		batchNumber, err := z.state.BatchNumberByL2BlockNumber(ctx, blockNumber, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get batch number from block number", err, true)
+		}
+		status.BlockNumber = types.ArgUint64Ptr(types.ArgUint64(blockNumber))
+		status.BatchNumber = types.ArgUint64Ptr(types.ArgUint64(batchNumber))
+
+		status.Virtualized, err = z.state.IsL2BlockVirtualized(ctx, blockNumber, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to check if the block is virtualized", err, true)
+		}
+		status.Verified, err = z.state.IsL2BlockConsolidated(ctx, blockNumber, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to check if the block is consolidated", err, true)
+		}
+
+		return status, nil
+	})
+}
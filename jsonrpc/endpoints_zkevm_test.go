@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"math"
 	"math/big"
 	"strings"
 	"testing"
@@ -11,13 +12,16 @@ import (
 
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -1741,3 +1745,800 @@ func signTx(tx *ethTypes.Transaction, chainID uint64) *ethTypes.Transaction {
 	signedTx, _ := auth.Signer(auth.From, tx)
 	return signedTx
 }
+
+func TestEstimateCounters(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.ZKCountersLimits = state.BatchConstraintsCfg{
+		MaxCumulativeGasUsed: 300000,
+		MaxKeccakHashes:      2145,
+		MaxPoseidonHashes:    252357,
+		MaxPoseidonPaddings:  135191,
+		MaxMemAligns:         236585,
+		MaxArithmetics:       236585,
+		MaxBinaries:          473170,
+		MaxSteps:             7570538,
+	}
+	s, m, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	expectedLimits := types.ZKCountersLimits{
+		MaxGasUsed:          300000,
+		MaxKeccakHashes:     2145,
+		MaxPoseidonHashes:   252357,
+		MaxPoseidonPaddings: 135191,
+		MaxMemAligns:        236585,
+		MaxArithmetics:      236585,
+		MaxBinaries:         473170,
+		MaxSteps:            7570538,
+	}
+	counters := state.ZKCounters{
+		GasUsed:              21000,
+		UsedKeccakHashes:     10,
+		UsedPoseidonHashes:   20,
+		UsedPoseidonPaddings: 30,
+		UsedMemAligns:        40,
+		UsedArithmetics:      50,
+		UsedBinaries:         60,
+		UsedSteps:            70,
+		UsedSha256Hashes_V2:  80,
+	}
+	expectedUsed := types.ZKCountersUsed{
+		GasUsed:              21000,
+		UsedKeccakHashes:     10,
+		UsedPoseidonHashes:   20,
+		UsedPoseidonPaddings: 30,
+		UsedMemAligns:        40,
+		UsedArithmetics:      50,
+		UsedBinaries:         60,
+		UsedSteps:            70,
+		UsedSHA256Hashes:     80,
+	}
+	exceedingCounters := counters
+	exceedingCounters.UsedKeccakHashes = 2200
+	exceedingUsed := expectedUsed
+	exceedingUsed.UsedKeccakHashes = 2200
+	strPtr := func(s string) *string { return &s }
+
+	type testCase struct {
+		name             string
+		counters         state.ZKCounters
+		result           *runtime.ExecutionResult
+		err              error
+		expectedResponse *types.ZKCountersResponse
+		expectedError    types.Error
+	}
+
+	testCases := []testCase{
+		{
+			name:     "counters within the limits",
+			counters: counters,
+			result:   &runtime.ExecutionResult{GasUsed: 21000},
+			expectedResponse: &types.ZKCountersResponse{
+				CountersUsed:   expectedUsed,
+				CountersLimits: expectedLimits,
+			},
+		},
+		{
+			name:     "counters exceeding the limits at node level",
+			counters: exceedingCounters,
+			result:   &runtime.ExecutionResult{GasUsed: 21000},
+			expectedResponse: &types.ZKCountersResponse{
+				CountersUsed:   exceedingUsed,
+				CountersLimits: expectedLimits,
+				OOCError:       strPtr("out of counters at node level: keccak hashes 2200 exceeds the limit 2145 by 55"),
+			},
+		},
+		{
+			name:     "execution out of counters",
+			counters: counters,
+			result:   &runtime.ExecutionResult{Err: runtime.ErrOutOfCountersKeccak},
+			expectedResponse: &types.ZKCountersResponse{
+				CountersUsed:   expectedUsed,
+				CountersLimits: expectedLimits,
+				OOCError:       strPtr(runtime.ErrOutOfCountersKeccak.Error()),
+			},
+		},
+		{
+			name:     "execution reverted",
+			counters: counters,
+			result:   &runtime.ExecutionResult{Err: runtime.ErrExecutionReverted, ReturnValue: []byte{0x1}},
+			expectedResponse: &types.ZKCountersResponse{
+				CountersUsed:   expectedUsed,
+				CountersLimits: expectedLimits,
+				Revert:         &types.RevertInfo{Message: runtime.ErrExecutionReverted.Error(), Data: types.ArgBytesPtr([]byte{0x1})},
+			},
+		},
+		{
+			name:     "execution failed",
+			counters: counters,
+			result:   &runtime.ExecutionResult{Err: runtime.ErrOutOfGas},
+			expectedResponse: &types.ZKCountersResponse{
+				CountersUsed:   expectedUsed,
+				CountersLimits: expectedLimits,
+				Error:          strPtr(runtime.ErrOutOfGas.Error()),
+			},
+		},
+		{
+			name:          "failed to process the transaction",
+			err:           errors.New("failed to process"),
+			expectedError: types.NewRPCError(types.DefaultErrorCode, "failed to estimate the zk counters: failed to process"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.err != nil {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+			} else {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+			}
+			m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+			block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(1), Root: common.HexToHash("0x1")}))
+			m.State.On("GetL2BlockByNumber", context.Background(), uint64(1), m.DbTx).Return(block, nil).Once()
+			m.State.On("GetNonce", context.Background(), common.HexToAddress("0x1"), common.HexToHash("0x1")).Return(uint64(0), nil).Once()
+			txMatchBy := mock.MatchedBy(func(tx *ethTypes.Transaction) bool {
+				return tx != nil && tx.To().Hex() == common.HexToAddress("0x2").Hex() && tx.Gas() == cfg.MaxCumulativeGasUsed
+			})
+			blockNumber := uint64(1)
+			m.State.On("EstimateZKCounters", context.Background(), txMatchBy, common.HexToAddress("0x1"), &blockNumber, m.DbTx).
+				Return(tc.counters, tc.result, tc.err).Once()
+
+			txArgs := types.TxArgs{From: state.HexToAddressPtr("0x1"), To: state.HexToAddressPtr("0x2")}
+			res, err := s.JSONRPCCall("zkevm_estimateCounters", txArgs, "0x1")
+			require.NoError(t, err)
+
+			if tc.expectedResponse != nil {
+				require.Nil(t, res.Error)
+				var response types.ZKCountersResponse
+				require.NoError(t, json.Unmarshal(res.Result, &response))
+				assert.Equal(t, *tc.expectedResponse, response)
+			}
+
+			if tc.expectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, tc.expectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.expectedError.Error(), res.Error.Message)
+			}
+		})
+	}
+}
+
+func TestGetL1InfoTreeLeaf(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	ger := common.HexToHash("0x7f1b3a4e0c61d76a2c4e9a1b8f0e3d2c5b6a79880f1e2d3c4b5a69788796a5b4")
+	entry := state.L1InfoTreeExitRootStorageEntry{
+		L1InfoTreeLeaf: state.L1InfoTreeLeaf{
+			GlobalExitRoot: state.GlobalExitRoot{
+				BlockNumber:     123,
+				Timestamp:       time.Unix(1700000000, 0),
+				MainnetExitRoot: common.HexToHash("0x1"),
+				RollupExitRoot:  common.HexToHash("0x2"),
+				GlobalExitRoot:  ger,
+			},
+			PreviousBlockHash: common.HexToHash("0x3"),
+		},
+		L1InfoTreeRoot:  common.HexToHash("0x4"),
+		L1InfoTreeIndex: 5,
+	}
+	expectedLeaf := types.L1InfoTreeLeaf{
+		Index:             5,
+		GlobalExitRoot:    ger,
+		MainnetExitRoot:   common.HexToHash("0x1"),
+		RollupExitRoot:    common.HexToHash("0x2"),
+		PreviousBlockHash: common.HexToHash("0x3"),
+		Timestamp:         1700000000,
+		BlockNumber:       123,
+		L1InfoTreeRoot:    common.HexToHash("0x4"),
+		Hash:              entry.Hash(),
+	}
+
+	type testCase struct {
+		Name           string
+		Arg            interface{}
+		ExpectedResult *types.L1InfoTreeLeaf
+		ExpectedError  types.Error
+		SetupMocks     func(m *mocksWrapper)
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "leaf found by index",
+			Arg:            "0x5",
+			ExpectedResult: &expectedLeaf,
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL1InfoRootLeafByIndex", context.Background(), uint32(5), m.DbTx).Return(entry, nil).Once()
+			},
+		},
+		{
+			Name:           "leaf found by global exit root",
+			Arg:            ger.String(),
+			ExpectedResult: &expectedLeaf,
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL1InfoRootLeafByGlobalExitRoot", context.Background(), ger, m.DbTx).Return(entry, nil).Once()
+			},
+		},
+		{
+			Name:           "leaf not found",
+			Arg:            "0x6",
+			ExpectedResult: nil,
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL1InfoRootLeafByIndex", context.Background(), uint32(6), m.DbTx).Return(state.L1InfoTreeExitRootStorageEntry{}, state.ErrNotFound).Once()
+			},
+		},
+		{
+			Name:          "index out of range",
+			Arg:           "0x100000000",
+			ExpectedError: types.NewRPCError(types.InvalidParamsErrorCode, "invalid index or global exit root"),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+			},
+		},
+		{
+			Name:          "failed to get leaf",
+			Arg:           "0x5",
+			ExpectedError: types.NewRPCError(types.DefaultErrorCode, "failed to get l1 info tree leaf from state"),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL1InfoRootLeafByIndex", context.Background(), uint32(5), m.DbTx).Return(state.L1InfoTreeExitRootStorageEntry{}, errors.New("failed to get leaf")).Once()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("zkevm_getL1InfoTreeLeaf", tc.Arg)
+			require.NoError(t, err)
+
+			if tc.ExpectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+				return
+			}
+
+			require.Nil(t, res.Error)
+			var result *types.L1InfoTreeLeaf
+			require.NoError(t, json.Unmarshal(res.Result, &result))
+			assert.Equal(t, tc.ExpectedResult, result)
+		})
+	}
+}
+
+func TestGetL1InfoTreeProof(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	entry := state.L1InfoTreeExitRootStorageEntry{
+		L1InfoTreeLeaf: state.L1InfoTreeLeaf{
+			GlobalExitRoot: state.GlobalExitRoot{
+				BlockNumber:     123,
+				Timestamp:       time.Unix(1700000000, 0),
+				MainnetExitRoot: common.HexToHash("0x1"),
+				RollupExitRoot:  common.HexToHash("0x2"),
+				GlobalExitRoot:  common.HexToHash("0x3"),
+			},
+			PreviousBlockHash: common.HexToHash("0x4"),
+		},
+		L1InfoTreeRoot:  common.HexToHash("0x5"),
+		L1InfoTreeIndex: 1,
+	}
+	proof := &state.L1InfoTreeProof{
+		Leaf:      entry,
+		Root:      common.HexToHash("0x6"),
+		RootIndex: 2,
+		Siblings:  [][32]byte{common.HexToHash("0x7"), common.HexToHash("0x8")},
+	}
+	expectedProof := types.L1InfoTreeProof{
+		Leaf:      types.NewL1InfoTreeLeaf(entry),
+		Root:      common.HexToHash("0x6"),
+		RootIndex: 2,
+		Siblings:  []common.Hash{common.HexToHash("0x7"), common.HexToHash("0x8")},
+	}
+	rootIndex := uint32(2)
+
+	type testCase struct {
+		Name           string
+		Params         []interface{}
+		ExpectedResult *types.L1InfoTreeProof
+		ExpectedError  types.Error
+		SetupMocks     func(m *mocksWrapper)
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "proof against the current root",
+			Params:         []interface{}{"0x1"},
+			ExpectedResult: &expectedProof,
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL1InfoTreeProof", context.Background(), uint32(1), (*uint32)(nil), m.DbTx).Return(proof, nil).Once()
+			},
+		},
+		{
+			Name:           "proof against the root of the root index",
+			Params:         []interface{}{"0x1", "0x2"},
+			ExpectedResult: &expectedProof,
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL1InfoTreeProof", context.Background(), uint32(1), &rootIndex, m.DbTx).Return(proof, nil).Once()
+			},
+		},
+		{
+			Name:           "leaf not found",
+			Params:         []interface{}{"0x3"},
+			ExpectedResult: nil,
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL1InfoTreeProof", context.Background(), uint32(3), (*uint32)(nil), m.DbTx).Return(nil, state.ErrNotFound).Once()
+			},
+		},
+		{
+			Name:          "root index lower than the index",
+			Params:        []interface{}{"0x3", "0x2"},
+			ExpectedError: types.NewRPCError(types.InvalidParamsErrorCode, state.ErrInvalidL1InfoTreeRootIndex.Error()),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL1InfoTreeProof", context.Background(), uint32(3), &rootIndex, m.DbTx).Return(nil, state.ErrInvalidL1InfoTreeRootIndex).Once()
+			},
+		},
+		{
+			Name:          "failed to compute proof",
+			Params:        []interface{}{"0x1"},
+			ExpectedError: types.NewRPCError(types.DefaultErrorCode, "failed to get l1 info tree proof from state"),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL1InfoTreeProof", context.Background(), uint32(1), (*uint32)(nil), m.DbTx).Return(nil, errors.New("failed to compute proof")).Once()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("zkevm_getL1InfoTreeProof", tc.Params...)
+			require.NoError(t, err)
+
+			if tc.ExpectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+				return
+			}
+
+			require.Nil(t, res.Error)
+			var result *types.L1InfoTreeProof
+			require.NoError(t, json.Unmarshal(res.Result, &result))
+			assert.Equal(t, tc.ExpectedResult, result)
+		})
+	}
+}
+
+func TestGetForkId(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	type testCase struct {
+		Name           string
+		Params         []interface{}
+		ExpectedResult uint64
+		ExpectedError  types.Error
+		SetupMocks     func(m *mocksWrapper)
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "fork id of the given batch",
+			Params:         []interface{}{"0x5"},
+			ExpectedResult: forkID6,
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetForkIDByBatchNumber", uint64(5)).Return(uint64(forkID6)).Once()
+			},
+		},
+		{
+			Name:           "fork id of the latest batch",
+			Params:         []interface{}{},
+			ExpectedResult: forkID6,
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLastClosedBatchNumber", context.Background(), m.DbTx).Return(uint64(10), nil).Once()
+				m.State.On("GetForkIDByBatchNumber", uint64(10)).Return(uint64(forkID6)).Once()
+			},
+		},
+		{
+			Name:          "failed to get the latest batch",
+			Params:        []interface{}{"latest"},
+			ExpectedError: types.NewRPCError(types.DefaultErrorCode, "failed to get the last batch number from state"),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLastClosedBatchNumber", context.Background(), m.DbTx).Return(uint64(0), errors.New("failed to get last batch number")).Once()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("zkevm_getForkId", tc.Params...)
+			require.NoError(t, err)
+
+			if tc.ExpectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+				return
+			}
+
+			require.Nil(t, res.Error)
+			var result types.ArgUint64
+			require.NoError(t, json.Unmarshal(res.Result, &result))
+			assert.Equal(t, tc.ExpectedResult, uint64(result))
+		})
+	}
+}
+
+func TestGetForks(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	forkIDs := []state.ForkIDInterval{
+		{FromBatchNumber: 0, ToBatchNumber: 10, ForkId: 5, Version: "v2.0.0", BlockNumber: 100},
+		{FromBatchNumber: 11, ToBatchNumber: math.MaxUint64, ForkId: forkID6, Version: "v3.0.0", BlockNumber: 200},
+	}
+	expectedForks := []types.ForkIDInterval{
+		{FromBatchNumber: 0, ToBatchNumber: 10, ForkID: 5, Version: "v2.0.0", BlockNumber: 100},
+		{FromBatchNumber: 11, ToBatchNumber: math.MaxUint64, ForkID: forkID6, Version: "v3.0.0", BlockNumber: 200},
+	}
+
+	t.Run("forks found", func(t *testing.T) {
+		m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("GetForkIDs", context.Background(), m.DbTx).Return(forkIDs, nil).Once()
+
+		res, err := s.JSONRPCCall("zkevm_getForks")
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+
+		var result []types.ForkIDInterval
+		require.NoError(t, json.Unmarshal(res.Result, &result))
+		assert.Equal(t, expectedForks, result)
+	})
+
+	t.Run("failed to get forks", func(t *testing.T) {
+		m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("GetForkIDs", context.Background(), m.DbTx).Return(nil, errors.New("failed to get fork ids")).Once()
+
+		res, err := s.JSONRPCCall("zkevm_getForks")
+		require.NoError(t, err)
+		require.NotNil(t, res.Error)
+		assert.Equal(t, types.DefaultErrorCode, res.Error.Code)
+		assert.Equal(t, "failed to get fork ids from state", res.Error.Message)
+	})
+}
+
+func TestGetChainConfig(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.ZKCountersLimits = state.BatchConstraintsCfg{
+		MaxTxsPerBatch:       300,
+		MaxBatchBytesSize:    120000,
+		MaxCumulativeGasUsed: 1125899906842624,
+		MaxKeccakHashes:      2145,
+		MaxPoseidonHashes:    252357,
+		MaxPoseidonPaddings:  135191,
+		MaxMemAligns:         236585,
+		MaxArithmetics:       236585,
+		MaxBinaries:          473170,
+		MaxSteps:             7570538,
+	}
+	cfg.Network = NetworkConfig{
+		L1ChainID:                 1337,
+		RollupID:                  1,
+		ZkEVMAddr:                 common.HexToAddress("0x1"),
+		RollupManagerAddr:         common.HexToAddress("0x2"),
+		PolAddr:                   common.HexToAddress("0x3"),
+		GlobalExitRootManagerAddr: common.HexToAddress("0x4"),
+	}
+	s, _, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	res, err := s.JSONRPCCall("zkevm_getChainConfig")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	var result types.ChainConfig
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	assert.Equal(t, types.ChainConfig{
+		ChainID:   types.ArgUint64(chainID),
+		L1ChainID: 1337,
+		RollupID:  1,
+		Contracts: types.ChainContracts{
+			ZkEVMAddr:                 common.HexToAddress("0x1"),
+			RollupManagerAddr:         common.HexToAddress("0x2"),
+			PolAddr:                   common.HexToAddress("0x3"),
+			GlobalExitRootManagerAddr: common.HexToAddress("0x4"),
+		},
+		BatchConstraints: types.BatchConstraints{
+			MaxTxsPerBatch:       300,
+			MaxBatchBytesSize:    120000,
+			MaxCumulativeGasUsed: 1125899906842624,
+			MaxKeccakHashes:      2145,
+			MaxPoseidonHashes:    252357,
+			MaxPoseidonPaddings:  135191,
+			MaxMemAligns:         236585,
+			MaxArithmetics:       236585,
+			MaxBinaries:          473170,
+			MaxSteps:             7570538,
+		},
+	}, result)
+}
+
+func TestGetLogsPaginated(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.MaxLogsCount = 100
+	cfg.MaxLogsBlockRange = 10
+	s, m, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	addresses := []common.Address{common.HexToAddress("0x111")}
+	filter := map[string]interface{}{
+		"address":   addresses[0].String(),
+		"fromBlock": "0x1",
+		"toBlock":   "0x20",
+	}
+	logs := []*ethTypes.Log{
+		{Address: addresses[0], BlockNumber: 1, Index: 0, Data: []byte{}, Topics: []common.Hash{}},
+		{Address: addresses[0], BlockNumber: 1, Index: 1, Data: []byte{}, Topics: []common.Hash{}},
+		{Address: addresses[0], BlockNumber: 3, Index: 0, Data: []byte{}, Topics: []common.Hash{}},
+	}
+
+	type testCase struct {
+		Name           string
+		Params         []interface{}
+		ExpectedLogs   int
+		ExpectedCursor *string
+		ExpectedError  types.Error
+		SetupMocks     func(m *mocksWrapper)
+	}
+
+	cursorOf := func(blockNumber, logIndex uint64) *string {
+		c := LogsCursor{BlockNumber: blockNumber, LogIndex: logIndex}.Encode()
+		return &c
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "page limited by the number of logs",
+			Params:         []interface{}{filter, nil, "0x2"},
+			ExpectedLogs:   2,
+			ExpectedCursor: cursorOf(3, 0),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLogsPage", context.Background(), uint64(1), uint64(0), uint64(10), addresses, [][]common.Hash(nil), uint64(3), m.DbTx).Return(logs, nil).Once()
+			},
+		},
+		{
+			Name:           "page limited by the block range",
+			Params:         []interface{}{filter, *cursorOf(3, 0), "0x2"},
+			ExpectedLogs:   1,
+			ExpectedCursor: cursorOf(13, 0),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLogsPage", context.Background(), uint64(3), uint64(0), uint64(12), addresses, [][]common.Hash(nil), uint64(3), m.DbTx).Return(logs[2:], nil).Once()
+			},
+		},
+		{
+			Name:           "remaining range one block longer than the block range",
+			Params:         []interface{}{filter, *cursorOf(22, 0)},
+			ExpectedLogs:   0,
+			ExpectedCursor: cursorOf(32, 0),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLogsPage", context.Background(), uint64(22), uint64(0), uint64(31), addresses, [][]common.Hash(nil), uint64(101), m.DbTx).Return([]*ethTypes.Log{}, nil).Once()
+			},
+		},
+		{
+			Name:         "last page",
+			Params:       []interface{}{filter, *cursorOf(30, 0)},
+			ExpectedLogs: 0,
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLogsPage", context.Background(), uint64(30), uint64(0), uint64(32), addresses, [][]common.Hash(nil), uint64(101), m.DbTx).Return([]*ethTypes.Log{}, nil).Once()
+			},
+		},
+		{
+			Name:          "cursor before the filter range",
+			Params:        []interface{}{map[string]interface{}{"fromBlock": "0x5", "toBlock": "0x20"}, *cursorOf(1, 0)},
+			ExpectedError: types.NewRPCError(types.InvalidParamsErrorCode, "invalid cursor"),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+			},
+		},
+		{
+			Name:          "malformed cursor",
+			Params:        []interface{}{filter, "0x1234"},
+			ExpectedError: types.NewRPCError(types.InvalidParamsErrorCode, "invalid cursor"),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+			},
+		},
+		{
+			Name:          "limit zero",
+			Params:        []interface{}{filter, nil, "0x0"},
+			ExpectedError: types.NewRPCError(types.InvalidParamsErrorCode, "limit must be greater than zero"),
+			SetupMocks:    func(m *mocksWrapper) {},
+		},
+		{
+			Name:          "limit above max logs count",
+			Params:        []interface{}{filter, nil, "0x65"},
+			ExpectedError: types.NewRPCError(types.InvalidParamsErrorCode, "limit must be less than or equal to 100"),
+			SetupMocks:    func(m *mocksWrapper) {},
+		},
+		{
+			Name:          "failed to get logs",
+			Params:        []interface{}{filter},
+			ExpectedError: types.NewRPCError(types.DefaultErrorCode, "failed to get logs from state"),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLogsPage", context.Background(), uint64(1), uint64(0), uint64(10), addresses, [][]common.Hash(nil), uint64(101), m.DbTx).Return(nil, errors.New("failed to get logs")).Once()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("zkevm_getLogsPaginated", tc.Params...)
+			require.NoError(t, err)
+
+			if tc.ExpectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+				return
+			}
+
+			require.Nil(t, res.Error)
+			var result types.LogsPage
+			require.NoError(t, json.Unmarshal(res.Result, &result))
+			assert.Len(t, result.Logs, tc.ExpectedLogs)
+			assert.Equal(t, tc.ExpectedCursor, result.Cursor)
+		})
+	}
+}
+
+func TestGetTransactionStatus(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	txHash := common.HexToHash("0x1")
+	failedReason := "out of counters"
+	failedStatus := pool.TxStatusFailed.String()
+	receivedAt := time.Unix(1700000000, 0)
+	history := []pool.TxStatusTransition{
+		{Status: pool.TxStatusPending, ChangedAt: receivedAt},
+		{Status: pool.TxStatusFailed, FailedReason: &failedReason, ChangedAt: receivedAt.Add(time.Second)},
+	}
+
+	type testCase struct {
+		Name           string
+		ExpectedResult *types.TransactionStatus
+		ExpectedError  types.Error
+		SetupMocks     func(m *mocksWrapper)
+	}
+
+	testCases := []testCase{
+		{
+			Name: "failed tx in the pool",
+			ExpectedResult: &types.TransactionStatus{
+				Hash:         txHash,
+				PoolStatus:   &failedStatus,
+				FailedReason: &failedReason,
+				History: []types.TransactionStatusTransition{
+					{Status: "pending", ChangedAt: types.ArgUint64(receivedAt.Unix())},
+					{Status: "failed", FailedReason: &failedReason, ChangedAt: types.ArgUint64(receivedAt.Unix() + 1)},
+				},
+			},
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.Pool.On("GetTxByHash", context.Background(), txHash).Return(&pool.Transaction{Status: pool.TxStatusFailed, FailedReason: &failedReason}, nil).Once()
+				m.Pool.On("GetTxStatusHistory", context.Background(), txHash).Return(history, nil).Once()
+				m.State.On("GetTransactionReceipt", context.Background(), txHash, m.DbTx).Return(nil, state.ErrNotFound).Once()
+			},
+		},
+		{
+			Name: "virtualized tx deleted from the pool",
+			ExpectedResult: &types.TransactionStatus{
+				Hash:        txHash,
+				BlockNumber: types.ArgUint64Ptr(5),
+				BatchNumber: types.ArgUint64Ptr(3),
+				Virtualized: true,
+				History: []types.TransactionStatusTransition{
+					{Status: "pending", ChangedAt: types.ArgUint64(receivedAt.Unix())},
+				},
+			},
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.Pool.On("GetTxByHash", context.Background(), txHash).Return(nil, pool.ErrNotFound).Once()
+				m.Pool.On("GetTxStatusHistory", context.Background(), txHash).Return(history[:1], nil).Once()
+				m.State.On("GetTransactionReceipt", context.Background(), txHash, m.DbTx).Return(&ethTypes.Receipt{BlockNumber: big.NewInt(5)}, nil).Once()
+				m.State.On("BatchNumberByL2BlockNumber", context.Background(), uint64(5), m.DbTx).Return(uint64(3), nil).Once()
+				m.State.On("IsL2BlockVirtualized", context.Background(), uint64(5), m.DbTx).Return(true, nil).Once()
+				m.State.On("IsL2BlockConsolidated", context.Background(), uint64(5), m.DbTx).Return(false, nil).Once()
+			},
+		},
+		{
+			Name:           "unknown tx",
+			ExpectedResult: nil,
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.Pool.On("GetTxByHash", context.Background(), txHash).Return(nil, pool.ErrNotFound).Once()
+				m.Pool.On("GetTxStatusHistory", context.Background(), txHash).Return([]pool.TxStatusTransition{}, nil).Once()
+				m.State.On("GetTransactionReceipt", context.Background(), txHash, m.DbTx).Return(nil, state.ErrNotFound).Once()
+			},
+		},
+		{
+			Name:          "failed to get tx from pool",
+			ExpectedError: types.NewRPCError(types.DefaultErrorCode, "failed to get tx from pool"),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.Pool.On("GetTxByHash", context.Background(), txHash).Return(nil, errors.New("failed to get tx")).Once()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("zkevm_getTransactionStatus", txHash.String())
+			require.NoError(t, err)
+
+			if tc.ExpectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+				return
+			}
+
+			require.Nil(t, res.Error)
+			var result *types.TransactionStatus
+			require.NoError(t, json.Unmarshal(res.Result, &result))
+			assert.Equal(t, tc.ExpectedResult, result)
+		})
+	}
+}
@@ -38,6 +38,11 @@ type handleRequest struct {
 	HttpRequest *http.Request
 }
 
+// isIPC returns if the request was received by the ipc server
+func (r handleRequest) isIPC() bool {
+	return r.wsConn != nil && r.wsConn.IsIPC()
+}
+
 // Handler manage services to handle jsonrpc requests
 //
 // Services are public structures containing public methods
@@ -62,6 +67,7 @@ type handleRequest struct {
 // check the `eth.go` file for more example on how the methods are implemented
 type Handler struct {
 	serviceMap map[string]*serviceData
+	limiter    *rateLimiter
 }
 
 func newJSONRpcHandler() *Handler {
@@ -82,6 +88,14 @@ func (h *Handler) Handle(req handleRequest) types.Response {
 		return types.NewResponse(req.Request, nil, err)
 	}
 
+	// the ipc requests aren't limited, the access to the unix socket is
+	// already restricted by its file permissions
+	if h.limiter != nil && !req.isIPC() {
+		if err := h.limiter.allow(req.HttpRequest, req.Method); err != nil {
+			return types.NewResponse(req.Request, nil, err)
+		}
+	}
+
 	inArgsOffset := 0
 	inArgs := make([]reflect.Value, fd.inNum)
 	inArgs[0] = service.sv
@@ -1,13 +1,24 @@
 package jsonrpc
 
+import (
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+)
+
 // storageInterface json rpc internal storage to persist data
 type storageInterface interface {
+	DeleteExpiredFilters(timeout time.Duration) error
+	GetAllBatchFiltersWithWSConn() []*Filter
 	GetAllBlockFiltersWithWSConn() []*Filter
 	GetAllLogFiltersWithWSConn() []*Filter
 	GetFilter(filterID string) (*Filter, error)
+	NewBatchFilter(wsConn *concurrentWsConn, eventType state.BatchEventType) (string, error)
 	NewBlockFilter(wsConn *concurrentWsConn) (string, error)
 	NewLogFilter(wsConn *concurrentWsConn, filter LogFilter) (string, error)
 	NewPendingTransactionFilter(wsConn *concurrentWsConn) (string, error)
+	NewResumedBlockFilter(wsConn *concurrentWsConn) (string, error)
+	NewResumedLogFilter(wsConn *concurrentWsConn, filter LogFilter) (string, error)
 	UninstallFilter(filterID string) error
 	UninstallFilterByWSConn(wsConn *concurrentWsConn) error
 	UpdateFilterLastPoll(filterID string) error
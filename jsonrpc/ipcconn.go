@@ -0,0 +1,72 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net"
+
+	"github.com/gorilla/websocket"
+)
+
+// errIPCReadLimit is returned when a message read from an ipc connection
+// exceeds the read limit
+var errIPCReadLimit = errors.New("ipc: read limit exceeded")
+
+// ipcConn is a connection to the ipc server, the messages are json values
+// sent one after another, the responses are written one per line
+type ipcConn struct {
+	conn net.Conn
+	// reader limits the bytes read from the connection while decoding a
+	// message, so a message exceeding the read limit isn't kept in memory
+	reader    *io.LimitedReader
+	decoder   *json.Decoder
+	readLimit int64
+}
+
+// newIPCConn creates a new instance of ipcConn
+func newIPCConn(conn net.Conn) *ipcConn {
+	reader := &io.LimitedReader{R: conn, N: math.MaxInt64}
+	return &ipcConn{
+		conn:    conn,
+		reader:  reader,
+		decoder: json.NewDecoder(reader),
+	}
+}
+
+// ReadMessage reads the next json value from the connection
+func (c *ipcConn) ReadMessage() (messageType int, p []byte, err error) {
+	c.reader.N = math.MaxInt64
+	if c.readLimit > 0 {
+		c.reader.N = c.readLimit
+	}
+
+	var message json.RawMessage
+	if err := c.decoder.Decode(&message); err != nil {
+		if c.reader.N <= 0 {
+			return 0, nil, errIPCReadLimit
+		}
+		return 0, nil, err
+	}
+	return websocket.TextMessage, message, nil
+}
+
+// WriteMessage writes the message to the connection followed by a new line
+func (c *ipcConn) WriteMessage(messageType int, data []byte) error {
+	message := make([]byte, 0, len(data)+1)
+	message = append(message, data...)
+	message = append(message, '\n')
+	_, err := c.conn.Write(message)
+	return err
+}
+
+// Close closes the connection
+func (c *ipcConn) Close() error {
+	return c.conn.Close()
+}
+
+// SetReadLimit sets the maximum size of the messages read from the connection
+func (c *ipcConn) SetReadLimit(limit int64) {
+	c.readLimit = limit
+}
@@ -12,8 +12,15 @@ const (
 	requestPrefix       = prefix + "request_"
 	requestsHandledName = requestPrefix + "handled"
 	requestDurationName = requestPrefix + "duration"
+	requestLimitName    = requestPrefix + "limit_exceeded"
+	cachePrefix         = prefix + "cache_"
+	cacheHitsName       = cachePrefix + "hits"
+	cacheMissesName     = cachePrefix + "misses"
 
 	requestHandledTypeLabelName = "type"
+	requestMethodLabelName      = "method"
+	requestCallerLabelName      = "caller"
+	requestReasonLabelName      = "reason"
 )
 
 // RequestHandledLabel represents the possible values for the
@@ -31,6 +38,17 @@ const (
 	RequestHandledLabelBatch RequestHandledLabel = "batch"
 )
 
+// LimitExceededReason represents the possible values for the
+// `jsonrpc_request_limit_exceeded` metric `reason` label.
+type LimitExceededReason string
+
+const (
+	// LimitExceededReasonQuota represents a request exceeding the quota of the caller
+	LimitExceededReasonQuota LimitExceededReason = "quota"
+	// LimitExceededReasonMethod represents a request exceeding the limit of the method
+	LimitExceededReasonMethod LimitExceededReason = "method"
+)
+
 // Register the metrics for the jsonrpc package.
 func Register() {
 	var (
@@ -46,6 +64,27 @@ func Register() {
 			},
 			Labels: []string{requestHandledTypeLabelName},
 		},
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: requestLimitName,
+				Help: "[JSONRPC] number of requests rejected due to the rate limits",
+			},
+			Labels: []string{requestMethodLabelName, requestCallerLabelName, requestReasonLabelName},
+		},
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: cacheHitsName,
+				Help: "[JSONRPC] number of responses served from the cache",
+			},
+			Labels: []string{requestMethodLabelName},
+		},
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: cacheMissesName,
+				Help: "[JSONRPC] number of responses not found in the cache",
+			},
+			Labels: []string{requestMethodLabelName},
+		},
 	}
 
 	start := 0.1
@@ -74,3 +113,25 @@ func RequestHandled(label RequestHandledLabel) {
 func RequestDuration(start time.Time) {
 	metrics.HistogramObserve(requestDurationName, time.Since(start).Seconds())
 }
+
+// RequestLimitExceeded increments the requests rejected due to the rate limits
+// counter vector by one for the given method, caller and reason.
+func RequestLimitExceeded(method, caller string, reason LimitExceededReason) {
+	counterVec, exist := metrics.CounterVec(requestLimitName)
+	if !exist {
+		return
+	}
+	counterVec.WithLabelValues(method, caller, string(reason)).Inc()
+}
+
+// CacheHit increments the responses served from the cache counter vector by
+// one for the given method.
+func CacheHit(method string) {
+	metrics.CounterVecInc(cacheHitsName, method)
+}
+
+// CacheMiss increments the responses not found in the cache counter vector by
+// one for the given method.
+func CacheMiss(method string) {
+	metrics.CounterVecInc(cacheMissesName, method)
+}
@@ -2,13 +2,48 @@
 
 package jsonrpc
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	state "github.com/0xPolygonHermez/zkevm-node/state"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
 
 // storageMock is an autogenerated mock type for the storageInterface type
 type storageMock struct {
 	mock.Mock
 }
 
+// DeleteExpiredFilters provides a mock function with given fields: timeout
+func (_m *storageMock) DeleteExpiredFilters(timeout time.Duration) error {
+	ret := _m.Called(timeout)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(time.Duration) error); ok {
+		r0 = rf(timeout)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAllBatchFiltersWithWSConn provides a mock function with given fields:
+func (_m *storageMock) GetAllBatchFiltersWithWSConn() []*Filter {
+	ret := _m.Called()
+
+	var r0 []*Filter
+	if rf, ok := ret.Get(0).(func() []*Filter); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*Filter)
+		}
+	}
+
+	return r0
+}
+
 // GetAllBlockFiltersWithWSConn provides a mock function with given fields:
 func (_m *storageMock) GetAllBlockFiltersWithWSConn() []*Filter {
 	ret := _m.Called()
@@ -67,6 +102,30 @@ func (_m *storageMock) GetFilter(filterID string) (*Filter, error) {
 	return r0, r1
 }
 
+// NewBatchFilter provides a mock function with given fields: wsConn, eventType
+func (_m *storageMock) NewBatchFilter(wsConn *concurrentWsConn, eventType state.BatchEventType) (string, error) {
+	ret := _m.Called(wsConn, eventType)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn, state.BatchEventType) (string, error)); ok {
+		return rf(wsConn, eventType)
+	}
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn, state.BatchEventType) string); ok {
+		r0 = rf(wsConn, eventType)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*concurrentWsConn, state.BatchEventType) error); ok {
+		r1 = rf(wsConn, eventType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewBlockFilter provides a mock function with given fields: wsConn
 func (_m *storageMock) NewBlockFilter(wsConn *concurrentWsConn) (string, error) {
 	ret := _m.Called(wsConn)
@@ -139,6 +198,54 @@ func (_m *storageMock) NewPendingTransactionFilter(wsConn *concurrentWsConn) (st
 	return r0, r1
 }
 
+// NewResumedBlockFilter provides a mock function with given fields: wsConn
+func (_m *storageMock) NewResumedBlockFilter(wsConn *concurrentWsConn) (string, error) {
+	ret := _m.Called(wsConn)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn) (string, error)); ok {
+		return rf(wsConn)
+	}
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn) string); ok {
+		r0 = rf(wsConn)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*concurrentWsConn) error); ok {
+		r1 = rf(wsConn)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewResumedLogFilter provides a mock function with given fields: wsConn, filter
+func (_m *storageMock) NewResumedLogFilter(wsConn *concurrentWsConn, filter LogFilter) (string, error) {
+	ret := _m.Called(wsConn, filter)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn, LogFilter) (string, error)); ok {
+		return rf(wsConn, filter)
+	}
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn, LogFilter) string); ok {
+		r0 = rf(wsConn, filter)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*concurrentWsConn, LogFilter) error); ok {
+		r1 = rf(wsConn, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UninstallFilter provides a mock function with given fields: filterID
 func (_m *storageMock) UninstallFilter(filterID string) error {
 	ret := _m.Called(filterID)
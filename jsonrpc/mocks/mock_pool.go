@@ -35,6 +35,20 @@ func (_m *PoolMock) AddTx(ctx context.Context, tx types.Transaction, ip string)
 	return r0
 }
 
+// AddTxWithConditions provides a mock function with given fields: ctx, tx, ip, conditions
+func (_m *PoolMock) AddTxWithConditions(ctx context.Context, tx types.Transaction, ip string, conditions *pool.TransactionConditions) error {
+	ret := _m.Called(ctx, tx, ip, conditions)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.Transaction, string, *pool.TransactionConditions) error); ok {
+		r0 = rf(ctx, tx, ip, conditions)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CountPendingTransactions provides a mock function with given fields: ctx
 func (_m *PoolMock) CountPendingTransactions(ctx context.Context) (uint64, error) {
 	ret := _m.Called(ctx)
@@ -159,6 +173,32 @@ func (_m *PoolMock) GetPendingTxs(ctx context.Context, limit uint64) ([]pool.Tra
 	return r0, r1
 }
 
+// GetPendingTxsByFrom provides a mock function with given fields: ctx, from
+func (_m *PoolMock) GetPendingTxsByFrom(ctx context.Context, from common.Address) ([]pool.Transaction, error) {
+	ret := _m.Called(ctx, from)
+
+	var r0 []pool.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address) ([]pool.Transaction, error)); ok {
+		return rf(ctx, from)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address) []pool.Transaction); ok {
+		r0 = rf(ctx, from)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pool.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address) error); ok {
+		r1 = rf(ctx, from)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTxByHash provides a mock function with given fields: ctx, hash
 func (_m *PoolMock) GetTxByHash(ctx context.Context, hash common.Hash) (*pool.Transaction, error) {
 	ret := _m.Called(ctx, hash)
@@ -185,6 +225,58 @@ func (_m *PoolMock) GetTxByHash(ctx context.Context, hash common.Hash) (*pool.Tr
 	return r0, r1
 }
 
+// GetTxStatusHistory provides a mock function with given fields: ctx, hash
+func (_m *PoolMock) GetTxStatusHistory(ctx context.Context, hash common.Hash) ([]pool.TxStatusTransition, error) {
+	ret := _m.Called(ctx, hash)
+
+	var r0 []pool.TxStatusTransition
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash) ([]pool.TxStatusTransition, error)); ok {
+		return rf(ctx, hash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash) []pool.TxStatusTransition); ok {
+		r0 = rf(ctx, hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pool.TxStatusTransition)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Hash) error); ok {
+		r1 = rf(ctx, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTxsByStatus provides a mock function with given fields: ctx, status, limit
+func (_m *PoolMock) GetTxsByStatus(ctx context.Context, status pool.TxStatus, limit uint64) ([]pool.Transaction, error) {
+	ret := _m.Called(ctx, status, limit)
+
+	var r0 []pool.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, pool.TxStatus, uint64) ([]pool.Transaction, error)); ok {
+		return rf(ctx, status, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, pool.TxStatus, uint64) []pool.Transaction); ok {
+		r0 = rf(ctx, status, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pool.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, pool.TxStatus, uint64) error); ok {
+		r1 = rf(ctx, status, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewPoolMock creates a new instance of PoolMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewPoolMock(t interface {
@@ -0,0 +1,639 @@
+package jsonrpc
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	openRPCVersion = "1.2.6"
+	openRPCTitle   = "zkEVM Node JSON-RPC"
+
+	openRPCSchemaRefPrefix = "#/components/schemas/"
+
+	hexQuantityPattern = "^0x(0|[1-9a-fA-F][0-9a-fA-F]*)$"
+	hexBytesPattern    = "^0x([0-9a-fA-F]{2})*$"
+	hexHashPattern     = "^0x[0-9a-fA-F]{64}$"
+	hexAddressPattern  = "^0x[0-9a-fA-F]{40}$"
+	decimalPattern     = "^[0-9]+$"
+)
+
+// OpenRPCDocument is the OpenRPC description of the methods exposed by the
+// JSON RPC server, see https://spec.open-rpc.org/
+type OpenRPCDocument struct {
+	OpenRPC    string            `json:"openrpc"`
+	Info       OpenRPCInfo       `json:"info"`
+	Methods    []OpenRPCMethod   `json:"methods"`
+	Components OpenRPCComponents `json:"components"`
+}
+
+// OpenRPCInfo contains the metadata of the described API
+type OpenRPCInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenRPCMethod describes a single JSON RPC method
+type OpenRPCMethod struct {
+	Name           string                     `json:"name"`
+	ParamStructure string                     `json:"paramStructure"`
+	Params         []OpenRPCContentDescriptor `json:"params"`
+	Result         OpenRPCContentDescriptor   `json:"result"`
+}
+
+// OpenRPCContentDescriptor describes a method parameter or a method result
+type OpenRPCContentDescriptor struct {
+	Name     string        `json:"name"`
+	Required bool          `json:"required,omitempty"`
+	Schema   OpenRPCSchema `json:"schema"`
+}
+
+// OpenRPCComponents contains the schemas referenced by the methods
+type OpenRPCComponents struct {
+	Schemas map[string]OpenRPCSchema `json:"schemas"`
+}
+
+// OpenRPCSchema is a JSON schema
+type OpenRPCSchema map[string]interface{}
+
+// NewOpenRPCDocument returns the OpenRPC document describing the methods of
+// the provided services, the same document returned by the rpc_discover
+// endpoint of a server running these services.
+//
+// The services are only inspected, so they can be zero values of the
+// endpoints structs.
+func NewOpenRPCDocument(services []Service) *OpenRPCDocument {
+	handler := newJSONRpcHandler()
+	for _, service := range services {
+		handler.registerService(service)
+	}
+	handler.registerService(Service{Name: APIRPC, Service: NewRPCEndpoints(handler)})
+	return handler.openRPCDocument()
+}
+
+// openRPCDocument builds the OpenRPC document from the reflection data of the
+// registered services
+func (h *Handler) openRPCDocument() *OpenRPCDocument {
+	builder := newOpenRPCSchemaBuilder()
+
+	serviceNames := make([]string, 0, len(h.serviceMap))
+	for serviceName := range h.serviceMap {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+
+	methods := []OpenRPCMethod{}
+	for _, serviceName := range serviceNames {
+		service := h.serviceMap[serviceName]
+		funcNames := make([]string, 0, len(service.funcMap))
+		for funcName := range service.funcMap {
+			funcNames = append(funcNames, funcName)
+		}
+		sort.Strings(funcNames)
+
+		for _, funcName := range funcNames {
+			methodName := serviceName + "_" + funcName
+			methods = append(methods, builder.method(methodName, service.funcMap[funcName]))
+		}
+	}
+
+	return &OpenRPCDocument{
+		OpenRPC: openRPCVersion,
+		Info: OpenRPCInfo{
+			Title:   openRPCTitle,
+			Version: zkevm.Version,
+		},
+		Methods: methods,
+		Components: OpenRPCComponents{
+			Schemas: builder.schemas,
+		},
+	}
+}
+
+var (
+	wsConnType      = reflect.TypeOf(&concurrentWsConn{})
+	httpRequestType = reflect.TypeOf(&http.Request{})
+)
+
+// openRPCAnnotation describes how a type is exposed in the JSON RPC
+// requests and responses when it differs from its go representation
+type openRPCAnnotation struct {
+	// param is the name used for the method parameters of this type
+	param string
+	// schema builds the json schema of the type
+	schema func(b *openRPCSchemaBuilder) OpenRPCSchema
+	// inline indicates the schema is used in place instead of being
+	// added to the components
+	inline bool
+}
+
+// openRPCAnnotations contains the annotations for the types with custom json
+// encoding, mainly the argument types defined in the jsonrpc/types package
+var openRPCAnnotations = map[reflect.Type]openRPCAnnotation{
+	reflect.TypeOf(types.ArgUint64(0)): {
+		param:  "quantity",
+		schema: hexQuantitySchema("hex encoded unsigned integer"),
+	},
+	reflect.TypeOf(types.ArgHexOrDecimalUint64(0)): {
+		param: "quantity",
+		schema: func(b *openRPCSchemaBuilder) OpenRPCSchema {
+			return OpenRPCSchema{
+				"description": "unsigned integer encoded as hex string, decimal string or number",
+				"oneOf": []OpenRPCSchema{
+					{"type": "string", "pattern": hexQuantityPattern},
+					{"type": "string", "pattern": decimalPattern},
+					{"type": "integer", "minimum": 0},
+				},
+			}
+		},
+	},
+	reflect.TypeOf(types.ArgBytes{}): {
+		param:  "data",
+		schema: hexStringSchema("hex encoded bytes", hexBytesPattern),
+	},
+	reflect.TypeOf(types.ArgBig{}): {
+		param:  "quantity",
+		schema: hexQuantitySchema("hex encoded big integer"),
+	},
+	reflect.TypeOf(types.ArgHash{}): {
+		param:  "hash",
+		schema: hexStringSchema("hex encoded 32 bytes hash", hexHashPattern),
+	},
+	reflect.TypeOf(types.ArgAddress{}): {
+		param:  "address",
+		schema: hexStringSchema("hex encoded 20 bytes address", hexAddressPattern),
+	},
+	reflect.TypeOf(common.Hash{}): {
+		param:  "hash",
+		schema: hexStringSchema("hex encoded 32 bytes hash", hexHashPattern),
+	},
+	reflect.TypeOf(common.Address{}): {
+		param:  "address",
+		schema: hexStringSchema("hex encoded 20 bytes address", hexAddressPattern),
+	},
+	reflect.TypeOf(types.BlockNumber(0)): {
+		param: "blockNumber",
+		schema: func(b *openRPCSchemaBuilder) OpenRPCSchema {
+			return OpenRPCSchema{
+				"description": "block number or block tag",
+				"oneOf": []OpenRPCSchema{
+					{"type": "string", "enum": []string{types.Earliest, types.Latest, types.Pending, types.Safe, types.Finalized}},
+					{"type": "string", "pattern": hexQuantityPattern},
+				},
+			}
+		},
+	},
+	reflect.TypeOf(types.BatchNumber(0)): {
+		param: "batchNumber",
+		schema: func(b *openRPCSchemaBuilder) OpenRPCSchema {
+			return OpenRPCSchema{
+				"description": "batch number or batch tag",
+				"oneOf": []OpenRPCSchema{
+					{"type": "string", "enum": []string{types.Earliest, types.Latest}},
+					{"type": "string", "pattern": hexQuantityPattern},
+					{"type": "string", "pattern": decimalPattern},
+				},
+			}
+		},
+	},
+	reflect.TypeOf(types.BlockNumberOrHash{}): {
+		param: "block",
+		schema: func(b *openRPCSchemaBuilder) OpenRPCSchema {
+			return OpenRPCSchema{
+				"description": "block number, block tag or block hash, also accepted as an EIP-1898 object",
+				"oneOf": []OpenRPCSchema{
+					b.schemaOf(reflect.TypeOf(types.BlockNumber(0))),
+					b.schemaOf(reflect.TypeOf(types.ArgHash{})),
+					{
+						"type": "object",
+						"properties": map[string]OpenRPCSchema{
+							types.BlockNumberKey: b.schemaOf(reflect.TypeOf(types.BlockNumber(0))),
+						},
+						"required": []string{types.BlockNumberKey},
+					},
+					{
+						"type": "object",
+						"properties": map[string]OpenRPCSchema{
+							types.BlockHashKey:        b.schemaOf(reflect.TypeOf(types.ArgHash{})),
+							types.RequireCanonicalKey: {"type": "boolean"},
+						},
+						"required": []string{types.BlockHashKey},
+					},
+				},
+			}
+		},
+	},
+	reflect.TypeOf(types.Index(0)): {
+		param: "index",
+		schema: func(b *openRPCSchemaBuilder) OpenRPCSchema {
+			return OpenRPCSchema{
+				"description": "index encoded as hex or decimal string",
+				"oneOf": []OpenRPCSchema{
+					{"type": "string", "pattern": hexQuantityPattern},
+					{"type": "string", "pattern": decimalPattern},
+				},
+			}
+		},
+	},
+	reflect.TypeOf(types.IndexOrHash{}): {
+		param: "indexOrHash",
+		schema: func(b *openRPCSchemaBuilder) OpenRPCSchema {
+			return OpenRPCSchema{
+				"description": "index or hash",
+				"oneOf": []OpenRPCSchema{
+					b.schemaOf(reflect.TypeOf(types.Index(0))),
+					b.schemaOf(reflect.TypeOf(types.ArgHash{})),
+				},
+			}
+		},
+	},
+	reflect.TypeOf(LogFilter{}): {
+		param: "filter",
+		schema: func(b *openRPCSchemaBuilder) OpenRPCSchema {
+			hash := b.schemaOf(reflect.TypeOf(common.Hash{}))
+			address := b.schemaOf(reflect.TypeOf(common.Address{}))
+			return OpenRPCSchema{
+				"type": "object",
+				"properties": map[string]OpenRPCSchema{
+					"blockHash": hash,
+					"fromBlock": b.schemaOf(reflect.TypeOf(types.BlockNumber(0))),
+					"toBlock":   b.schemaOf(reflect.TypeOf(types.BlockNumber(0))),
+					"address": {
+						"oneOf": []OpenRPCSchema{address, {"type": "array", "items": address}},
+					},
+					"topics": {
+						"type": "array",
+						"items": OpenRPCSchema{
+							"oneOf": []OpenRPCSchema{{"type": "null"}, hash, {"type": "array", "items": hash}},
+						},
+					},
+				},
+			}
+		},
+	},
+	reflect.TypeOf(types.TransactionOrHash{}): {
+		param: "transactionOrHash",
+		schema: func(b *openRPCSchemaBuilder) OpenRPCSchema {
+			return OpenRPCSchema{
+				"oneOf": []OpenRPCSchema{
+					b.schemaOf(reflect.TypeOf(types.Transaction{})),
+					b.schemaOf(reflect.TypeOf(common.Hash{})),
+				},
+			}
+		},
+	},
+	reflect.TypeOf(types.BlockOrHash{}): {
+		param: "blockOrHash",
+		schema: func(b *openRPCSchemaBuilder) OpenRPCSchema {
+			return OpenRPCSchema{
+				"oneOf": []OpenRPCSchema{
+					b.schemaOf(reflect.TypeOf(types.Block{})),
+					b.schemaOf(reflect.TypeOf(common.Hash{})),
+				},
+			}
+		},
+	},
+	reflect.TypeOf(ethTypes.AccessTuple{}): {
+		param: "accessTuple",
+		// the access tuples json encoding only adds validations over the
+		// struct fields
+		schema: func(b *openRPCSchemaBuilder) OpenRPCSchema {
+			return b.structSchema(reflect.TypeOf(ethTypes.AccessTuple{}))
+		},
+	},
+	reflect.TypeOf(ethTypes.Log{}): {
+		param: "log",
+		// the geth logs are encoded with the same format of the rpc logs
+		schema: func(b *openRPCSchemaBuilder) OpenRPCSchema { return b.schemaOf(reflect.TypeOf(types.Log{})) },
+		inline: true,
+	},
+	reflect.TypeOf(big.Int{}): {
+		param:  "number",
+		schema: func(b *openRPCSchemaBuilder) OpenRPCSchema { return OpenRPCSchema{"type": "integer"} },
+		inline: true,
+	},
+	reflect.TypeOf(time.Time{}): {
+		param: "time",
+		schema: func(b *openRPCSchemaBuilder) OpenRPCSchema {
+			return OpenRPCSchema{"type": "string", "format": "date-time"}
+		},
+		inline: true,
+	},
+	reflect.TypeOf(json.RawMessage{}): {
+		param:  "value",
+		schema: func(b *openRPCSchemaBuilder) OpenRPCSchema { return OpenRPCSchema{} },
+		inline: true,
+	},
+}
+
+// openRPCAnyResultType is the result type of the methods whose result format
+// depends on their params, like the traces of the configured tracer, they are
+// described with an empty schema, that accepts any value
+var openRPCAnyResultType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+// openRPCResultTypes contains the types of the values returned by the
+// methods, since the endpoints return them as interface{} the result type
+// can't be taken from the reflection data. Every registered method must be
+// listed here, the methods not listed are described with an empty schema.
+var openRPCResultTypes = map[string]reflect.Type{
+	"debug_getRawBlock":                       reflect.TypeOf(types.ArgBytes{}),
+	"debug_getRawHeader":                      reflect.TypeOf(types.ArgBytes{}),
+	"debug_getRawReceipts":                    reflect.TypeOf([]types.ArgBytes{}),
+	"debug_getRawTransaction":                 reflect.TypeOf(types.ArgBytes{}),
+	"debug_traceBatchByNumber":                reflect.TypeOf([]traceBatchTransactionResponse{}),
+	"debug_traceBlockByHash":                  reflect.TypeOf([]traceBlockTransactionResponse{}),
+	"debug_traceBlockByNumber":                reflect.TypeOf([]traceBlockTransactionResponse{}),
+	"debug_traceCall":                         openRPCAnyResultType,
+	"debug_traceTransaction":                  openRPCAnyResultType,
+	"eth_blockNumber":                         reflect.TypeOf(types.ArgUint64(0)),
+	"eth_call":                                reflect.TypeOf(types.ArgBytes{}),
+	"eth_chainId":                             reflect.TypeOf(types.ArgUint64(0)),
+	"eth_coinbase":                            reflect.TypeOf(common.Address{}),
+	"eth_createAccessList":                    reflect.TypeOf(types.AccessListResult{}),
+	"eth_estimateGas":                         reflect.TypeOf(types.ArgUint64(0)),
+	"eth_feeHistory":                          reflect.TypeOf(types.FeeHistory{}),
+	"eth_gasPrice":                            reflect.TypeOf(types.ArgUint64(0)),
+	"eth_getBalance":                          reflect.TypeOf(types.ArgBig{}),
+	"eth_getBlockByHash":                      reflect.TypeOf(types.Block{}),
+	"eth_getBlockByNumber":                    reflect.TypeOf(types.Block{}),
+	"eth_getBlockReceipts":                    reflect.TypeOf([]types.Receipt{}),
+	"eth_getBlockTransactionCountByHash":      reflect.TypeOf(types.ArgUint64(0)),
+	"eth_getBlockTransactionCountByNumber":    reflect.TypeOf(types.ArgUint64(0)),
+	"eth_getCode":                             reflect.TypeOf(types.ArgBytes{}),
+	"eth_getCompilers":                        reflect.TypeOf([]string{}),
+	"eth_getFilterChanges":                    openRPCAnyResultType,
+	"eth_getFilterLogs":                       reflect.TypeOf([]types.Log{}),
+	"eth_getLogs":                             reflect.TypeOf([]types.Log{}),
+	"eth_getProof":                            reflect.TypeOf(types.AccountProof{}),
+	"eth_getStorageAt":                        reflect.TypeOf(types.ArgBytes{}),
+	"eth_getTransactionByBlockHashAndIndex":   reflect.TypeOf(types.Transaction{}),
+	"eth_getTransactionByBlockNumberAndIndex": reflect.TypeOf(types.Transaction{}),
+	"eth_getTransactionByHash":                reflect.TypeOf(types.Transaction{}),
+	"eth_getTransactionCount":                 reflect.TypeOf(types.ArgUint64(0)),
+	"eth_getTransactionReceipt":               reflect.TypeOf(types.Receipt{}),
+	"eth_getUncleByBlockHashAndIndex":         reflect.TypeOf(types.Block{}),
+	"eth_getUncleByBlockNumberAndIndex":       reflect.TypeOf(types.Block{}),
+	"eth_getUncleCountByBlockHash":            reflect.TypeOf(types.ArgUint64(0)),
+	"eth_getUncleCountByBlockNumber":          reflect.TypeOf(types.ArgUint64(0)),
+	"eth_maxPriorityFeePerGas":                reflect.TypeOf(types.ArgUint64(0)),
+	"eth_newBlockFilter":                      reflect.TypeOf(""),
+	"eth_newFilter":                           reflect.TypeOf(""),
+	"eth_newPendingTransactionFilter":         reflect.TypeOf(""),
+	"eth_protocolVersion":                     reflect.TypeOf(types.ArgUint64(0)),
+	"eth_sendRawTransaction":                  reflect.TypeOf(common.Hash{}),
+	"eth_sendRawTransactionConditional":       reflect.TypeOf(common.Hash{}),
+	"eth_sendRawTransactionSync":              reflect.TypeOf(types.Receipt{}),
+	"eth_subscribe":                           reflect.TypeOf(""),
+	"eth_syncing":                             openRPCAnyResultType,
+	"eth_uninstallFilter":                     reflect.TypeOf(false),
+	"eth_unsubscribe":                         reflect.TypeOf(false),
+	"net_version":                             reflect.TypeOf(""),
+	"ots_getApiLevel":                         reflect.TypeOf(0),
+	"ots_getBlockDetails":                     reflect.TypeOf(otsBlockDetails{}),
+	"ots_getBlockTransactions":                reflect.TypeOf(otsBlockTransactions{}),
+	"ots_getContractCreator":                  reflect.TypeOf(otsContractCreator{}),
+	"ots_getInternalOperations":               reflect.TypeOf([]otsInternalOperation{}),
+	"ots_getTransactionBySenderAndNonce":      reflect.TypeOf(common.Hash{}),
+	"ots_getTransactionError":                 reflect.TypeOf(types.ArgBytes{}),
+	"ots_hasCode":                             reflect.TypeOf(false),
+	"ots_searchTransactionsAfter":             reflect.TypeOf(otsTransactionsPage{}),
+	"ots_searchTransactionsBefore":            reflect.TypeOf(otsTransactionsPage{}),
+	"ots_traceTransaction":                    reflect.TypeOf([]otsTraceEntry{}),
+	"rpc_discover":                            reflect.TypeOf(OpenRPCDocument{}),
+	"trace_block":                             reflect.TypeOf([]json.RawMessage{}),
+	"trace_filter":                            reflect.TypeOf([]json.RawMessage{}),
+	"trace_replayBlockTransactions":           reflect.TypeOf([]traceReplayTransactionResponse{}),
+	"trace_transaction":                       reflect.TypeOf([]json.RawMessage{}),
+	"txpool_content":                          reflect.TypeOf(contentResponse{}),
+	"txpool_contentFrom":                      reflect.TypeOf(contentFromResponse{}),
+	"txpool_inspect":                          reflect.TypeOf(inspectResponse{}),
+	"txpool_status":                           reflect.TypeOf(statusResponse{}),
+	"web3_clientVersion":                      reflect.TypeOf(""),
+	"web3_sha3":                               reflect.TypeOf(types.ArgBytes{}),
+	"zkevm_batchNumber":                       reflect.TypeOf(types.ArgUint64(0)),
+	"zkevm_batchNumberByBlockNumber":          reflect.TypeOf(types.ArgUint64(0)),
+	"zkevm_consolidatedBlockNumber":           reflect.TypeOf(types.ArgUint64(0)),
+	"zkevm_estimateCounters":                  reflect.TypeOf(types.ZKCountersResponse{}),
+	"zkevm_getBatchByNumber":                  reflect.TypeOf(types.Batch{}),
+	"zkevm_getChainConfig":                    reflect.TypeOf(types.ChainConfig{}),
+	"zkevm_getForkId":                         reflect.TypeOf(types.ArgUint64(0)),
+	"zkevm_getForks":                          reflect.TypeOf([]types.ForkIDInterval{}),
+	"zkevm_getFullBlockByHash":                reflect.TypeOf(types.Block{}),
+	"zkevm_getFullBlockByNumber":              reflect.TypeOf(types.Block{}),
+	"zkevm_getL1InfoTreeLeaf":                 reflect.TypeOf(types.L1InfoTreeLeaf{}),
+	"zkevm_getL1InfoTreeProof":                reflect.TypeOf(types.L1InfoTreeProof{}),
+	"zkevm_getLogsPaginated":                  reflect.TypeOf(types.LogsPage{}),
+	"zkevm_getNativeBlockHashesInRange":       reflect.TypeOf([]common.Hash{}),
+	"zkevm_getTransactionStatus":              reflect.TypeOf(types.TransactionStatus{}),
+	"zkevm_isBlockConsolidated":               reflect.TypeOf(false),
+	"zkevm_isBlockVirtualized":                reflect.TypeOf(false),
+	"zkevm_verifiedBatchNumber":               reflect.TypeOf(types.ArgUint64(0)),
+	"zkevm_virtualBatchNumber":                reflect.TypeOf(types.ArgUint64(0)),
+}
+
+func hexQuantitySchema(description string) func(b *openRPCSchemaBuilder) OpenRPCSchema {
+	return hexStringSchema(description, hexQuantityPattern)
+}
+
+func hexStringSchema(description, pattern string) func(b *openRPCSchemaBuilder) OpenRPCSchema {
+	return func(b *openRPCSchemaBuilder) OpenRPCSchema {
+		return OpenRPCSchema{"type": "string", "pattern": pattern, "description": description}
+	}
+}
+
+// openRPCSchemaBuilder converts go types into json schemas, named types are
+// added to the components of the document and referenced by the methods
+type openRPCSchemaBuilder struct {
+	annotations map[reflect.Type]openRPCAnnotation
+	schemas     map[string]OpenRPCSchema
+	names       map[reflect.Type]string
+}
+
+func newOpenRPCSchemaBuilder() *openRPCSchemaBuilder {
+	return &openRPCSchemaBuilder{
+		annotations: openRPCAnnotations,
+		schemas:     map[string]OpenRPCSchema{},
+		names:       map[reflect.Type]string{},
+	}
+}
+
+// method describes the method from its reflection data, the parameters
+// injected by the server, like the websocket connection or the http request,
+// aren't part of the method params
+func (b *openRPCSchemaBuilder) method(name string, fd *funcData) OpenRPCMethod {
+	params := []OpenRPCContentDescriptor{}
+	usedNames := map[string]bool{}
+	for i := 1; i < fd.inNum; i++ {
+		t := fd.reqt[i]
+		if t == wsConnType || t == httpRequestType {
+			continue
+		}
+		paramName := openRPCParamName(t, len(params))
+		if usedNames[paramName] {
+			paramName = fmt.Sprintf("%s%d", paramName, len(params)+1)
+		}
+		usedNames[paramName] = true
+		params = append(params, OpenRPCContentDescriptor{
+			Name:     paramName,
+			Required: t.Kind() != reflect.Ptr && t.Kind() != reflect.Map,
+			Schema:   b.schemaOf(t),
+		})
+	}
+
+	resultSchema := OpenRPCSchema{}
+	if resultType, found := openRPCResultTypes[name]; found {
+		resultSchema = b.schemaOf(resultType)
+	}
+
+	return OpenRPCMethod{
+		Name:           name,
+		ParamStructure: "by-position",
+		Params:         params,
+		Result: OpenRPCContentDescriptor{
+			Name:   "result",
+			Schema: resultSchema,
+		},
+	}
+}
+
+// openRPCParamName returns the name of a param based on its type, since the
+// reflection data doesn't contain the names of the function params
+func openRPCParamName(t reflect.Type, position int) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if annotation, found := openRPCAnnotations[t]; found {
+		return annotation.param
+	}
+	if t.Name() == "" || t.PkgPath() == "" {
+		return fmt.Sprintf("param%d", position+1)
+	}
+	return lowerCaseFirst(strings.TrimPrefix(t.Name(), "Arg"))
+}
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// schemaOf returns the json schema of the provided type
+func (b *openRPCSchemaBuilder) schemaOf(t reflect.Type) OpenRPCSchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if annotation, found := b.annotations[t]; found {
+		if annotation.inline {
+			return annotation.schema(b)
+		}
+		return b.ref(t, func() OpenRPCSchema { return annotation.schema(b) })
+	}
+
+	if t.Name() != "" && reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return b.ref(t, func() OpenRPCSchema { return OpenRPCSchema{"type": "string"} })
+	}
+
+	// the format of the types with custom json encoding that aren't
+	// annotated is unknown
+	if t.Name() != "" && (reflect.PtrTo(t).Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonUnmarshalerType)) {
+		return OpenRPCSchema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return OpenRPCSchema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return OpenRPCSchema{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return OpenRPCSchema{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return OpenRPCSchema{"type": "number"}
+	case reflect.String:
+		return OpenRPCSchema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return OpenRPCSchema{"type": "string", "contentEncoding": "base64"}
+		}
+		return OpenRPCSchema{"type": "array", "items": b.schemaOf(t.Elem())}
+	case reflect.Map:
+		return OpenRPCSchema{"type": "object", "additionalProperties": b.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		return b.ref(t, func() OpenRPCSchema { return b.structSchema(t) })
+	default:
+		return OpenRPCSchema{}
+	}
+}
+
+// ref adds the schema of the type to the components, if it wasn't added yet,
+// and returns a reference to it
+func (b *openRPCSchemaBuilder) ref(t reflect.Type, build func() OpenRPCSchema) OpenRPCSchema {
+	name, found := b.names[t]
+	if !found {
+		name = t.Name()
+		if _, taken := b.schemas[name]; taken {
+			name = path.Base(t.PkgPath()) + strings.ToUpper(name[:1]) + name[1:]
+		}
+		for i := 2; b.schemas[name] != nil; i++ {
+			name = fmt.Sprintf("%s%d", t.Name(), i)
+		}
+		// the name is reserved before building the schema to support
+		// recursive types
+		b.names[t] = name
+		b.schemas[name] = OpenRPCSchema{}
+		b.schemas[name] = build()
+	}
+	return OpenRPCSchema{"$ref": openRPCSchemaRefPrefix + name}
+}
+
+// structSchema describes the exported fields of the struct using the same
+// rules as the json encoding, fields without json tag are described with the
+// first char in lower case, since the decoding isn't case sensitive
+func (b *openRPCSchemaBuilder) structSchema(t reflect.Type) OpenRPCSchema {
+	properties := map[string]OpenRPCSchema{}
+	b.addStructProperties(t, properties)
+	return OpenRPCSchema{"type": "object", "properties": properties}
+}
+
+func (b *openRPCSchemaBuilder) addStructProperties(t reflect.Type, properties map[string]OpenRPCSchema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			b.addStructProperties(fieldType, properties)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = lowerCaseFirst(field.Name)
+		}
+		properties[name] = b.schemaOf(field.Type)
+	}
+}
@@ -2,9 +2,9 @@ package jsonrpc
 
 import (
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
-	"sync"
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/hex"
@@ -12,7 +12,6 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/gorilla/websocket"
 	"github.com/jackc/pgx/v4"
 )
 
@@ -23,6 +22,9 @@ const (
 	FilterTypeBlock = "block"
 	// FilterTypePendingTx represent a filter of type pending Tx.
 	FilterTypePendingTx = "pendingTx"
+	// FilterTypeBatch represents a filter of type batch, only available for
+	// web socket subscriptions
+	FilterTypeBatch = "batch"
 )
 
 // Filter represents a filter.
@@ -33,47 +35,36 @@ type Filter struct {
 	LastPoll   time.Time
 	WsConn     *concurrentWsConn
 
-	wsQueue       *state.Queue[[]byte]
-	wsQueueSignal *sync.Cond
+	// resume holds the notifications of the newHeads and logs subscriptions
+	// while the blocks missed by the client are replayed
+	resume *subscriptionResume
 }
 
 // EnqueueSubscriptionDataToBeSent enqueues subscription data to be sent
 // via web sockets connection
 func (f *Filter) EnqueueSubscriptionDataToBeSent(data []byte) {
-	f.wsQueue.Push(data)
-	f.wsQueueSignal.Broadcast()
+	message, err := f.subscriptionMessage(data)
+	if err != nil {
+		log.Errorf("Unable to encode WS message to filter %v, %s", f.ID, err.Error())
+		return
+	}
+
+	f.WsConn.enqueueSubscriptionMessage(message)
 }
 
-// SendEnqueuedSubscriptionData consumes all the enqueued subscription data
-// and sends it via web sockets connection.
-func (f *Filter) SendEnqueuedSubscriptionData() {
-	for {
-		// wait for a signal that a new item was
-		// added to the queue
-		log.Debugf("waiting subscription data signal")
-		f.wsQueueSignal.L.Lock()
-		f.wsQueueSignal.Wait()
-		f.wsQueueSignal.L.Unlock()
-		log.Debugf("subscription data signal received, sending enqueued data")
-		for {
-			d, err := f.wsQueue.Pop()
-			if err == state.ErrQueueEmpty {
-				break
-			} else if err != nil {
-				log.Errorf("failed to pop subscription data from queue to be sent via web sockets to filter %v, %s", f.ID, err.Error())
-				break
-			}
-			f.sendSubscriptionResponse(d)
-		}
+// enqueueReplayedSubscriptionData enqueues the subscription data of a replayed
+// block waiting for room in the queue, so the replayed data isn't dropped by
+// the overflow policy, it returns false if the connection is closed
+func (f *Filter) enqueueReplayedSubscriptionData(data []byte) (bool, error) {
+	message, err := f.subscriptionMessage(data)
+	if err != nil {
+		return false, err
 	}
+	return f.WsConn.enqueueSubscriptionMessageWait(message), nil
 }
 
-// sendSubscriptionResponse send data as subscription response via
-// web sockets connection controlled by a mutex
-func (f *Filter) sendSubscriptionResponse(data []byte) {
-	const errMessage = "Unable to write WS message to filter %v, %s"
-
-	start := time.Now()
+// subscriptionMessage returns the subscription notification with the data
+func (f *Filter) subscriptionMessage(data []byte) ([]byte, error) {
 	res := types.SubscriptionResponse{
 		JSONRPC: "2.0",
 		Method:  "eth_subscription",
@@ -82,19 +73,67 @@ func (f *Filter) sendSubscriptionResponse(data []byte) {
 			Result:       data,
 		},
 	}
-	message, err := json.Marshal(res)
-	if err != nil {
-		log.Errorf(fmt.Sprintf(errMessage, f.ID, err.Error()))
+	return json.Marshal(res)
+}
+
+// EnqueueBlockSubscriptionDataToBeSent enqueues the subscription data of a
+// block to be sent via web sockets connection, while the subscription is being
+// resumed the data is held until the missed blocks are replayed and the data
+// of the blocks already replayed is discarded
+func (f *Filter) EnqueueBlockSubscriptionDataToBeSent(blockNumber uint64, data ...[]byte) {
+	if f.resume == nil {
+		for _, d := range data {
+			f.EnqueueSubscriptionDataToBeSent(d)
+		}
 		return
 	}
 
-	err = f.WsConn.WriteMessage(websocket.TextMessage, message)
-	if err != nil {
-		log.Errorf(fmt.Sprintf(errMessage, f.ID, err.Error()))
+	f.resume.mutex.Lock()
+	defer f.resume.mutex.Unlock()
+	if f.resume.resuming {
+		if f.resume.overflowed {
+			return
+		}
+		if len(f.resume.held) >= maxSubscriptionResumeHeldBlocks {
+			// the client can't get the blocks in order anymore, so it's
+			// disconnected to resume the subscription again
+			f.resume.overflowed = true
+			f.resume.held = nil
+			log.Warnf("Disconnecting client: %s", errSubscriptionResumeOverflow.Error())
+			go f.WsConn.disconnect(errSubscriptionResumeOverflow)
+			return
+		}
+		f.resume.held = append(f.resume.held, heldBlockData{blockNumber: blockNumber, data: data})
+		return
+	}
+	if blockNumber <= f.resume.lastReplayedBlock {
 		return
 	}
-	log.Debugf("WS message sent: %v", string(message))
-	log.Debugf("[SendSubscriptionResponse] took %v", time.Since(start))
+	for _, d := range data {
+		f.EnqueueSubscriptionDataToBeSent(d)
+	}
+}
+
+// finishResume stops holding the subscription data and enqueues the data held
+// for the blocks after the last replayed block
+func (f *Filter) finishResume(lastReplayedBlock uint64) {
+	if f.resume == nil {
+		return
+	}
+
+	f.resume.mutex.Lock()
+	defer f.resume.mutex.Unlock()
+	f.resume.resuming = false
+	f.resume.lastReplayedBlock = lastReplayedBlock
+	for _, held := range f.resume.held {
+		if held.blockNumber <= lastReplayedBlock {
+			continue
+		}
+		for _, d := range held.data {
+			f.EnqueueSubscriptionDataToBeSent(d)
+		}
+	}
+	f.resume.held = nil
 }
 
 // FilterType express the type of the filter, block, logs, pending transactions
@@ -108,6 +147,10 @@ type LogFilter struct {
 	Addresses []common.Address
 	Topics    [][]common.Hash
 	Since     *time.Time
+
+	// ResumeFromBlock is the block the newHeads and logs subscriptions are
+	// resumed from, the missed blocks are replayed from it
+	ResumeFromBlock *types.BlockNumber
 }
 
 // addTopic adds specific topics to the log filter topics
@@ -159,7 +202,7 @@ func (f *LogFilter) MarshalJSON() ([]byte, error) {
 		fromBlock := ""
 		obj.FromBlock = &fromBlock
 	} else if f.FromBlock != nil {
-		fromBlock := hex.EncodeUint64(uint64(*f.FromBlock))
+		fromBlock := f.FromBlock.StringOrHex()
 		obj.FromBlock = &fromBlock
 	}
 
@@ -167,7 +210,7 @@ func (f *LogFilter) MarshalJSON() ([]byte, error) {
 		toBlock := ""
 		obj.ToBlock = &toBlock
 	} else if f.ToBlock != nil {
-		toBlock := hex.EncodeUint64(uint64(*f.ToBlock))
+		toBlock := f.ToBlock.StringOrHex()
 		obj.ToBlock = &toBlock
 	}
 
@@ -226,6 +269,14 @@ func (f *LogFilter) UnmarshalJSON(data []byte) error {
 		f.ToBlock = &bn
 	}
 
+	if obj.ResumeFromBlock != nil {
+		bn, err := types.StringToBlockNumber(*obj.ResumeFromBlock)
+		if err != nil {
+			return err
+		}
+		f.ResumeFromBlock = &bn
+	}
+
 	if obj.Address != nil {
 		// decode address, either "" or [""]
 		switch raw := obj.Address.(type) {
@@ -370,6 +421,44 @@ func (f *NativeBlockHashBlockRangeFilter) GetNumericBlockNumbers(ctx context.Con
 	return getNumericBlockNumbers(ctx, s, e, &f.FromBlock, &f.ToBlock, cfg.MaxNativeBlockHashBlockRange, state.ErrMaxNativeBlockHashBlockRangeLimitExceeded, dbTx)
 }
 
+// TraceFilter is a filter to filter the traces by block range and by the
+// addresses of the calls, used by trace_filter
+type TraceFilter struct {
+	FromBlock   *types.BlockNumber `json:"fromBlock"`
+	ToBlock     *types.BlockNumber `json:"toBlock"`
+	FromAddress []common.Address   `json:"fromAddress"`
+	ToAddress   []common.Address   `json:"toAddress"`
+	After       *uint64            `json:"after"`
+	Count       *uint64            `json:"count"`
+}
+
+// GetNumericBlockNumbers load the numeric block numbers from state accordingly
+// to the provided from and to block number
+func (f *TraceFilter) GetNumericBlockNumbers(ctx context.Context, cfg Config, s types.StateInterface, e types.EthermanInterface, dbTx pgx.Tx) (uint64, uint64, types.Error) {
+	return getNumericBlockNumbers(ctx, s, e, f.FromBlock, f.ToBlock, cfg.MaxTraceFilterBlockRange, state.ErrMaxTraceFilterBlockRangeLimitExceeded, dbTx)
+}
+
+// Match returns true if the from and to addresses of the trace match the filter,
+// an empty list of addresses matches any address
+func (f *TraceFilter) Match(from, to *common.Address) bool {
+	return matchTraceAddress(f.FromAddress, from) && matchTraceAddress(f.ToAddress, to)
+}
+
+func matchTraceAddress(addresses []common.Address, address *common.Address) bool {
+	if len(addresses) == 0 {
+		return true
+	}
+	if address == nil {
+		return false
+	}
+	for _, a := range addresses {
+		if a == *address {
+			return true
+		}
+	}
+	return false
+}
+
 // getNumericBlockNumbers load the numeric block numbers from state accordingly
 // to the provided from and to block number
 func getNumericBlockNumbers(ctx context.Context, s types.StateInterface, e types.EthermanInterface, fromBlock, toBlock *types.BlockNumber, maxBlockRange uint64, maxBlockRangeErr error, dbTx pgx.Tx) (uint64, uint64, types.Error) {
@@ -401,3 +490,37 @@ func getNumericBlockNumbers(ctx context.Context, s types.StateInterface, e types
 
 	return fromBlockNumber, toBlockNumber, nil
 }
+
+// logsCursorLength is the length of an encoded logs cursor, the block number
+// and the log index as big endian uint64 values
+const logsCursorLength = 16
+
+// LogsCursor is the position of the next log returned by zkevm_getLogsPaginated,
+// it's sent to the clients as an opaque hex string
+type LogsCursor struct {
+	BlockNumber uint64
+	LogIndex    uint64
+}
+
+// Encode returns the cursor as an opaque hex string
+func (c LogsCursor) Encode() string {
+	b := make([]byte, logsCursorLength)
+	binary.BigEndian.PutUint64(b[:8], c.BlockNumber)
+	binary.BigEndian.PutUint64(b[8:], c.LogIndex)
+	return hex.EncodeToHex(b)
+}
+
+// DecodeLogsCursor decodes a cursor returned by Encode
+func DecodeLogsCursor(cursor string) (LogsCursor, error) {
+	b, err := hex.DecodeHex(cursor)
+	if err != nil {
+		return LogsCursor{}, err
+	}
+	if len(b) != logsCursorLength {
+		return LogsCursor{}, fmt.Errorf("invalid cursor length %d", len(b))
+	}
+	return LogsCursor{
+		BlockNumber: binary.BigEndian.Uint64(b[:8]),
+		LogIndex:    binary.BigEndian.Uint64(b[8:]),
+	}, nil
+}
@@ -0,0 +1,307 @@
+package jsonrpc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/metrics"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultAPIKeyHeader = "X-API-Key"
+	defaultMethodCost   = 1
+	anonymousCallerName = "anonymous"
+
+	// limitersSweepInterval is how often the limiters of the callers that
+	// recovered all their credits are removed
+	limitersSweepInterval = time.Minute
+)
+
+var (
+	// ErrMissingAPIKey is returned when the api keys are required and the
+	// request doesn't provide one
+	ErrMissingAPIKey = errors.New("missing api key")
+	// ErrInvalidAPIKey is returned when the request provides an unknown api key
+	ErrInvalidAPIKey = errors.New("invalid api key")
+)
+
+// caller identifies who sends a request, the quotas are tracked by caller
+type caller struct {
+	// id is the key used to track the quotas of the caller
+	id string
+	// name is the name used to report the caller in the metrics
+	name string
+	// creditsPerSecond is the quota of the caller, zero means no limit
+	creditsPerSecond float64
+}
+
+// rateLimiter authenticates the callers via api keys and applies the caller
+// quotas and the method limits to every request handled by the server, no
+// matter if it was sent via HTTP, as part of a batch or via WebSockets
+type rateLimiter struct {
+	cfg            RateLimitConfig
+	keys           map[string]APIKeyConfig
+	methods        map[string]MethodLimitConfig
+	maxCost        uint64
+	trustedProxies []*net.IPNet
+
+	mu             sync.Mutex
+	callerLimiters map[string]*rate.Limiter
+	methodLimiters map[string]*rate.Limiter
+	lastSweep      time.Time
+}
+
+// newRateLimiter creates a rate limiter, returns nil when the rate limit is
+// disabled
+func newRateLimiter(cfg RateLimitConfig) (*rateLimiter, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	if cfg.APIKeyHeader == "" {
+		cfg.APIKeyHeader = defaultAPIKeyHeader
+	}
+
+	l := &rateLimiter{
+		cfg:            cfg,
+		keys:           make(map[string]APIKeyConfig, len(cfg.APIKeys)),
+		methods:        make(map[string]MethodLimitConfig, len(cfg.Methods)),
+		maxCost:        defaultMethodCost,
+		callerLimiters: map[string]*rate.Limiter{},
+		methodLimiters: map[string]*rate.Limiter{},
+		lastSweep:      time.Now(),
+	}
+
+	for _, key := range cfg.APIKeys {
+		if key.Key == "" {
+			return nil, fmt.Errorf("api key %q is empty", key.Name)
+		}
+		if _, found := l.keys[key.Key]; found {
+			return nil, fmt.Errorf("api key %q is duplicated", key.Name)
+		}
+		l.keys[key.Key] = key
+	}
+
+	for _, proxy := range cfg.TrustedProxies {
+		ipNet, err := parseIPNet(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q is invalid: %w", proxy, err)
+		}
+		l.trustedProxies = append(l.trustedProxies, ipNet)
+	}
+
+	for _, method := range cfg.Methods {
+		if _, found := l.methods[method.Method]; found {
+			return nil, fmt.Errorf("method %q is duplicated", method.Method)
+		}
+		if method.Cost == 0 {
+			method.Cost = defaultMethodCost
+		}
+		if method.Cost > l.maxCost {
+			l.maxCost = method.Cost
+		}
+		l.methods[method.Method] = method
+	}
+
+	return l, nil
+}
+
+// authenticate identifies the caller of the http request by its api key,
+// provided via the configured header or as the URL path, the requests without
+// api key are identified by their IP. The URL path is only used as api key
+// when it's a registered one, so any other path is ignored
+func (l *rateLimiter) authenticate(req *http.Request) (caller, error) {
+	if req == nil {
+		return caller{id: anonymousCallerName, name: anonymousCallerName, creditsPerSecond: l.cfg.AnonymousCreditsPerSecond}, nil
+	}
+
+	apiKey := req.Header.Get(l.cfg.APIKeyHeader)
+	if apiKey == "" {
+		if path := strings.Trim(req.URL.Path, "/"); path != "" {
+			if _, found := l.keys[path]; found {
+				apiKey = path
+			}
+		}
+	}
+
+	if apiKey == "" {
+		if l.cfg.APIKeyRequired {
+			return caller{}, ErrMissingAPIKey
+		}
+		return caller{
+			id:               "ip/" + l.callerIP(req),
+			name:             anonymousCallerName,
+			creditsPerSecond: l.cfg.AnonymousCreditsPerSecond,
+		}, nil
+	}
+
+	key, found := l.keys[apiKey]
+	if !found {
+		return caller{}, ErrInvalidAPIKey
+	}
+	return caller{
+		id:               "key/" + key.Key,
+		name:             key.Name,
+		creditsPerSecond: key.CreditsPerSecond,
+	}, nil
+}
+
+// allow consumes the credits of the method from the quota of the caller of
+// the http request, returns an error if the caller is not authenticated or
+// any of the limits is exceeded
+func (l *rateLimiter) allow(req *http.Request, method string) types.Error {
+	c, err := l.authenticate(req)
+	if err != nil {
+		return types.NewRPCError(types.InvalidRequestErrorCode, err.Error())
+	}
+
+	cost := uint64(defaultMethodCost)
+	methodLimit, hasMethodLimit := l.methods[method]
+	if hasMethodLimit {
+		cost = methodLimit.Cost
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.sweep(now)
+
+	var methodReservation *rate.Reservation
+	if hasMethodLimit && methodLimit.MaxRequestsPerSecond > 0 {
+		limiter := l.getLimiter(l.methodLimiters, c.id+"/"+method, methodLimit.MaxRequestsPerSecond, 1)
+		methodReservation = limiter.ReserveN(now, 1)
+		if !methodReservation.OK() || methodReservation.DelayFrom(now) > 0 {
+			methodReservation.CancelAt(now)
+			metrics.RequestLimitExceeded(method, c.name, metrics.LimitExceededReasonMethod)
+			return types.NewRPCError(types.LimitExceededErrorCode, fmt.Sprintf("method %s limit exceeded", method))
+		}
+	}
+
+	if c.creditsPerSecond > 0 {
+		limiter := l.getLimiter(l.callerLimiters, c.id, c.creditsPerSecond, l.maxCost)
+		reservation := limiter.ReserveN(now, int(cost))
+		if !reservation.OK() || reservation.DelayFrom(now) > 0 {
+			reservation.CancelAt(now)
+			if methodReservation != nil {
+				methodReservation.CancelAt(now)
+			}
+			metrics.RequestLimitExceeded(method, c.name, metrics.LimitExceededReasonQuota)
+			return types.NewRPCError(types.LimitExceededErrorCode, "quota exceeded")
+		}
+	}
+
+	return nil
+}
+
+// getLimiter returns the limiter for the given id, creating it if needed. The
+// burst is at least the credits of a second and at least the given min burst,
+// so any allowed request fits in the bucket
+func (l *rateLimiter) getLimiter(limiters map[string]*rate.Limiter, id string, perSecond float64, minBurst uint64) *rate.Limiter {
+	limiter, found := limiters[id]
+	if !found {
+		burst := int(math.Ceil(perSecond))
+		if burst < int(minBurst) {
+			burst = int(minBurst)
+		}
+		limiter = rate.NewLimiter(rate.Limit(perSecond), burst)
+		limiters[id] = limiter
+	}
+	return limiter
+}
+
+// sweep removes the limiters that recovered all their credits, since they
+// are equivalent to a new limiter
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < limitersSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for _, limiters := range []map[string]*rate.Limiter{l.callerLimiters, l.methodLimiters} {
+		for id, limiter := range limiters {
+			if limiter.TokensAt(now) >= float64(limiter.Burst()) {
+				delete(limiters, id)
+			}
+		}
+	}
+}
+
+// callerIP returns the IP of the caller of the request, which is the remote
+// address of the request unless it was sent by a trusted proxy, in that case
+// it's the last IP of the X-Forwarded-For header that isn't a trusted proxy,
+// or the X-Real-IP header if there is no X-Forwarded-For header
+func (l *rateLimiter) callerIP(req *http.Request) string {
+	ip := remoteIP(req)
+	if !l.isTrustedProxy(ip) {
+		return ip
+	}
+
+	if forwardedFor := req.Header.Values("X-Forwarded-For"); len(forwardedFor) > 0 {
+		hops := strings.Split(strings.Join(forwardedFor, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if hop == "" {
+				continue
+			}
+			ip = hop
+			if !l.isTrustedProxy(ip) {
+				break
+			}
+		}
+		return ip
+	}
+
+	if realIP := strings.TrimSpace(req.Header.Get("X-Real-IP")); realIP != "" {
+		return realIP
+	}
+
+	return ip
+}
+
+// isTrustedProxy checks if the IP belongs to one of the trusted proxies
+func (l *rateLimiter) isTrustedProxy(ip string) bool {
+	parsedIP := net.ParseIP(ip)
+	if parsedIP == nil {
+		return false
+	}
+	for _, proxy := range l.trustedProxies {
+		if proxy.Contains(parsedIP) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIPNet parses an IP or a CIDR, a single IP is a network with only
+// that IP
+func parseIPNet(s string) (*net.IPNet, error) {
+	if strings.Contains(s, "/") {
+		_, ipNet, err := net.ParseCIDR(s)
+		return ipNet, err
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP %s", s)
+	}
+	if ipv4 := ip.To4(); ipv4 != nil {
+		return &net.IPNet{IP: ipv4, Mask: net.CIDRMask(net.IPv4len*8, net.IPv4len*8)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(net.IPv6len*8, net.IPv6len*8)}, nil
+}
+
+// remoteIP returns the IP of the remote address of the request
+func remoteIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,222 @@
+package jsonrpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/client"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRequest(path, apiKey, remoteAddr string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	if apiKey != "" {
+		req.Header.Set(defaultAPIKeyHeader, apiKey)
+	}
+	req.RemoteAddr = remoteAddr
+	return req
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	l, err := newRateLimiter(RateLimitConfig{Enabled: false})
+	require.NoError(t, err)
+	assert.Nil(t, l)
+
+	_, err = newRateLimiter(RateLimitConfig{Enabled: true, APIKeys: []APIKeyConfig{{Name: "a"}}})
+	assert.EqualError(t, err, `api key "a" is empty`)
+
+	_, err = newRateLimiter(RateLimitConfig{Enabled: true, APIKeys: []APIKeyConfig{{Name: "a", Key: "k"}, {Name: "b", Key: "k"}}})
+	assert.EqualError(t, err, `api key "b" is duplicated`)
+
+	_, err = newRateLimiter(RateLimitConfig{Enabled: true, Methods: []MethodLimitConfig{{Method: "eth_call"}, {Method: "eth_call"}}})
+	assert.EqualError(t, err, `method "eth_call" is duplicated`)
+
+	_, err = newRateLimiter(RateLimitConfig{Enabled: true, TrustedProxies: []string{"10.0.0.300"}})
+	assert.EqualError(t, err, `trusted proxy "10.0.0.300" is invalid: invalid IP 10.0.0.300`)
+}
+
+func TestRateLimiterCallerIP(t *testing.T) {
+	l, err := newRateLimiter(RateLimitConfig{Enabled: true, TrustedProxies: []string{"10.0.0.1", "192.168.0.0/16"}})
+	require.NoError(t, err)
+
+	type testCase struct {
+		Name         string
+		RemoteAddr   string
+		ForwardedFor []string
+		RealIP       string
+		ExpectedIP   string
+	}
+
+	testCases := []testCase{
+		{
+			Name:         "headers of an untrusted remote address are ignored",
+			RemoteAddr:   "1.1.1.1:1234",
+			ForwardedFor: []string{"2.2.2.2"},
+			RealIP:       "3.3.3.3",
+			ExpectedIP:   "1.1.1.1",
+		},
+		{
+			Name:         "forwarded for by a trusted proxy",
+			RemoteAddr:   "10.0.0.1:1234",
+			ForwardedFor: []string{"2.2.2.2"},
+			RealIP:       "3.3.3.3",
+			ExpectedIP:   "2.2.2.2",
+		},
+		{
+			Name:         "spoofed forwarded for hops are skipped",
+			RemoteAddr:   "10.0.0.1:1234",
+			ForwardedFor: []string{"4.4.4.4, 2.2.2.2", "192.168.1.1"},
+			ExpectedIP:   "2.2.2.2",
+		},
+		{
+			Name:       "real ip sent by a trusted proxy",
+			RemoteAddr: "192.168.1.1:1234",
+			RealIP:     "3.3.3.3",
+			ExpectedIP: "3.3.3.3",
+		},
+		{
+			Name:       "trusted proxy without headers",
+			RemoteAddr: "10.0.0.1:1234",
+			ExpectedIP: "10.0.0.1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := newTestRequest("/", "", tc.RemoteAddr)
+			for _, forwardedFor := range tc.ForwardedFor {
+				req.Header.Add("X-Forwarded-For", forwardedFor)
+			}
+			if tc.RealIP != "" {
+				req.Header.Set("X-Real-IP", tc.RealIP)
+			}
+			assert.Equal(t, tc.ExpectedIP, l.callerIP(req))
+		})
+	}
+}
+
+func TestRateLimiterAuthenticate(t *testing.T) {
+	l, err := newRateLimiter(RateLimitConfig{
+		Enabled:                   true,
+		AnonymousCreditsPerSecond: 10,
+		APIKeys:                   []APIKeyConfig{{Name: "a", Key: "secret", CreditsPerSecond: 100}},
+	})
+	require.NoError(t, err)
+
+	c, err := l.authenticate(newTestRequest("/", "secret", "1.1.1.1:1234"))
+	require.NoError(t, err)
+	assert.Equal(t, caller{id: "key/secret", name: "a", creditsPerSecond: 100}, c)
+
+	c, err = l.authenticate(newTestRequest("/secret", "", "1.1.1.1:1234"))
+	require.NoError(t, err)
+	assert.Equal(t, "a", c.name)
+
+	c, err = l.authenticate(newTestRequest("/", "", "1.1.1.1:1234"))
+	require.NoError(t, err)
+	assert.Equal(t, caller{id: "ip/1.1.1.1", name: anonymousCallerName, creditsPerSecond: 10}, c)
+
+	// a path that isn't a registered api key is ignored
+	c, err = l.authenticate(newTestRequest("/unknown", "", "1.1.1.1:1234"))
+	require.NoError(t, err)
+	assert.Equal(t, caller{id: "ip/1.1.1.1", name: anonymousCallerName, creditsPerSecond: 10}, c)
+
+	_, err = l.authenticate(newTestRequest("/", "unknown", "1.1.1.1:1234"))
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	l.cfg.APIKeyRequired = true
+	_, err = l.authenticate(newTestRequest("/", "", "1.1.1.1:1234"))
+	assert.ErrorIs(t, err, ErrMissingAPIKey)
+}
+
+func TestRateLimiterAllow(t *testing.T) {
+	// the credits are recovered slow enough to not affect the test
+	const slowRate = 0.001
+	l, err := newRateLimiter(RateLimitConfig{
+		Enabled: true,
+		APIKeys: []APIKeyConfig{{Name: "a", Key: "secret", CreditsPerSecond: slowRate}},
+		Methods: []MethodLimitConfig{
+			{Method: "debug_traceTransaction", Cost: 3},
+			{Method: "eth_getLogs", MaxRequestsPerSecond: slowRate},
+		},
+	})
+	require.NoError(t, err)
+
+	assertLimitExceeded := func(t *testing.T, err types.Error, msg string) {
+		require.NotNil(t, err)
+		assert.Equal(t, types.LimitExceededErrorCode, err.ErrorCode())
+		assert.Equal(t, msg, err.Error())
+	}
+
+	t.Run("api key quota", func(t *testing.T) {
+		// the bucket of the key fits the most expensive method
+		req := newTestRequest("/", "secret", "1.1.1.1:1234")
+		assert.Nil(t, l.allow(req, "debug_traceTransaction"))
+		assertLimitExceeded(t, l.allow(req, "eth_chainId"), "quota exceeded")
+	})
+
+	t.Run("anonymous callers without quota", func(t *testing.T) {
+		req := newTestRequest("/", "", "1.1.1.1:1234")
+		for i := 0; i < 10; i++ {
+			assert.Nil(t, l.allow(req, "debug_traceTransaction"))
+		}
+	})
+
+	t.Run("method limit by caller", func(t *testing.T) {
+		req := newTestRequest("/", "", "2.2.2.2:1234")
+		assert.Nil(t, l.allow(req, "eth_getLogs"))
+		assertLimitExceeded(t, l.allow(req, "eth_getLogs"), "method eth_getLogs limit exceeded")
+		assert.Nil(t, l.allow(req, "eth_chainId"))
+
+		assert.Nil(t, l.allow(newTestRequest("/", "", "3.3.3.3:1234"), "eth_getLogs"))
+	})
+
+	t.Run("invalid api key", func(t *testing.T) {
+		err := l.allow(newTestRequest("/", "unknown", "1.1.1.1:1234"), "eth_chainId")
+		require.NotNil(t, err)
+		assert.Equal(t, types.InvalidRequestErrorCode, err.ErrorCode())
+	})
+}
+
+func TestRateLimitServer(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.RateLimit = RateLimitConfig{
+		Enabled:        true,
+		APIKeyRequired: true,
+		APIKeys:        []APIKeyConfig{{Name: "a", Key: "secret", CreditsPerSecond: 0.001}},
+		Methods:        []MethodLimitConfig{{Method: "eth_chainId", Cost: 2}},
+	}
+	s, _, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	t.Run("missing api key", func(t *testing.T) {
+		_, err := s.JSONRPCCall("eth_chainId")
+		assert.EqualError(t, err, "401 - "+ErrMissingAPIKey.Error()+"\n")
+
+		_, res, err := websocket.DefaultDialer.Dial(s.ServerWebSocketsURL, nil)
+		require.Error(t, err)
+		require.NotNil(t, res)
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	})
+
+	t.Run("unregistered path is not an api key", func(t *testing.T) {
+		_, err := client.JSONRPCCall(s.ServerURL+"/unknown", "eth_chainId")
+		assert.EqualError(t, err, "401 - "+ErrMissingAPIKey.Error()+"\n")
+	})
+
+	t.Run("batch requests share the quota", func(t *testing.T) {
+		res, err := client.JSONRPCBatchCall(s.ServerURL+"/secret",
+			client.BatchCall{Method: "eth_chainId"},
+			client.BatchCall{Method: "eth_chainId"},
+		)
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		assert.Nil(t, res[0].Error)
+		require.NotNil(t, res[1].Error)
+		assert.Equal(t, types.LimitExceededErrorCode, res[1].Error.Code)
+		assert.Equal(t, "quota exceeded", res[1].Error.Message)
+	})
+}
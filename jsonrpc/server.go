@@ -10,6 +10,10 @@ import (
 	"mime"
 	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
 	"sync"
 	"syscall"
 	"time"
@@ -34,6 +38,12 @@ const (
 	APITxPool = "txpool"
 	// APIWeb3 represents the web3 API prefix.
 	APIWeb3 = "web3"
+	// APITrace represents the trace API prefix.
+	APITrace = "trace"
+	// APIOts represents the ots API prefix.
+	APIOts = "ots"
+	// APIRPC represents the rpc API prefix.
+	APIRPC = "rpc"
 
 	wsBufferSizeLimitInBytes = 1024
 	maxRequestContentLength  = 1024 * 1024 * 5
@@ -48,13 +58,23 @@ type Server struct {
 	config     Config
 	chainID    uint64
 	handler    *Handler
+	storage    storageInterface
 	srv        *http.Server
 	wsSrv      *http.Server
 	wsUpgrader websocket.Upgrader
+	ipcLis     net.Listener
+
+	// methodCosts are the costs of the requests of a batch request
+	methodCosts map[string]uint64
+
+	// stopFiltersSweeper is closed when the server is stopped to stop
+	// deleting the expired filters
+	stopFiltersSweeper chan struct{}
 
 	connCounterMutex *sync.Mutex
 	httpConnCounter  int64
 	wsConnCounter    int64
+	ipcConnCounter   int64
 }
 
 // Service defines a struct that will provide public methods to be exposed
@@ -79,8 +99,18 @@ func NewServer(
 	storage storageInterface,
 	services []Service,
 ) *Server {
-	if cfg.WebSockets.Enabled {
+	// the subscriptions, created by websockets or ipc, and the txs waited by
+	// eth_sendRawTransactionSync are notified of the new blocks and batches
+	// by the monitors
+	monitor := cfg.WebSockets.Enabled || cfg.IPC.Enabled
+	for _, service := range services {
+		if service.Name == APIEth {
+			monitor = true
+		}
+	}
+	if monitor {
 		s.StartToMonitorNewL2Blocks()
+		s.StartToMonitorNewBatches()
 	}
 
 	handler := newJSONRpcHandler()
@@ -88,12 +118,16 @@ func NewServer(
 	for _, service := range services {
 		handler.registerService(service)
 	}
+	handler.registerService(Service{Name: APIRPC, Service: NewRPCEndpoints(handler)})
 
 	srv := &Server{
-		config:           cfg,
-		handler:          handler,
-		chainID:          chainID,
-		connCounterMutex: &sync.Mutex{},
+		config:             cfg,
+		handler:            handler,
+		storage:            storage,
+		chainID:            chainID,
+		methodCosts:        newMethodCosts(cfg.RateLimit),
+		stopFiltersSweeper: make(chan struct{}),
+		connCounterMutex:   &sync.Mutex{},
 	}
 	return srv
 }
@@ -102,13 +136,52 @@ func NewServer(
 func (s *Server) Start() error {
 	metrics.Register()
 
+	limiter, err := newRateLimiter(s.config.RateLimit)
+	if err != nil {
+		return fmt.Errorf("invalid rate limit config: %w", err)
+	}
+	s.handler.limiter = limiter
+
+	if err := s.config.WebSockets.validateSubscriptionQueueOverflowPolicy(); err != nil {
+		return fmt.Errorf("invalid websockets config: %w", err)
+	}
+
 	if s.config.WebSockets.Enabled {
 		go s.startWS()
 	}
 
+	if s.config.IPC.Enabled {
+		if err := s.listenIPC(); err != nil {
+			return fmt.Errorf("failed to start ipc server: %w", err)
+		}
+		go s.serveIPC(s.ipcLis)
+	}
+
+	if s.config.FilterStorage.FilterTimeout.Duration > 0 {
+		go s.sweepExpiredFilters()
+	}
+
 	return s.startHTTP()
 }
 
+// sweepExpiredFilters periodically deletes the filters that were not polled
+// during the configured filter timeout until the server is stopped
+func (s *Server) sweepExpiredFilters() {
+	timeout := s.config.FilterStorage.FilterTimeout.Duration
+	ticker := time.NewTicker(timeout)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.storage.DeleteExpiredFilters(timeout); err != nil {
+				log.Errorf("failed to delete expired filters: %v", err)
+			}
+		case <-s.stopFiltersSweeper:
+			return
+		}
+	}
+}
+
 // startHTTP starts a server to respond http requests
 func (s *Server) startHTTP() error {
 	if s.srv != nil {
@@ -187,8 +260,81 @@ func (s *Server) startWS() {
 	}
 }
 
+// listenIPC creates the unix socket of the ipc server with the configured
+// permissions, replacing the socket file left by a previous run. The socket is
+// created in a private directory and moved to its path once its permissions
+// are set, so it can't be connected to with the default permissions
+func (s *Server) listenIPC() error {
+	if s.ipcLis != nil {
+		return fmt.Errorf("ipc server already started")
+	}
+
+	fileMode, err := strconv.ParseUint(s.config.IPC.FileMode, 8, 32) //nolint:gomnd
+	if err != nil {
+		return fmt.Errorf("invalid ipc file mode %v: %w", s.config.IPC.FileMode, err)
+	}
+
+	path := s.config.IPC.Path
+	if err := os.MkdirAll(filepath.Dir(path), 0751); err != nil { //nolint:gomnd
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	privateDir, err := os.MkdirTemp(filepath.Dir(path), ".ipc-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(privateDir) //nolint:errcheck
+
+	privatePath := filepath.Join(privateDir, filepath.Base(path))
+	lis, err := net.Listen("unix", privatePath)
+	if err != nil {
+		log.Errorf("failed to create unix socket listener: %v", err)
+		return err
+	}
+	// the socket is removed from its final path when the server is stopped
+	lis.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(privatePath, os.FileMode(fileMode)); err != nil {
+		_ = lis.Close()
+		return err
+	}
+	if err := os.Rename(privatePath, path); err != nil {
+		_ = lis.Close()
+		return err
+	}
+
+	s.ipcLis = lis
+	log.Infof("ipc server started: %s", path)
+	return nil
+}
+
+// serveIPC accepts the connections to the ipc server, the listener is given
+// since the server forgets it when it's stopped
+func (s *Server) serveIPC(lis net.Listener) {
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				log.Infof("ipc server stopped")
+				return
+			}
+			log.Errorf("failed to accept ipc connection: %v", err)
+			continue
+		}
+		go s.handleIPC(conn)
+	}
+}
+
 // Stop shutdown the rpc server
 func (s *Server) Stop() error {
+	select {
+	case <-s.stopFiltersSweeper:
+	default:
+		close(s.stopFiltersSweeper)
+	}
+
 	if s.srv != nil {
 		if err := s.srv.Shutdown(context.Background()); err != nil {
 			return err
@@ -211,6 +357,16 @@ func (s *Server) Stop() error {
 		s.wsSrv = nil
 	}
 
+	if s.ipcLis != nil {
+		if err := s.ipcLis.Close(); err != nil {
+			return err
+		}
+		s.ipcLis = nil
+		if err := os.Remove(s.config.IPC.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -218,7 +374,11 @@ func (s *Server) handle(w http.ResponseWriter, req *http.Request) {
 	w.Header().Set("Content-Type", contentType)
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+	allowedHeaders := "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization"
+	if s.handler.limiter != nil {
+		allowedHeaders += ", " + s.handler.limiter.cfg.APIKeyHeader
+	}
+	w.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
 
 	if req.Method == http.MethodOptions {
 		return
@@ -237,6 +397,11 @@ func (s *Server) handle(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if err := s.authenticate(req); err != nil {
+		handleInvalidRequest(w, err, http.StatusUnauthorized)
+		return
+	}
+
 	body := io.LimitReader(req.Body, maxRequestContentLength)
 	data, err := io.ReadAll(body)
 	if err != nil {
@@ -264,6 +429,15 @@ func (s *Server) handle(w http.ResponseWriter, req *http.Request) {
 	s.combinedLog(req, start, http.StatusOK, respLen)
 }
 
+// authenticate checks the api key of the request when the rate limit is enabled
+func (s *Server) authenticate(req *http.Request) error {
+	if s.handler.limiter == nil {
+		return nil
+	}
+	_, err := s.handler.limiter.authenticate(req)
+	return err
+}
+
 // validateRequest returns a non-zero response code and error message if the
 // request is invalid.
 func validateRequest(req *http.Request) (int, error) {
@@ -346,13 +520,7 @@ func (s *Server) handleBatchRequest(httpRequest *http.Request, w http.ResponseWr
 		}
 	}
 
-	responses := make([]types.Response, 0, len(requests))
-
-	for _, request := range requests {
-		req := handleRequest{Request: request, HttpRequest: httpRequest}
-		response := s.handler.Handle(req)
-		responses = append(responses, response)
-	}
+	responses := s.processBatchRequest(httpRequest, requests)
 
 	respBytes, _ := json.Marshal(responses)
 	_, err = w.Write(respBytes)
@@ -387,6 +555,11 @@ func (s *Server) handleWs(w http.ResponseWriter, req *http.Request) {
 	// CORS rule - Allow requests from anywhere
 	s.wsUpgrader.CheckOrigin = func(r *http.Request) bool { return true }
 
+	if err := s.authenticate(req); err != nil {
+		handleInvalidRequest(w, err, http.StatusUnauthorized)
+		return
+	}
+
 	// Upgrade the connection to a WS one
 	innerWsConn, err := s.wsUpgrader.Upgrade(w, req, nil)
 	if err != nil {
@@ -394,7 +567,7 @@ func (s *Server) handleWs(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	wsConn := newConcurrentWsConn(innerWsConn)
+	wsConn := newConcurrentWsConn(innerWsConn, s.config.WebSockets)
 
 	// Set read limit
 	wsConn.SetReadLimit(s.config.WebSockets.ReadLimit)
@@ -435,14 +608,70 @@ func (s *Server) handleWs(w http.ResponseWriter, req *http.Request) {
 		}
 
 		if msgType == websocket.TextMessage || msgType == websocket.BinaryMessage {
-			resp, err := s.handler.HandleWs(message, wsConn, req)
-			if err != nil {
-				log.Error(fmt.Sprintf("Unable to handle WS request, %s", err.Error()))
-				_ = wsConn.WriteMessage(msgType, []byte(fmt.Sprintf("WS Handle error: %s", err.Error())))
+			wsConn.handleRequest(func() {
+				resp, err := s.handler.HandleWs(message, wsConn, req)
+				if err != nil {
+					log.Error(fmt.Sprintf("Unable to handle WS request, %s", err.Error()))
+					_ = wsConn.WriteMessage(msgType, []byte(fmt.Sprintf("WS Handle error: %s", err.Error())))
+				} else {
+					_ = wsConn.WriteMessage(msgType, resp)
+				}
+			})
+		}
+	}
+}
+
+// handleIPC serves the requests and the subscriptions of an ipc connection,
+// the requests are handled as the web socket ones
+func (s *Server) handleIPC(conn net.Conn) {
+	ipcConn := newConcurrentIPCConn(newIPCConn(conn), s.config.WebSockets)
+	ipcConn.SetReadLimit(maxRequestContentLength)
+
+	// the requests of the endpoints that depend on the http request get
+	// an empty one, since the ipc connection has no headers
+	req := &http.Request{Header: http.Header{}, URL: &url.URL{}, RemoteAddr: s.config.IPC.Path}
+
+	defer func(ipcConn *concurrentWsConn) {
+		if err := ipcConn.Close(); err != nil {
+			log.Error(fmt.Sprintf("Unable to gracefully close IPC connection, %s", err.Error()))
+		}
+	}(ipcConn)
+
+	s.increaseIPCConnCounter()
+	defer s.decreaseIPCConnCounter()
+
+	// recover
+	defer func() {
+		if err := recover(); err != nil {
+			log.Error(err)
+		}
+	}()
+	log.Info("IPC connection established")
+	for {
+		_, message, err := ipcConn.ReadMessage()
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+				log.Info("Closing IPC connection gracefully")
+			} else if errors.Is(err, errIPCReadLimit) {
+				log.Info("Closing IPC connection due to read limit exceeded")
 			} else {
-				_ = wsConn.WriteMessage(msgType, resp)
+				log.Error(fmt.Sprintf("Unable to read IPC message, %s", err.Error()))
+				log.Info("Closing IPC connection with error")
 			}
+
+			s.handler.RemoveFilterByWsConn(ipcConn)
+
+			break
 		}
+
+		ipcConn.handleRequest(func() {
+			resp, err := s.handler.HandleWs(message, ipcConn, req)
+			if err != nil {
+				log.Error(fmt.Sprintf("Unable to handle IPC request, %s", err.Error()))
+				return
+			}
+			_ = ipcConn.WriteMessage(websocket.TextMessage, resp)
+		})
 	}
 }
 
@@ -474,9 +703,23 @@ func (s *Server) decreaseWsConnCounter() {
 	s.connCounterMutex.Unlock()
 }
 
+func (s *Server) increaseIPCConnCounter() {
+	s.connCounterMutex.Lock()
+	s.ipcConnCounter++
+	s.logConnCounters()
+	s.connCounterMutex.Unlock()
+}
+
+func (s *Server) decreaseIPCConnCounter() {
+	s.connCounterMutex.Lock()
+	s.ipcConnCounter--
+	s.logConnCounters()
+	s.connCounterMutex.Unlock()
+}
+
 func (s *Server) logConnCounters() {
-	totalConnCounter := s.httpConnCounter + s.wsConnCounter
-	log.Infof("[ HTTP conns: %v | WS conns: %v | Total conns: %v ]", s.httpConnCounter, s.wsConnCounter, totalConnCounter)
+	totalConnCounter := s.httpConnCounter + s.wsConnCounter + s.ipcConnCounter
+	log.Infof("[ HTTP conns: %v | WS conns: %v | IPC conns: %v | Total conns: %v ]", s.httpConnCounter, s.wsConnCounter, s.ipcConnCounter, totalConnCounter)
 }
 
 func handleInvalidRequest(w http.ResponseWriter, err error, code int) {
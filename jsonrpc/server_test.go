@@ -3,15 +3,21 @@ package jsonrpc
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math/big"
+	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/client"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/mocks"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
@@ -19,7 +25,9 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/trie"
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -50,6 +58,7 @@ func newMockedServer(t *testing.T, cfg Config) (*mockedServer, *mocksWrapper, *e
 	st := mocks.NewStateMock(t)
 	etherman := mocks.NewEthermanMock(t)
 	storage := newStorageMock(t)
+	cache := NewConsolidatedCache(cfg.Cache, st)
 	dbTx := mocks.NewDBTxMock(t)
 	apis := map[string]bool{
 		APIEth:    true,
@@ -58,17 +67,22 @@ func newMockedServer(t *testing.T, cfg Config) (*mockedServer, *mocksWrapper, *e
 		APIZKEVM:  true,
 		APITxPool: true,
 		APIWeb3:   true,
+		APITrace:  true,
+		APIOts:    true,
 	}
 
 	var newL2BlockEventHandler state.NewL2BlockEventHandler = func(e state.NewL2BlockEvent) {}
 	st.On("RegisterNewL2BlockEventHandler", mock.IsType(newL2BlockEventHandler)).Once()
 	st.On("StartToMonitorNewL2Blocks").Once()
+	var newBatchEventHandler state.NewBatchEventHandler = func(e state.NewBatchEvent) {}
+	st.On("RegisterNewBatchEventHandler", mock.IsType(newBatchEventHandler)).Once()
+	st.On("StartToMonitorNewBatches").Once()
 
 	services := []Service{}
 	if _, ok := apis[APIEth]; ok {
 		services = append(services, Service{
 			Name:    APIEth,
-			Service: NewEthEndpoints(cfg, chainID, pool, st, etherman, storage),
+			Service: NewEthEndpoints(cfg, chainID, pool, st, etherman, storage, cache),
 		})
 	}
 
@@ -82,14 +96,14 @@ func newMockedServer(t *testing.T, cfg Config) (*mockedServer, *mocksWrapper, *e
 	if _, ok := apis[APIZKEVM]; ok {
 		services = append(services, Service{
 			Name:    APIZKEVM,
-			Service: NewZKEVMEndpoints(cfg, st, etherman),
+			Service: NewZKEVMEndpoints(cfg, chainID, pool, st, etherman, cache),
 		})
 	}
 
 	if _, ok := apis[APITxPool]; ok {
 		services = append(services, Service{
 			Name:    APITxPool,
-			Service: &TxPoolEndpoints{},
+			Service: NewTxPoolEndpoints(cfg, pool, st),
 		})
 	}
 
@@ -100,6 +114,20 @@ func newMockedServer(t *testing.T, cfg Config) (*mockedServer, *mocksWrapper, *e
 		})
 	}
 
+	if _, ok := apis[APITrace]; ok {
+		services = append(services, Service{
+			Name:    APITrace,
+			Service: NewTraceEndpoints(cfg, st, etherman),
+		})
+	}
+
+	if _, ok := apis[APIOts]; ok {
+		services = append(services, Service{
+			Name:    APIOts,
+			Service: NewOtsEndpoints(cfg, st, etherman),
+		})
+	}
+
 	if _, ok := apis[APIWeb3]; ok {
 		services = append(services, Service{
 			Name:    APIWeb3,
@@ -331,6 +359,183 @@ func TestBatchRequests(t *testing.T) {
 	}
 }
 
+func TestBatchRequestsConcurrency(t *testing.T) {
+	const numberOfRequests = 3
+
+	cfg := getSequencerDefaultConfig()
+	cfg.BatchRequestsConcurrency = numberOfRequests
+	s, m, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	// each request waits until all the requests are being processed, so the
+	// batch only finishes if they are processed concurrently
+	var wg sync.WaitGroup
+	wg.Add(numberOfRequests)
+	allStarted := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(allStarted)
+	}()
+
+	m.DbTx.On("Commit", context.Background()).Return(nil).Times(numberOfRequests)
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Times(numberOfRequests)
+	m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Run(func(args mock.Arguments) {
+		wg.Done()
+		select {
+		case <-allStarted:
+		case <-time.After(5 * time.Second):
+		}
+	}).Return(uint64(10), nil).Times(numberOfRequests)
+
+	calls := []client.BatchCall{}
+	for i := 0; i < numberOfRequests; i++ {
+		calls = append(calls, client.BatchCall{Method: "eth_blockNumber"})
+	}
+
+	start := time.Now()
+	result, err := s.JSONRPCBatchCall(calls...)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	require.Equal(t, numberOfRequests, len(result))
+	for i, res := range result {
+		assert.Equal(t, float64(i), res.ID)
+		require.Nil(t, res.Error)
+		assert.Equal(t, `"0xa"`, string(res.Result))
+	}
+}
+
+func TestBatchRequestsCostLimit(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.BatchRequestsConcurrency = 2
+	cfg.BatchRequestsCostLimit = 4
+	cfg.RateLimit.Methods = []MethodLimitConfig{{Method: "net_version", Cost: 2}}
+	s, _, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	result, err := s.JSONRPCBatchCall(
+		client.BatchCall{Method: "net_version"},
+		client.BatchCall{Method: "web3_clientVersion"},
+		client.BatchCall{Method: "net_version"},
+		client.BatchCall{Method: "web3_clientVersion"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(result))
+
+	// the costs are 2, 1 and 2, so the third request exceeds the limit and
+	// the following requests aren't processed either
+	for i, res := range result {
+		assert.Equal(t, float64(i), res.ID)
+		if i < 2 {
+			assert.Nil(t, res.Error)
+			assert.NotEmpty(t, res.Result)
+		} else {
+			require.NotNil(t, res.Error)
+			assert.Equal(t, types.LimitExceededErrorCode, res.Error.Code)
+			assert.Equal(t, types.ErrBatchRequestsCostLimitExceeded.Error(), res.Error.Message)
+			assert.Empty(t, res.Result)
+		}
+	}
+}
+
+func TestBatchRequestsTimeout(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.BatchRequestsConcurrency = 1
+	cfg.BatchRequestsTimeout.Duration = 100 * time.Millisecond
+	s, m, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+	m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Run(func(args mock.Arguments) {
+		<-release
+	}).Return(uint64(10), nil).Once()
+	m.DbTx.On("Commit", context.Background()).Run(func(args mock.Arguments) {
+		close(finished)
+	}).Return(nil).Once()
+
+	result, err := s.JSONRPCBatchCall(
+		client.BatchCall{Method: "net_version"},
+		client.BatchCall{Method: "eth_blockNumber"},
+		client.BatchCall{Method: "net_version"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(result))
+
+	// the slow request is still being processed when the timeout is reached
+	close(release)
+	<-finished
+
+	assert.Nil(t, result[0].Error)
+	assert.Equal(t, fmt.Sprintf(`"%d"`, chainID), string(result[0].Result))
+	for _, res := range result[1:] {
+		require.NotNil(t, res.Error)
+		assert.Equal(t, types.LimitExceededErrorCode, res.Error.Code)
+		assert.Equal(t, types.ErrBatchRequestsTimeoutExceeded.Error(), res.Error.Message)
+	}
+}
+
+func TestExpiredFiltersSweeper(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.WebSockets.Enabled = false
+	cfg.FilterStorage.FilterTimeout.Duration = 10 * time.Millisecond
+	storage := newStorageMock(t)
+	s := NewServer(cfg, chainID, mocks.NewPoolMock(t), mocks.NewStateMock(t), storage, nil)
+
+	swept := make(chan struct{}, 1)
+	storage.On("DeleteExpiredFilters", cfg.FilterStorage.FilterTimeout.Duration).Run(func(args mock.Arguments) {
+		select {
+		case swept <- struct{}{}:
+		default:
+		}
+	}).Return(nil)
+
+	stopped := make(chan struct{})
+	go func() {
+		s.sweepExpiredFilters()
+		close(stopped)
+	}()
+	<-swept
+
+	require.NoError(t, s.Stop())
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		require.Fail(t, "the expired filters sweeper was not stopped")
+	}
+}
+
+func TestNewServerStartsToMonitor(t *testing.T) {
+	testCases := []struct {
+		name              string
+		webSocketsEnabled bool
+		ipcEnabled        bool
+		services          []Service
+		expectedMonitors  bool
+	}{
+		{name: "websockets enabled", webSocketsEnabled: true, expectedMonitors: true},
+		{name: "ipc enabled", ipcEnabled: true, expectedMonitors: true},
+		{name: "eth service", services: []Service{{Name: APIEth, Service: &Web3Endpoints{}}}, expectedMonitors: true},
+		{name: "no subscriptions nor eth service", services: []Service{{Name: APIWeb3, Service: &Web3Endpoints{}}}, expectedMonitors: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := getSequencerDefaultConfig()
+			cfg.WebSockets.Enabled = tc.webSocketsEnabled
+			cfg.IPC.Enabled = tc.ipcEnabled
+			st := mocks.NewStateMock(t)
+			if tc.expectedMonitors {
+				st.On("StartToMonitorNewL2Blocks").Once()
+				st.On("StartToMonitorNewBatches").Once()
+			}
+
+			NewServer(cfg, chainID, mocks.NewPoolMock(t), st, newStorageMock(t), tc.services)
+		})
+	}
+}
+
 func TestRequestValidation(t *testing.T) {
 	type testCase struct {
 		Name                    string
@@ -638,3 +843,154 @@ func TestMaxRequestPerIPPerSec(t *testing.T) {
 	// connection abruptly
 	time.Sleep(time.Second)
 }
+
+func TestIPC(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.IPC = IPCConfig{
+		Enabled:  true,
+		Path:     filepath.Join(t.TempDir(), "zkevm-node.ipc"),
+		FileMode: "0660",
+	}
+	s, m, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	info, err := os.Stat(cfg.IPC.Path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0660), info.Mode().Perm())
+
+	ipcClient, err := rpc.DialIPC(context.Background(), cfg.IPC.Path)
+	require.NoError(t, err)
+
+	var version string
+	require.NoError(t, ipcClient.Call(&version, "web3_clientVersion"))
+	assert.Equal(t, zkevm.Version, version)
+
+	var ipcConn *concurrentWsConn
+	m.Storage.
+		On("NewBlockFilter", mock.IsType(&concurrentWsConn{})).
+		Run(func(args mock.Arguments) {
+			ipcConn = args.Get(0).(*concurrentWsConn)
+		}).
+		Return("0x1", nil).
+		Once()
+
+	notifications := make(chan json.RawMessage)
+	_, err = ipcClient.Subscribe(context.Background(), "eth", notifications, "newHeads")
+	require.NoError(t, err)
+	require.NotNil(t, ipcConn)
+	assert.True(t, ipcConn.IsIPC())
+
+	filter := &Filter{ID: "0x1", WsConn: ipcConn}
+	filter.EnqueueSubscriptionDataToBeSent([]byte(`{"number":"0x1"}`))
+	select {
+	case notification := <-notifications:
+		assert.JSONEq(t, `{"number":"0x1"}`, string(notification))
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "subscription notification not received")
+	}
+
+	// the filters of the connection are removed when it's closed
+	filterRemoved := make(chan struct{})
+	m.Storage.
+		On("UninstallFilterByWSConn", ipcConn).
+		Run(func(args mock.Arguments) {
+			close(filterRemoved)
+		}).
+		Return(nil).
+		Once()
+
+	ipcClient.Close()
+	select {
+	case <-filterRemoved:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "filters of the ipc connection not removed")
+	}
+
+	// the socket is removed when the server is stopped
+	s.Stop()
+	entries, err := os.ReadDir(filepath.Dir(cfg.IPC.Path))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestIPCConnReadLimit(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+
+	conn := newIPCConn(server)
+	conn.SetReadLimit(64)
+	defer conn.Close()
+
+	go func() {
+		_, _ = client.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"eth_chainId"}`))
+		_, _ = client.Write([]byte(`{"jsonrpc":"2.0","id":2,"method":"eth_chainId","params":["` + strings.Repeat("a", 1024) + `"]}`))
+	}()
+
+	_, message, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"method":"eth_chainId"}`, string(message))
+
+	_, _, err = conn.ReadMessage()
+	assert.ErrorIs(t, err, errIPCReadLimit)
+}
+
+func TestSubscriptionQueueOverflowDisconnect(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.WebSockets.SubscriptionQueueSize = 1
+	cfg.WebSockets.SubscriptionQueueOverflowPolicy = SubscriptionQueueOverflowPolicyDisconnect
+	s, m, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	var wsConn *concurrentWsConn
+	m.Storage.
+		On("NewBlockFilter", mock.IsType(&concurrentWsConn{})).
+		Run(func(args mock.Arguments) {
+			wsConn = args.Get(0).(*concurrentWsConn)
+		}).
+		Return("0x1", nil).
+		Once()
+
+	filterRemoved := make(chan struct{})
+	m.Storage.
+		On("UninstallFilterByWSConn", mock.IsType(&concurrentWsConn{})).
+		Run(func(args mock.Arguments) {
+			close(filterRemoved)
+		}).
+		Return(nil).
+		Once()
+
+	client, _, err := websocket.DefaultDialer.Dial(s.ServerWebSocketsURL, nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_subscribe","params":["newHeads"]}`)))
+	_, res, err := client.ReadMessage()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"result":"0x1"}`, string(res))
+	require.NotNil(t, wsConn)
+
+	// the notifications aren't sent while a request is handled, so they
+	// accumulate in the queue until it overflows
+	wsConn.handleRequest(func() {
+		filter := &Filter{ID: "0x1", WsConn: wsConn}
+		for i := 0; i < 3; i++ {
+			filter.EnqueueSubscriptionDataToBeSent([]byte(`{"number":"0x1"}`))
+		}
+		require.Eventually(t, wsConn.subscriptionQueue.isClosed, 5*time.Second, 10*time.Millisecond)
+	})
+
+	// the client gets the close message with the reason
+	var closeErr *websocket.CloseError
+	for err == nil {
+		_, _, err = client.ReadMessage()
+	}
+	require.ErrorAs(t, err, &closeErr)
+	assert.Equal(t, websocket.ClosePolicyViolation, closeErr.Code)
+	assert.Equal(t, errSubscriptionQueueOverflow.Error(), closeErr.Text)
+
+	select {
+	case <-filterRemoved:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "filters of the closed connection not removed")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/google/uuid"
 )
@@ -25,10 +26,12 @@ type Storage struct {
 	blockFiltersWithWSConn     map[string]*Filter
 	logFiltersWithWSConn       map[string]*Filter
 	pendingTxFiltersWithWSConn map[string]*Filter
+	batchFiltersWithWSConn     map[string]*Filter
 
 	blockMutex     *sync.Mutex
 	logMutex       *sync.Mutex
 	pendingTxMutex *sync.Mutex
+	batchMutex     *sync.Mutex
 }
 
 // NewStorage creates and initializes an instance of Storage
@@ -39,9 +42,26 @@ func NewStorage() *Storage {
 		blockFiltersWithWSConn:     make(map[string]*Filter),
 		logFiltersWithWSConn:       make(map[string]*Filter),
 		pendingTxFiltersWithWSConn: make(map[string]*Filter),
+		batchFiltersWithWSConn:     make(map[string]*Filter),
 		blockMutex:                 &sync.Mutex{},
 		logMutex:                   &sync.Mutex{},
 		pendingTxMutex:             &sync.Mutex{},
+		batchMutex:                 &sync.Mutex{},
+	}
+}
+
+// NewFilterStorage creates the filter storage defined by the provided configuration
+func NewFilterStorage(cfg FilterStorageConfig) (storageInterface, error) {
+	switch cfg.Type {
+	case FilterStorageTypeMemory, "":
+		return NewStorage(), nil
+	case FilterStorageTypePostgres:
+		if cfg.FilterTimeout.Duration == 0 {
+			log.Warn("the filters stored in postgres never expire, set FilterTimeout to remove the filters that are not polled")
+		}
+		return NewPostgresStorage(cfg.DB)
+	default:
+		return nil, fmt.Errorf("unknown filter storage type %q", cfg.Type)
 	}
 }
 
@@ -51,21 +71,43 @@ func (s *Storage) NewLogFilter(wsConn *concurrentWsConn, filter LogFilter) (stri
 		return "", err
 	}
 
-	return s.createFilter(FilterTypeLog, filter, wsConn)
+	return s.createFilter(FilterTypeLog, filter, wsConn, false)
+}
+
+// NewResumedLogFilter persists a new log filter of a subscription resumed from
+// a past block, its notifications are held until the missed blocks are replayed
+func (s *Storage) NewResumedLogFilter(wsConn *concurrentWsConn, filter LogFilter) (string, error) {
+	if err := filter.Validate(); err != nil {
+		return "", err
+	}
+
+	return s.createFilter(FilterTypeLog, filter, wsConn, true)
 }
 
 // NewBlockFilter persists a new block log filter
 func (s *Storage) NewBlockFilter(wsConn *concurrentWsConn) (string, error) {
-	return s.createFilter(FilterTypeBlock, nil, wsConn)
+	return s.createFilter(FilterTypeBlock, nil, wsConn, false)
+}
+
+// NewResumedBlockFilter persists a new block filter of a subscription resumed from
+// a past block, its notifications are held until the missed blocks are replayed
+func (s *Storage) NewResumedBlockFilter(wsConn *concurrentWsConn) (string, error) {
+	return s.createFilter(FilterTypeBlock, nil, wsConn, true)
 }
 
 // NewPendingTransactionFilter persists a new pending transaction filter
 func (s *Storage) NewPendingTransactionFilter(wsConn *concurrentWsConn) (string, error) {
-	return s.createFilter(FilterTypePendingTx, nil, wsConn)
+	return s.createFilter(FilterTypePendingTx, nil, wsConn, false)
+}
+
+// NewBatchFilter persists a new filter for the batches reaching the given
+// stage of their lifecycle
+func (s *Storage) NewBatchFilter(wsConn *concurrentWsConn, eventType state.BatchEventType) (string, error) {
+	return s.createFilter(FilterTypeBatch, eventType, wsConn, false)
 }
 
 // create persists the filter to the memory and provides the filter id
-func (s *Storage) createFilter(t FilterType, parameters interface{}, wsConn *concurrentWsConn) (string, error) {
+func (s *Storage) createFilter(t FilterType, parameters interface{}, wsConn *concurrentWsConn, resumed bool) (string, error) {
 	lastPoll := time.Now().UTC()
 	id, err := s.generateFilterID()
 	if err != nil {
@@ -75,21 +117,25 @@ func (s *Storage) createFilter(t FilterType, parameters interface{}, wsConn *con
 	s.blockMutex.Lock()
 	s.logMutex.Lock()
 	s.pendingTxMutex.Lock()
+	s.batchMutex.Lock()
 	defer s.blockMutex.Unlock()
 	defer s.logMutex.Unlock()
 	defer s.pendingTxMutex.Unlock()
+	defer s.batchMutex.Unlock()
 
 	f := &Filter{
-		ID:            id,
-		Type:          t,
-		Parameters:    parameters,
-		LastPoll:      lastPoll,
-		WsConn:        wsConn,
-		wsQueue:       state.NewQueue[[]byte](),
-		wsQueueSignal: sync.NewCond(&sync.Mutex{}),
+		ID:         id,
+		Type:       t,
+		Parameters: parameters,
+		LastPoll:   lastPoll,
+		WsConn:     wsConn,
 	}
 
-	go state.InfiniteSafeRun(f.SendEnqueuedSubscriptionData, fmt.Sprintf("failed to send enqueued subscription data to filter %v", id), time.Second)
+	// the block notifications of the resumed subscriptions are held until
+	// the subscription finishes replaying the blocks the client missed
+	if resumed {
+		f.resume = newSubscriptionResume()
+	}
 
 	s.allFilters[id] = f
 	if f.WsConn != nil {
@@ -104,6 +150,8 @@ func (s *Storage) createFilter(t FilterType, parameters interface{}, wsConn *con
 			s.logFiltersWithWSConn[id] = f
 		} else if t == FilterTypePendingTx {
 			s.pendingTxFiltersWithWSConn[id] = f
+		} else if t == FilterTypeBatch {
+			s.batchFiltersWithWSConn[id] = f
 		}
 	}
 	return id, nil
@@ -152,14 +200,30 @@ func (s *Storage) GetAllLogFiltersWithWSConn() []*Filter {
 	return filters
 }
 
+// GetAllBatchFiltersWithWSConn returns an array with all filter that have
+// a web socket connection and are filtering by batch lifecycle events
+func (s *Storage) GetAllBatchFiltersWithWSConn() []*Filter {
+	s.batchMutex.Lock()
+	defer s.batchMutex.Unlock()
+
+	filters := []*Filter{}
+	for _, filter := range s.batchFiltersWithWSConn {
+		f := filter
+		filters = append(filters, f)
+	}
+	return filters
+}
+
 // GetFilter gets a filter by its id
 func (s *Storage) GetFilter(filterID string) (*Filter, error) {
 	s.blockMutex.Lock()
 	s.logMutex.Lock()
 	s.pendingTxMutex.Lock()
+	s.batchMutex.Lock()
 	defer s.blockMutex.Unlock()
 	defer s.logMutex.Unlock()
 	defer s.pendingTxMutex.Unlock()
+	defer s.batchMutex.Unlock()
 
 	filter, found := s.allFilters[filterID]
 	if !found {
@@ -174,9 +238,11 @@ func (s *Storage) UpdateFilterLastPoll(filterID string) error {
 	s.blockMutex.Lock()
 	s.logMutex.Lock()
 	s.pendingTxMutex.Lock()
+	s.batchMutex.Lock()
 	defer s.blockMutex.Unlock()
 	defer s.logMutex.Unlock()
 	defer s.pendingTxMutex.Unlock()
+	defer s.batchMutex.Unlock()
 
 	filter, found := s.allFilters[filterID]
 	if !found {
@@ -192,9 +258,11 @@ func (s *Storage) UninstallFilter(filterID string) error {
 	s.blockMutex.Lock()
 	s.logMutex.Lock()
 	s.pendingTxMutex.Lock()
+	s.batchMutex.Lock()
 	defer s.blockMutex.Unlock()
 	defer s.logMutex.Unlock()
 	defer s.pendingTxMutex.Unlock()
+	defer s.batchMutex.Unlock()
 
 	filter, found := s.allFilters[filterID]
 	if !found {
@@ -210,9 +278,11 @@ func (s *Storage) UninstallFilterByWSConn(wsConn *concurrentWsConn) error {
 	s.blockMutex.Lock()
 	s.logMutex.Lock()
 	s.pendingTxMutex.Lock()
+	s.batchMutex.Lock()
 	defer s.blockMutex.Unlock()
 	defer s.logMutex.Unlock()
 	defer s.pendingTxMutex.Unlock()
+	defer s.batchMutex.Unlock()
 
 	filters, found := s.allFiltersWithWSConn[wsConn]
 	if !found {
@@ -226,6 +296,28 @@ func (s *Storage) UninstallFilterByWSConn(wsConn *concurrentWsConn) error {
 	return nil
 }
 
+// DeleteExpiredFilters deletes the filters not bound to a web socket connection
+// that were not polled during the provided timeout
+func (s *Storage) DeleteExpiredFilters(timeout time.Duration) error {
+	s.blockMutex.Lock()
+	s.logMutex.Lock()
+	s.pendingTxMutex.Lock()
+	s.batchMutex.Lock()
+	defer s.blockMutex.Unlock()
+	defer s.logMutex.Unlock()
+	defer s.pendingTxMutex.Unlock()
+	defer s.batchMutex.Unlock()
+
+	deadline := time.Now().UTC().Add(-timeout)
+	for _, filter := range s.allFilters {
+		if filter.WsConn == nil && filter.LastPoll.Before(deadline) {
+			s.deleteFilter(filter)
+		}
+	}
+
+	return nil
+}
+
 // deleteFilter deletes a filter from all the maps
 func (s *Storage) deleteFilter(filter *Filter) {
 	if filter.Type == FilterTypeBlock {
@@ -234,6 +326,8 @@ func (s *Storage) deleteFilter(filter *Filter) {
 		delete(s.logFiltersWithWSConn, filter.ID)
 	} else if filter.Type == FilterTypePendingTx {
 		delete(s.pendingTxFiltersWithWSConn, filter.ID)
+	} else if filter.Type == FilterTypeBatch {
+		delete(s.batchFiltersWithWSConn, filter.ID)
 	}
 
 	if filter.WsConn != nil {
@@ -0,0 +1,166 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/db"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// PostgresStorage uses a postgres database to store the filters created via
+// HTTP, so they can be served by any of the RPC replicas sharing the database.
+// The filters bound to a web socket connection can only be served by the
+// replica holding the connection, so they are kept in memory.
+//
+// The last poll of a filter, which is the cursor used to compute the filter
+// changes, and the filter expiration are computed using the database clock, so
+// all the replicas have the same view of the filters no matter their own clocks.
+type PostgresStorage struct {
+	*Storage
+	db *pgxpool.Pool
+}
+
+// NewPostgresStorage creates and initializes an instance of PostgresStorage
+func NewPostgresStorage(cfg db.Config) (*PostgresStorage, error) {
+	rpcDB, err := db.NewSQLDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PostgresStorage{
+		Storage: NewStorage(),
+		db:      rpcDB,
+	}, nil
+}
+
+// NewLogFilter persists a new log filter
+func (s *PostgresStorage) NewLogFilter(wsConn *concurrentWsConn, filter LogFilter) (string, error) {
+	if wsConn != nil {
+		return s.Storage.NewLogFilter(wsConn, filter)
+	}
+
+	if err := filter.Validate(); err != nil {
+		return "", err
+	}
+
+	return s.createFilter(FilterTypeLog, &filter)
+}
+
+// NewBlockFilter persists a new block log filter
+func (s *PostgresStorage) NewBlockFilter(wsConn *concurrentWsConn) (string, error) {
+	if wsConn != nil {
+		return s.Storage.NewBlockFilter(wsConn)
+	}
+
+	return s.createFilter(FilterTypeBlock, nil)
+}
+
+// NewPendingTransactionFilter persists a new pending transaction filter
+func (s *PostgresStorage) NewPendingTransactionFilter(wsConn *concurrentWsConn) (string, error) {
+	if wsConn != nil {
+		return s.Storage.NewPendingTransactionFilter(wsConn)
+	}
+
+	return s.createFilter(FilterTypePendingTx, nil)
+}
+
+// createFilter persists the filter to the database and provides the filter id
+func (s *PostgresStorage) createFilter(t FilterType, parameters *LogFilter) (string, error) {
+	id, err := s.generateFilterID()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate filter ID: %w", err)
+	}
+
+	var encodedParameters []byte
+	if parameters != nil {
+		encodedParameters, err = json.Marshal(parameters)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode filter parameters: %w", err)
+		}
+	}
+
+	const createFilterSQL = "INSERT INTO rpc.filter (id, filter_type, parameters, last_poll) VALUES ($1, $2, $3, NOW())"
+	if _, err := s.db.Exec(context.Background(), createFilterSQL, id, t, encodedParameters); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// GetFilter gets a filter by its id
+func (s *PostgresStorage) GetFilter(filterID string) (*Filter, error) {
+	filter, err := s.Storage.GetFilter(filterID)
+	if !errors.Is(err, ErrNotFound) {
+		return filter, err
+	}
+
+	const getFilterSQL = "SELECT id, filter_type, parameters, last_poll FROM rpc.filter WHERE id = $1"
+
+	var (
+		t                 string
+		encodedParameters []byte
+		lastPoll          time.Time
+	)
+	filter = &Filter{}
+	err = s.db.QueryRow(context.Background(), getFilterSQL, filterID).Scan(&filter.ID, &t, &encodedParameters, &lastPoll)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	filter.Type = FilterType(t)
+	filter.LastPoll = lastPoll.UTC()
+	if filter.Type == FilterTypeLog {
+		var parameters LogFilter
+		if err := json.Unmarshal(encodedParameters, &parameters); err != nil {
+			return nil, fmt.Errorf("failed to decode filter parameters: %w", err)
+		}
+		filter.Parameters = parameters
+	}
+
+	return filter, nil
+}
+
+// UpdateFilterLastPoll updates the last poll to now
+func (s *PostgresStorage) UpdateFilterLastPoll(filterID string) error {
+	const updateFilterLastPollSQL = "UPDATE rpc.filter SET last_poll = NOW() WHERE id = $1"
+	res, err := s.db.Exec(context.Background(), updateFilterLastPollSQL, filterID)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return s.Storage.UpdateFilterLastPoll(filterID)
+	}
+	return nil
+}
+
+// UninstallFilter deletes a filter by its id
+func (s *PostgresStorage) UninstallFilter(filterID string) error {
+	const uninstallFilterSQL = "DELETE FROM rpc.filter WHERE id = $1"
+	res, err := s.db.Exec(context.Background(), uninstallFilterSQL, filterID)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return s.Storage.UninstallFilter(filterID)
+	}
+	return nil
+}
+
+// DeleteExpiredFilters deletes the filters not bound to a web socket connection
+// that were not polled during the provided timeout. Since the expiration is
+// checked by a single statement using the database clock, it is safe for
+// several replicas to sweep the expired filters concurrently
+func (s *PostgresStorage) DeleteExpiredFilters(timeout time.Duration) error {
+	const deleteExpiredFiltersSQL = "DELETE FROM rpc.filter WHERE last_poll < NOW() - make_interval(secs => $1)"
+	if _, err := s.db.Exec(context.Background(), deleteExpiredFiltersSQL, timeout.Seconds()); err != nil {
+		return err
+	}
+	return s.Storage.DeleteExpiredFilters(timeout)
+}
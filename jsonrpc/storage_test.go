@@ -0,0 +1,99 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageDeleteExpiredFilters(t *testing.T) {
+	s := NewStorage()
+
+	expiredID, err := s.NewBlockFilter(nil)
+	require.NoError(t, err)
+	activeID, err := s.NewPendingTransactionFilter(nil)
+	require.NoError(t, err)
+	wsConn := &concurrentWsConn{}
+	wsID, err := s.NewBlockFilter(wsConn)
+	require.NoError(t, err)
+
+	s.allFilters[expiredID].LastPoll = time.Now().UTC().Add(-10 * time.Minute)
+	s.allFilters[wsID].LastPoll = time.Now().UTC().Add(-10 * time.Minute)
+
+	require.NoError(t, s.DeleteExpiredFilters(5*time.Minute))
+
+	_, err = s.GetFilter(expiredID)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = s.GetFilter(activeID)
+	assert.NoError(t, err)
+
+	// filters bound to a web socket connection live as long as the connection
+	_, err = s.GetFilter(wsID)
+	assert.NoError(t, err)
+	assert.Len(t, s.GetAllBlockFiltersWithWSConn(), 1)
+}
+
+func TestStorageBatchFilters(t *testing.T) {
+	s := NewStorage()
+
+	wsConn := &concurrentWsConn{}
+	id, err := s.NewBatchFilter(wsConn, state.BatchEventVerified)
+	require.NoError(t, err)
+
+	filters := s.GetAllBatchFiltersWithWSConn()
+	require.Len(t, filters, 1)
+	assert.Equal(t, id, filters[0].ID)
+	assert.Equal(t, FilterType(FilterTypeBatch), filters[0].Type)
+	assert.Equal(t, state.BatchEventVerified, filters[0].Parameters)
+	assert.Empty(t, s.GetAllBlockFiltersWithWSConn())
+
+	require.NoError(t, s.UninstallFilterByWSConn(wsConn))
+	assert.Empty(t, s.GetAllBatchFiltersWithWSConn())
+}
+
+func TestNewFilterStorage(t *testing.T) {
+	s, err := NewFilterStorage(FilterStorageConfig{Type: FilterStorageTypeMemory})
+	require.NoError(t, err)
+	assert.IsType(t, &Storage{}, s)
+
+	s, err = NewFilterStorage(FilterStorageConfig{})
+	require.NoError(t, err)
+	assert.IsType(t, &Storage{}, s)
+
+	_, err = NewFilterStorage(FilterStorageConfig{Type: "unknown"})
+	assert.EqualError(t, err, `unknown filter storage type "unknown"`)
+}
+
+func TestLogFilterJSONRoundTrip(t *testing.T) {
+	pending := types.PendingBlockNumber
+	latest := types.LatestBlockNumber
+	safe := types.SafeBlockNumber
+	one := types.BlockNumber(1)
+
+	testCases := []LogFilter{
+		{FromBlock: &pending, ToBlock: &latest},
+		{FromBlock: &one, ToBlock: &safe, Addresses: []common.Address{common.HexToAddress("0x1")}},
+		{
+			BlockHash: &common.Hash{},
+			Addresses: []common.Address{common.HexToAddress("0x1"), common.HexToAddress("0x2")},
+			Topics:    [][]common.Hash{{common.HexToHash("0x3")}, {}, {common.HexToHash("0x4"), common.HexToHash("0x5")}},
+		},
+	}
+
+	for _, filter := range testCases {
+		f := filter
+		b, err := json.Marshal(&f)
+		require.NoError(t, err)
+
+		var decoded LogFilter
+		require.NoError(t, json.Unmarshal(b, &decoded))
+		assert.Equal(t, f, decoded)
+	}
+}
@@ -0,0 +1,176 @@
+package jsonrpc
+
+import (
+	"errors"
+	"sync"
+)
+
+// errSubscriptionQueueOverflow is returned when a notification is enqueued to
+// a full subscription queue whose overflow policy is to disconnect the client
+var errSubscriptionQueueOverflow = errors.New("subscription queue overflow, notifications are not read fast enough")
+
+// errSubscriptionResumeOverflow is sent to the clients whose resumed
+// subscription received more new blocks than the ones that can be held
+// while the missed blocks are replayed
+var errSubscriptionResumeOverflow = errors.New("too many new blocks while replaying the missed blocks of the subscription")
+
+// maxSubscriptionResumeHeldBlocks is the maximum number of new blocks whose
+// notifications are held while a subscription is resumed
+const maxSubscriptionResumeHeldBlocks = 1000
+
+// subscriptionQueue is the queue of the subscription notifications waiting to
+// be sent to a connection, it's bounded by the configured size and drops the
+// oldest notification or refuses the new one when it's full, depending on the
+// overflow policy
+type subscriptionQueue struct {
+	items  [][]byte
+	size   uint
+	policy string
+	mutex  *sync.Mutex
+
+	// signal receives a value when items are pushed to the queue
+	signal chan struct{}
+	// popped receives a value when the items of the queue are taken
+	popped chan struct{}
+	// closed is closed when the connection is closed
+	closed    chan struct{}
+	closeOnce *sync.Once
+}
+
+// newSubscriptionQueue creates a new instance of subscriptionQueue, a size of
+// zero means the queue is unbounded
+func newSubscriptionQueue(size uint, policy string) *subscriptionQueue {
+	return &subscriptionQueue{
+		items:     make([][]byte, 0),
+		size:      size,
+		policy:    policy,
+		mutex:     &sync.Mutex{},
+		signal:    make(chan struct{}, 1),
+		popped:    make(chan struct{}, 1),
+		closed:    make(chan struct{}),
+		closeOnce: &sync.Once{},
+	}
+}
+
+// push enqueues a notification, when the queue is full the oldest notification
+// is dropped or, if the overflow policy is to disconnect the client, the queue
+// is closed and errSubscriptionQueueOverflow is returned. The notifications
+// pushed to a closed queue are discarded.
+func (q *subscriptionQueue) push(message []byte) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.isClosed() {
+		return nil
+	}
+
+	if q.size > 0 && uint(len(q.items)) >= q.size {
+		if q.policy == SubscriptionQueueOverflowPolicyDisconnect {
+			q.close()
+			return errSubscriptionQueueOverflow
+		}
+		q.items[0] = nil
+		q.items = q.items[1:]
+	}
+	q.items = append(q.items, message)
+
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// pushWait enqueues a notification waiting for room when the queue is full
+// instead of applying the overflow policy, so the notification isn't lost.
+// It returns false if the queue is closed before the notification is enqueued.
+func (q *subscriptionQueue) pushWait(message []byte) bool {
+	for {
+		q.mutex.Lock()
+		if q.isClosed() {
+			q.mutex.Unlock()
+			return false
+		}
+		if q.size == 0 || uint(len(q.items)) < q.size {
+			q.items = append(q.items, message)
+			select {
+			case q.signal <- struct{}{}:
+			default:
+			}
+			q.mutex.Unlock()
+			return true
+		}
+		q.mutex.Unlock()
+
+		select {
+		case <-q.closed:
+			return false
+		case <-q.popped:
+		}
+	}
+}
+
+// popAll returns all the enqueued notifications and empties the queue
+func (q *subscriptionQueue) popAll() [][]byte {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	items := q.items
+	q.items = make([][]byte, 0)
+	select {
+	case q.popped <- struct{}{}:
+	default:
+	}
+	return items
+}
+
+// len returns the number of enqueued notifications
+func (q *subscriptionQueue) len() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return len(q.items)
+}
+
+// close signals the queue consumer to stop
+func (q *subscriptionQueue) close() {
+	q.closeOnce.Do(func() { close(q.closed) })
+}
+
+// isClosed returns if the queue was closed
+func (q *subscriptionQueue) isClosed() bool {
+	select {
+	case <-q.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// subscriptionResume holds the notifications of a newHeads or logs subscription
+// while the blocks the client missed are replayed from the state, so the client
+// gets the blocks in order and each block once. When more new blocks than
+// maxSubscriptionResumeHeldBlocks are received during the replay, the client
+// is disconnected and the notifications are discarded
+type subscriptionResume struct {
+	mutex             *sync.Mutex
+	resuming          bool
+	overflowed        bool
+	lastReplayedBlock uint64
+	held              []heldBlockData
+}
+
+// heldBlockData is the subscription data of a block received while the
+// subscription is being resumed
+type heldBlockData struct {
+	blockNumber uint64
+	data        [][]byte
+}
+
+// newSubscriptionResume creates a new instance of subscriptionResume holding
+// the notifications until the resume finishes
+func newSubscriptionResume() *subscriptionResume {
+	return &subscriptionResume{
+		mutex:    &sync.Mutex{},
+		resuming: true,
+	}
+}
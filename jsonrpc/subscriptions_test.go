@@ -0,0 +1,116 @@
+package jsonrpc
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionQueue(t *testing.T) {
+	t.Run("drop oldest", func(t *testing.T) {
+		q := newSubscriptionQueue(2, SubscriptionQueueOverflowPolicyDropOldest)
+		for _, m := range []string{"1", "2", "3"} {
+			require.NoError(t, q.push([]byte(m)))
+		}
+		assert.Equal(t, [][]byte{[]byte("2"), []byte("3")}, q.popAll())
+		assert.Zero(t, q.len())
+	})
+
+	t.Run("disconnect", func(t *testing.T) {
+		q := newSubscriptionQueue(2, SubscriptionQueueOverflowPolicyDisconnect)
+		require.NoError(t, q.push([]byte("1")))
+		require.NoError(t, q.push([]byte("2")))
+		assert.ErrorIs(t, q.push([]byte("3")), errSubscriptionQueueOverflow)
+		assert.True(t, q.isClosed())
+
+		// the queue is closed, so the next notifications are discarded
+		require.NoError(t, q.push([]byte("4")))
+		assert.Equal(t, [][]byte{[]byte("1"), []byte("2")}, q.popAll())
+	})
+
+	t.Run("unbounded", func(t *testing.T) {
+		q := newSubscriptionQueue(0, SubscriptionQueueOverflowPolicyDisconnect)
+		for i := 0; i < 100; i++ {
+			require.NoError(t, q.push([]byte{byte(i)}))
+		}
+		assert.Equal(t, 100, q.len())
+	})
+
+	t.Run("push wait", func(t *testing.T) {
+		q := newSubscriptionQueue(1, SubscriptionQueueOverflowPolicyDisconnect)
+		require.True(t, q.pushWait([]byte("1")))
+
+		// the queue is full, so the notification waits until the queue is read
+		pushed := make(chan bool)
+		go func() { pushed <- q.pushWait([]byte("2")) }()
+		select {
+		case <-pushed:
+			require.Fail(t, "the notification was pushed to a full queue")
+		case <-time.After(50 * time.Millisecond):
+		}
+		assert.False(t, q.isClosed())
+		assert.Equal(t, [][]byte{[]byte("1")}, q.popAll())
+		assert.True(t, <-pushed)
+		assert.Equal(t, [][]byte{[]byte("2")}, q.popAll())
+
+		// the waiting notification is discarded when the queue is closed
+		require.True(t, q.pushWait([]byte("3")))
+		go func() { pushed <- q.pushWait([]byte("4")) }()
+		q.close()
+		assert.False(t, <-pushed)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		q := newSubscriptionQueue(0, "")
+		assert.False(t, q.isClosed())
+		q.close()
+		q.close()
+		assert.True(t, q.isClosed())
+	})
+}
+
+func TestFilterResume(t *testing.T) {
+	wsConn := &concurrentWsConn{subscriptionQueue: newSubscriptionQueue(0, "")}
+	f := &Filter{ID: "0x1", WsConn: wsConn, resume: newSubscriptionResume()}
+
+	// the block data is held while resuming
+	f.EnqueueBlockSubscriptionDataToBeSent(5, []byte("5"))
+	f.EnqueueBlockSubscriptionDataToBeSent(6, []byte(`"6a"`), []byte(`"6b"`))
+	assert.Zero(t, wsConn.subscriptionQueue.len())
+
+	// the replayed blocks are sent before the held ones
+	f.EnqueueSubscriptionDataToBeSent([]byte("4"))
+	f.EnqueueSubscriptionDataToBeSent([]byte("5"))
+	f.finishResume(5)
+	f.EnqueueBlockSubscriptionDataToBeSent(5, []byte("5"))
+	f.EnqueueBlockSubscriptionDataToBeSent(7, []byte("7"))
+
+	messages := wsConn.subscriptionQueue.popAll()
+	require.Len(t, messages, 5)
+	for i, expected := range []string{`4`, `5`, `"6a"`, `"6b"`, `7`} {
+		assert.Contains(t, string(messages[i]), `"result":`+expected)
+	}
+}
+
+func TestFilterResumeOverflow(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	wsConn := newConcurrentIPCConn(newIPCConn(server), WebSocketsConfig{})
+	f := &Filter{ID: "0x1", WsConn: wsConn, resume: newSubscriptionResume()}
+
+	for blockNumber := uint64(1); blockNumber <= maxSubscriptionResumeHeldBlocks; blockNumber++ {
+		f.EnqueueBlockSubscriptionDataToBeSent(blockNumber, []byte("1"))
+	}
+	assert.False(t, wsConn.subscriptionQueue.isClosed())
+
+	// the client is disconnected when the held blocks exceed the max
+	f.EnqueueBlockSubscriptionDataToBeSent(maxSubscriptionResumeHeldBlocks+1, []byte("1"))
+	require.Eventually(t, wsConn.subscriptionQueue.isClosed, time.Second, 10*time.Millisecond)
+	assert.Nil(t, f.resume.held)
+
+	f.finishResume(1)
+	assert.Zero(t, wsConn.subscriptionQueue.len())
+}
@@ -0,0 +1,70 @@
+package jsonrpc
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// txWaiters keeps the requests waiting for a transaction to be included in a
+// stored l2 block, they are notified by the new l2 block events
+type txWaiters struct {
+	mutex   *sync.Mutex
+	waiters map[common.Hash][]chan struct{}
+}
+
+// newTxWaiters creates a new instance of txWaiters
+func newTxWaiters() *txWaiters {
+	return &txWaiters{
+		mutex:   &sync.Mutex{},
+		waiters: make(map[common.Hash][]chan struct{}),
+	}
+}
+
+// wait registers a waiter for the transaction, the returned channel is closed
+// when the transaction is included in a block and the returned function must
+// be called to unregister the waiter once it's not needed anymore
+func (w *txWaiters) wait(txHash common.Hash) (<-chan struct{}, func()) {
+	included := make(chan struct{})
+
+	w.mutex.Lock()
+	w.waiters[txHash] = append(w.waiters[txHash], included)
+	w.mutex.Unlock()
+
+	cancel := func() {
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+
+		waiters := w.waiters[txHash]
+		for i, waiter := range waiters {
+			if waiter == included {
+				waiters = append(waiters[:i], waiters[i+1:]...)
+				break
+			}
+		}
+		if len(waiters) == 0 {
+			delete(w.waiters, txHash)
+		} else {
+			w.waiters[txHash] = waiters
+		}
+	}
+
+	return included, cancel
+}
+
+// notify wakes up the waiters of the transactions included in a block
+func (w *txWaiters) notify(txHashes []common.Hash) {
+	if w == nil {
+		return
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for _, txHash := range txHashes {
+		for _, included := range w.waiters[txHash] {
+			close(included)
+		}
+		delete(w.waiters, txHash)
+	}
+}
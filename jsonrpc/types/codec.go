@@ -10,6 +10,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/encoding"
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/jackc/pgx/v4"
 )
 
@@ -399,6 +400,44 @@ func (i *Index) UnmarshalJSON(buffer []byte) error {
 	return nil
 }
 
+// IndexOrHash allows a string value to be parsed into an index or a hash,
+// it's used by methods like zkevm_getL1InfoTreeLeaf that allows the leaf to
+// be specified either by its index or by its global exit root
+type IndexOrHash struct {
+	index *Index
+	hash  *ArgHash
+}
+
+// Index returns the index
+func (i *IndexOrHash) Index() *Index {
+	return i.index
+}
+
+// Hash returns the hash
+func (i *IndexOrHash) Hash() *ArgHash {
+	return i.hash
+}
+
+// UnmarshalJSON automatically decodes the user input for the index or hash, when a JSON RPC method is called
+func (i *IndexOrHash) UnmarshalJSON(buffer []byte) error {
+	str := strings.Trim(string(buffer), "\"")
+	if len(strings.TrimPrefix(str, "0x")) == common.HashLength*2 {
+		var hash ArgHash
+		if err := json.Unmarshal(buffer, &hash); err != nil {
+			return err
+		}
+		i.index, i.hash = nil, &hash
+		return nil
+	}
+
+	var index Index
+	if err := index.UnmarshalJSON(buffer); err != nil {
+		return err
+	}
+	i.index, i.hash = &index, nil
+	return nil
+}
+
 // BatchNumber is the number of a ethereum block
 type BatchNumber int64
 
@@ -449,3 +449,32 @@ func argHashPtr(hash common.Hash) *ArgHash {
 	h := ArgHash(hash)
 	return &h
 }
+
+func TestIndexOrHashUnmarshalJSON(t *testing.T) {
+	hash := common.HexToHash("0x7f1b3a4e0c61d76a2c4e9a1b8f0e3d2c5b6a79880f1e2d3c4b5a69788796a5b4")
+	index := Index(134)
+
+	testCases := []struct {
+		input         string
+		expectedIndex *Index
+		expectedHash  *ArgHash
+		expectErr     bool
+	}{
+		{input: `"0x86"`, expectedIndex: &index},
+		{input: `134`, expectedIndex: &index},
+		{input: `"` + hash.String() + `"`, expectedHash: (*ArgHash)(&hash)},
+		{input: `"abc"`, expectErr: true},
+	}
+
+	for _, testCase := range testCases {
+		var i IndexOrHash
+		err := json.Unmarshal([]byte(testCase.input), &i)
+		if testCase.expectErr {
+			assert.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expectedIndex, i.Index())
+		assert.Equal(t, testCase.expectedHash, i.Hash())
+	}
+}
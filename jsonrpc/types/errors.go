@@ -15,6 +15,8 @@ const (
 	InvalidParamsErrorCode = -32602
 	// ParserErrorCode error code for parsing errors
 	ParserErrorCode = -32700
+	// LimitExceededErrorCode error code for requests exceeding the rate limits
+	LimitExceededErrorCode = -32005
 )
 
 var (
@@ -25,6 +27,14 @@ var (
 	// ErrBatchRequestsLimitExceeded returned by the server when a batch request
 	// is detected and the number of requests are greater than the configured limit.
 	ErrBatchRequestsLimitExceeded = fmt.Errorf("batch requests limit exceeded")
+
+	// ErrBatchRequestsCostLimitExceeded returned by the server for each request
+	// of a batch request that exceeds the configured cost limit of the batch
+	ErrBatchRequestsCostLimitExceeded = fmt.Errorf("batch requests cost limit exceeded")
+
+	// ErrBatchRequestsTimeoutExceeded returned by the server for each request
+	// of a batch request that wasn't processed within the configured timeout
+	ErrBatchRequestsTimeoutExceeded = fmt.Errorf("batch requests timeout exceeded")
 )
 
 // Error interface
@@ -11,4 +11,7 @@ type LogFilterRequest struct {
 	ToBlock   *string       `json:"toBlock,omitempty"`
 	Address   interface{}   `json:"address,omitempty"`
 	Topics    []interface{} `json:"topics,omitempty"`
+
+	// ResumeFromBlock is only used by the newHeads and logs subscriptions
+	ResumeFromBlock *string `json:"resumeFromBlock,omitempty"`
 }
@@ -3,13 +3,17 @@ package types
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"strconv"
 	"strings"
 
 	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/jackc/pgx/v4"
@@ -48,6 +52,30 @@ func ArgUint64Ptr(a ArgUint64) *ArgUint64 {
 	return &a
 }
 
+// ArgHexOrDecimalUint64 helps to marshal uint64 values provided in the RPC requests
+// either as hex strings, decimal strings or json numbers
+type ArgHexOrDecimalUint64 uint64
+
+// MarshalText marshals into text
+func (b ArgHexOrDecimalUint64) MarshalText() ([]byte, error) {
+	return ArgUint64(b).MarshalText()
+}
+
+// UnmarshalJSON unmarshals from a json number or string
+func (b *ArgHexOrDecimalUint64) UnmarshalJSON(input []byte) error {
+	str := strings.Trim(string(input), "\"")
+	base := 10 //nolint:gomnd
+	if strings.HasPrefix(str, "0x") || strings.HasPrefix(str, "0X") {
+		str, base = str[2:], hex.Base
+	}
+	num, err := strconv.ParseUint(str, base, hex.BitSize64)
+	if err != nil {
+		return fmt.Errorf("invalid hex or decimal number %s: %w", string(input), err)
+	}
+	*b = ArgHexOrDecimalUint64(num)
+	return nil
+}
+
 // ArgBytes helps to marshal byte array values provided in the RPC requests
 type ArgBytes []byte
 
@@ -237,6 +265,99 @@ func (args *TxArgs) ToTransaction(ctx context.Context, st StateInterface, maxCum
 	return sender, tx, nil
 }
 
+// StateOverride is the set of accounts overridden before executing a call,
+// keyed by the account address, following the geth state override set format
+type StateOverride map[common.Address]OverrideAccount
+
+// OverrideAccount indicates the overriding fields of an account
+type OverrideAccount struct {
+	Nonce     *ArgUint64                   `json:"nonce"`
+	Code      *ArgBytes                    `json:"code"`
+	Balance   *ArgBig                      `json:"balance"`
+	State     *map[common.Hash]common.Hash `json:"state"`
+	StateDiff *map[common.Hash]common.Hash `json:"stateDiff"`
+}
+
+// ToStateOverride converts the state override into the state format
+func (so StateOverride) ToStateOverride() state.StateOverride {
+	if len(so) == 0 {
+		return nil
+	}
+	res := make(state.StateOverride, len(so))
+	for address, account := range so {
+		overrideAccount := state.OverrideAccount{
+			State:     account.State,
+			StateDiff: account.StateDiff,
+		}
+		if account.Nonce != nil {
+			nonce := uint64(*account.Nonce)
+			overrideAccount.Nonce = &nonce
+		}
+		if account.Code != nil {
+			code := []byte(*account.Code)
+			overrideAccount.Code = &code
+		}
+		if account.Balance != nil {
+			overrideAccount.Balance = new(big.Int).Set((*big.Int)(account.Balance))
+		}
+		res[address] = overrideAccount
+	}
+	return res
+}
+
+// TransactionConditions are the conditions of a transaction sent with
+// eth_sendRawTransactionConditional, the known accounts are keyed by the
+// account address and hold the expected values of their storage slots
+type TransactionConditions struct {
+	KnownAccounts  map[common.Address]KnownAccountStorage `json:"knownAccounts,omitempty"`
+	BlockNumberMin *ArgUint64                             `json:"blockNumberMin,omitempty"`
+	BlockNumberMax *ArgUint64                             `json:"blockNumberMax,omitempty"`
+	TimestampMin   *ArgUint64                             `json:"timestampMin,omitempty"`
+	TimestampMax   *ArgUint64                             `json:"timestampMax,omitempty"`
+}
+
+// KnownAccountStorage are the expected values of the storage slots of a known account
+type KnownAccountStorage map[common.Hash]common.Hash
+
+// UnmarshalJSON unmarshals the expected values of the storage slots, the
+// storage root of the account can't be used as condition because the accounts
+// of the zkEVM state don't have a storage root
+func (s *KnownAccountStorage) UnmarshalJSON(input []byte) error {
+	var slots map[common.Hash]common.Hash
+	if err := json.Unmarshal(input, &slots); err != nil {
+		var root common.Hash
+		if json.Unmarshal(input, &root) == nil {
+			return errors.New("storage root conditions are not supported, the expected values of the storage slots must be provided")
+		}
+		return err
+	}
+	*s = slots
+	return nil
+}
+
+// ToPoolConditions converts the conditions into the pool format
+func (c TransactionConditions) ToPoolConditions() *pool.TransactionConditions {
+	res := &pool.TransactionConditions{}
+	if len(c.KnownAccounts) > 0 {
+		res.KnownAccounts = make(map[common.Address]map[common.Hash]common.Hash, len(c.KnownAccounts))
+		for address, slots := range c.KnownAccounts {
+			res.KnownAccounts[address] = slots
+		}
+	}
+	toUint64Ptr := func(v *ArgUint64) *uint64 {
+		if v == nil {
+			return nil
+		}
+		u := uint64(*v)
+		return &u
+	}
+	res.BlockNumberMin = toUint64Ptr(c.BlockNumberMin)
+	res.BlockNumberMax = toUint64Ptr(c.BlockNumberMax)
+	res.TimestampMin = toUint64Ptr(c.TimestampMin)
+	res.TimestampMax = toUint64Ptr(c.TimestampMax)
+	return res
+}
+
 // Block structure
 type Block struct {
 	ParentHash      common.Hash         `json:"parentHash"`
@@ -423,6 +544,30 @@ func NewBatch(batch *state.Batch, virtualBatch *state.VirtualBatch, verifiedBatc
 	return res, nil
 }
 
+// BatchEvent is the notification sent to the subscriptions of the batch
+// lifecycle events
+type BatchEvent struct {
+	Number        ArgUint64    `json:"number"`
+	Status        string       `json:"status"`
+	L1TxHash      *common.Hash `json:"l1TxHash,omitempty"`
+	L1BlockNumber *ArgUint64   `json:"l1BlockNumber,omitempty"`
+}
+
+// NewBatchEvent creates a BatchEvent instance
+func NewBatchEvent(e state.NewBatchEvent) BatchEvent {
+	res := BatchEvent{
+		Number: ArgUint64(e.BatchNumber),
+		Status: string(e.Type),
+	}
+	if e.Type != state.BatchEventClosed {
+		txHash := e.TxHash
+		blockNumber := ArgUint64(e.BlockNumber)
+		res.L1TxHash = &txHash
+		res.L1BlockNumber = &blockNumber
+	}
+	return res
+}
+
 // TransactionOrHash for union type of transaction and types.Hash
 type TransactionOrHash struct {
 	Hash *common.Hash
@@ -575,6 +720,135 @@ func NewTransaction(
 	return res, nil
 }
 
+// FeeHistory structure
+type FeeHistory struct {
+	OldestBlock  ArgUint64  `json:"oldestBlock"`
+	Reward       [][]ArgBig `json:"reward,omitempty"`
+	BaseFee      []ArgBig   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64  `json:"gasUsedRatio"`
+}
+
+// AccessListTxArgs is the transaction argument of eth_createAccessList, the
+// access list is the starting point of the access list creation
+type AccessListTxArgs struct {
+	TxArgs
+	AccessList *types.AccessList
+}
+
+// AccessListResult structure
+type AccessListResult struct {
+	AccessList types.AccessList `json:"accessList"`
+	GasUsed    ArgUint64        `json:"gasUsed"`
+	Error      string           `json:"error,omitempty"`
+}
+
+// NewAccessListResult creates an AccessListResult instance
+func NewAccessListResult(r state.AccessListResult) AccessListResult {
+	res := AccessListResult{
+		AccessList: r.AccessList,
+		GasUsed:    ArgUint64(r.GasUsed),
+	}
+	if res.AccessList == nil {
+		res.AccessList = types.AccessList{}
+	}
+	if r.Err != nil {
+		res.Error = r.Err.Error()
+	}
+	return res
+}
+
+// ZKCountersResponse structure
+type ZKCountersResponse struct {
+	CountersUsed   ZKCountersUsed   `json:"countersUsed"`
+	CountersLimits ZKCountersLimits `json:"countersLimits"`
+	Revert         *RevertInfo      `json:"revert,omitempty"`
+	OOCError       *string          `json:"oocError,omitempty"`
+	Error          *string          `json:"error,omitempty"`
+}
+
+// ZKCountersUsed structure
+type ZKCountersUsed struct {
+	GasUsed              ArgUint64 `json:"gasUsed"`
+	UsedKeccakHashes     ArgUint64 `json:"usedKeccakHashes"`
+	UsedPoseidonHashes   ArgUint64 `json:"usedPoseidonHashes"`
+	UsedPoseidonPaddings ArgUint64 `json:"usedPoseidonPaddings"`
+	UsedMemAligns        ArgUint64 `json:"usedMemAligns"`
+	UsedArithmetics      ArgUint64 `json:"usedArithmetics"`
+	UsedBinaries         ArgUint64 `json:"usedBinaries"`
+	UsedSteps            ArgUint64 `json:"usedSteps"`
+	UsedSHA256Hashes     ArgUint64 `json:"usedSHA256Hashes"`
+}
+
+// ZKCountersLimits structure
+type ZKCountersLimits struct {
+	MaxGasUsed          ArgUint64 `json:"maxGasUsed"`
+	MaxKeccakHashes     ArgUint64 `json:"maxKeccakHashes"`
+	MaxPoseidonHashes   ArgUint64 `json:"maxPoseidonHashes"`
+	MaxPoseidonPaddings ArgUint64 `json:"maxPoseidonPaddings"`
+	MaxMemAligns        ArgUint64 `json:"maxMemAligns"`
+	MaxArithmetics      ArgUint64 `json:"maxArithmetics"`
+	MaxBinaries         ArgUint64 `json:"maxBinaries"`
+	MaxSteps            ArgUint64 `json:"maxSteps"`
+}
+
+// RevertInfo contains the reverted message and data when a tx is reverted
+type RevertInfo struct {
+	Message string    `json:"message"`
+	Data    *ArgBytes `json:"data,omitempty"`
+}
+
+// NewZKCountersResponse creates a new instance of ZKCountersResponse with the
+// counters used by the execution result and the limits of the batch constraints.
+// The ROM out of counters error of the execution, or the error of the counters
+// exceeding the batch constraints, is reported as the OOC error, and any other
+// ROM error that isn't a revert is reported as the error.
+func NewZKCountersResponse(counters state.ZKCounters, limits state.BatchConstraintsCfg, result *runtime.ExecutionResult) ZKCountersResponse {
+	res := ZKCountersResponse{
+		CountersUsed: ZKCountersUsed{
+			GasUsed:              ArgUint64(counters.GasUsed),
+			UsedKeccakHashes:     ArgUint64(counters.UsedKeccakHashes),
+			UsedPoseidonHashes:   ArgUint64(counters.UsedPoseidonHashes),
+			UsedPoseidonPaddings: ArgUint64(counters.UsedPoseidonPaddings),
+			UsedMemAligns:        ArgUint64(counters.UsedMemAligns),
+			UsedArithmetics:      ArgUint64(counters.UsedArithmetics),
+			UsedBinaries:         ArgUint64(counters.UsedBinaries),
+			UsedSteps:            ArgUint64(counters.UsedSteps),
+			UsedSHA256Hashes:     ArgUint64(counters.UsedSha256Hashes_V2),
+		},
+		CountersLimits: ZKCountersLimits{
+			MaxGasUsed:          ArgUint64(limits.MaxCumulativeGasUsed),
+			MaxKeccakHashes:     ArgUint64(limits.MaxKeccakHashes),
+			MaxPoseidonHashes:   ArgUint64(limits.MaxPoseidonHashes),
+			MaxPoseidonPaddings: ArgUint64(limits.MaxPoseidonPaddings),
+			MaxMemAligns:        ArgUint64(limits.MaxMemAligns),
+			MaxArithmetics:      ArgUint64(limits.MaxArithmetics),
+			MaxBinaries:         ArgUint64(limits.MaxBinaries),
+			MaxSteps:            ArgUint64(limits.MaxSteps),
+		},
+	}
+
+	if result.Reverted() {
+		res.Revert = &RevertInfo{Message: result.Err.Error()}
+		if len(result.ReturnValue) > 0 {
+			res.Revert.Data = ArgBytesPtr(result.ReturnValue)
+		}
+	}
+
+	oocErr := limits.CheckConstraints(counters)
+	if runtime.IsOutOfCounterError(result.Err) {
+		oocErr = result.Err
+	} else if result.Failed() && !result.Reverted() {
+		msg := result.Err.Error()
+		res.Error = &msg
+	}
+	if oocErr != nil {
+		msg := oocErr.Error()
+		res.OOCError = &msg
+	}
+
+	return res
+}
+
 // Receipt structure
 type Receipt struct {
 	Root              common.Hash     `json:"root"`
@@ -668,6 +942,212 @@ func NewLog(l types.Log) Log {
 	}
 }
 
+// AccountProof is the response of eth_getProof, each value is provided along
+// with the sparse merkle tree proof of its leaf
+type AccountProof struct {
+	Address       common.Address `json:"address"`
+	Balance       ArgBig         `json:"balance"`
+	BalanceProof  SMTProof       `json:"balanceProof"`
+	Nonce         ArgUint64      `json:"nonce"`
+	NonceProof    SMTProof       `json:"nonceProof"`
+	CodeHash      common.Hash    `json:"codeHash"`
+	CodeHashProof SMTProof       `json:"codeHashProof"`
+	StorageProof  []StorageProof `json:"storageProof"`
+}
+
+// StorageProof is the proof of a storage position of an account
+type StorageProof struct {
+	Key   common.Hash `json:"key"`
+	Value ArgBig      `json:"value"`
+	Proof SMTProof    `json:"proof"`
+}
+
+// SMTProof is the sparse merkle tree proof of a leaf, see merkletree.Proof
+type SMTProof struct {
+	Key      common.Hash  `json:"key"`
+	Siblings []ArgBytes   `json:"siblings"`
+	InsKey   *common.Hash `json:"insKey,omitempty"`
+	InsValue *ArgBig      `json:"insValue,omitempty"`
+}
+
+// NewAccountProof creates a new instance of AccountProof
+func NewAccountProof(address common.Address, storageKeys []common.Hash, p *state.AccountProof) (*AccountProof, error) {
+	if len(storageKeys) != len(p.Storage) {
+		return nil, fmt.Errorf("expected %d storage proofs, got %d", len(storageKeys), len(p.Storage))
+	}
+
+	res := &AccountProof{
+		Address:       address,
+		Balance:       ArgBig(*p.Balance.ValueScalar()),
+		BalanceProof:  NewSMTProof(p.Balance),
+		Nonce:         ArgUint64(p.Nonce.ValueScalar().Uint64()),
+		NonceProof:    NewSMTProof(p.Nonce),
+		CodeHash:      common.BigToHash(p.CodeHash.ValueScalar()),
+		CodeHashProof: NewSMTProof(p.CodeHash),
+		StorageProof:  make([]StorageProof, 0, len(storageKeys)),
+	}
+	for i, key := range storageKeys {
+		res.StorageProof = append(res.StorageProof, StorageProof{
+			Key:   key,
+			Value: ArgBig(*p.Storage[i].ValueScalar()),
+			Proof: NewSMTProof(p.Storage[i]),
+		})
+	}
+	return res, nil
+}
+
+// NewSMTProof creates a new instance of SMTProof
+func NewSMTProof(p *merkletree.Proof) SMTProof {
+	res := SMTProof{
+		Key:      common.BytesToHash(p.EncodedKey()),
+		Siblings: make([]ArgBytes, 0, len(p.Siblings)),
+	}
+	for _, sibling := range p.EncodedSiblings() {
+		res.Siblings = append(res.Siblings, sibling)
+	}
+	if insKey := p.EncodedInsKey(); insKey != nil {
+		k := common.BytesToHash(insKey)
+		v := ArgBig(*p.InsValueScalar())
+		res.InsKey, res.InsValue = &k, &v
+	}
+	return res
+}
+
+// Verify checks the proof of the given value against the given state root
+func (p SMTProof) Verify(root common.Hash, value *big.Int) error {
+	siblings := make([][]byte, 0, len(p.Siblings))
+	for _, sibling := range p.Siblings {
+		siblings = append(siblings, sibling)
+	}
+	var insKey []byte
+	var insValue *big.Int
+	if p.InsKey != nil && p.InsValue != nil {
+		insKey, insValue = p.InsKey.Bytes(), (*big.Int)(p.InsValue)
+	}
+	proof, err := merkletree.NewProof(p.Key.Bytes(), value, siblings, insKey, insValue)
+	if err != nil {
+		return err
+	}
+	return merkletree.VerifyProof(root.Bytes(), proof)
+}
+
+// L1InfoTreeLeaf structure
+type L1InfoTreeLeaf struct {
+	Index             ArgUint64   `json:"index"`
+	GlobalExitRoot    common.Hash `json:"globalExitRoot"`
+	MainnetExitRoot   common.Hash `json:"mainnetExitRoot"`
+	RollupExitRoot    common.Hash `json:"rollupExitRoot"`
+	PreviousBlockHash common.Hash `json:"previousBlockHash"`
+	Timestamp         ArgUint64   `json:"timestamp"`
+	BlockNumber       ArgUint64   `json:"blockNumber"`
+	L1InfoTreeRoot    common.Hash `json:"l1InfoTreeRoot"`
+	Hash              common.Hash `json:"hash"`
+}
+
+// NewL1InfoTreeLeaf creates a L1InfoTreeLeaf instance
+func NewL1InfoTreeLeaf(e state.L1InfoTreeExitRootStorageEntry) L1InfoTreeLeaf {
+	return L1InfoTreeLeaf{
+		Index:             ArgUint64(e.L1InfoTreeIndex),
+		GlobalExitRoot:    e.GlobalExitRoot.GlobalExitRoot,
+		MainnetExitRoot:   e.MainnetExitRoot,
+		RollupExitRoot:    e.RollupExitRoot,
+		PreviousBlockHash: e.PreviousBlockHash,
+		Timestamp:         ArgUint64(e.Timestamp.Unix()),
+		BlockNumber:       ArgUint64(e.BlockNumber),
+		L1InfoTreeRoot:    e.L1InfoTreeRoot,
+		Hash:              e.Hash(),
+	}
+}
+
+// L1InfoTreeProof structure
+type L1InfoTreeProof struct {
+	Leaf      L1InfoTreeLeaf `json:"leaf"`
+	Root      common.Hash    `json:"root"`
+	RootIndex ArgUint64      `json:"rootIndex"`
+	Siblings  []common.Hash  `json:"siblings"`
+}
+
+// NewL1InfoTreeProof creates a L1InfoTreeProof instance
+func NewL1InfoTreeProof(p state.L1InfoTreeProof) L1InfoTreeProof {
+	res := L1InfoTreeProof{
+		Leaf:      NewL1InfoTreeLeaf(p.Leaf),
+		Root:      p.Root,
+		RootIndex: ArgUint64(p.RootIndex),
+		Siblings:  make([]common.Hash, 0, len(p.Siblings)),
+	}
+	for _, sibling := range p.Siblings {
+		res.Siblings = append(res.Siblings, sibling)
+	}
+	return res
+}
+
+// ForkIDInterval structure
+type ForkIDInterval struct {
+	ForkID          ArgUint64 `json:"forkId"`
+	FromBatchNumber ArgUint64 `json:"fromBatchNumber"`
+	ToBatchNumber   ArgUint64 `json:"toBatchNumber"`
+	Version         string    `json:"version"`
+	BlockNumber     ArgUint64 `json:"blockNumber"`
+}
+
+// NewForkIDInterval creates a ForkIDInterval instance
+func NewForkIDInterval(i state.ForkIDInterval) ForkIDInterval {
+	return ForkIDInterval{
+		ForkID:          ArgUint64(i.ForkId),
+		FromBatchNumber: ArgUint64(i.FromBatchNumber),
+		ToBatchNumber:   ArgUint64(i.ToBatchNumber),
+		Version:         i.Version,
+		BlockNumber:     ArgUint64(i.BlockNumber),
+	}
+}
+
+// ChainConfig structure
+type ChainConfig struct {
+	ChainID          ArgUint64        `json:"chainId"`
+	L1ChainID        ArgUint64        `json:"l1ChainId"`
+	RollupID         ArgUint64        `json:"rollupId"`
+	Contracts        ChainContracts   `json:"contracts"`
+	BatchConstraints BatchConstraints `json:"batchConstraints"`
+}
+
+// ChainContracts structure
+type ChainContracts struct {
+	ZkEVMAddr                 common.Address `json:"polygonZkEVMAddress"`
+	RollupManagerAddr         common.Address `json:"polygonRollupManagerAddress"`
+	PolAddr                   common.Address `json:"polTokenAddress"`
+	GlobalExitRootManagerAddr common.Address `json:"polygonZkEVMGlobalExitRootAddress"`
+}
+
+// BatchConstraints structure
+type BatchConstraints struct {
+	MaxTxsPerBatch       ArgUint64 `json:"maxTxsPerBatch"`
+	MaxBatchBytesSize    ArgUint64 `json:"maxBatchBytesSize"`
+	MaxCumulativeGasUsed ArgUint64 `json:"maxCumulativeGasUsed"`
+	MaxKeccakHashes      ArgUint64 `json:"maxKeccakHashes"`
+	MaxPoseidonHashes    ArgUint64 `json:"maxPoseidonHashes"`
+	MaxPoseidonPaddings  ArgUint64 `json:"maxPoseidonPaddings"`
+	MaxMemAligns         ArgUint64 `json:"maxMemAligns"`
+	MaxArithmetics       ArgUint64 `json:"maxArithmetics"`
+	MaxBinaries          ArgUint64 `json:"maxBinaries"`
+	MaxSteps             ArgUint64 `json:"maxSteps"`
+}
+
+// NewBatchConstraints creates a BatchConstraints instance
+func NewBatchConstraints(c state.BatchConstraintsCfg) BatchConstraints {
+	return BatchConstraints{
+		MaxTxsPerBatch:       ArgUint64(c.MaxTxsPerBatch),
+		MaxBatchBytesSize:    ArgUint64(c.MaxBatchBytesSize),
+		MaxCumulativeGasUsed: ArgUint64(c.MaxCumulativeGasUsed),
+		MaxKeccakHashes:      ArgUint64(c.MaxKeccakHashes),
+		MaxPoseidonHashes:    ArgUint64(c.MaxPoseidonHashes),
+		MaxPoseidonPaddings:  ArgUint64(c.MaxPoseidonPaddings),
+		MaxMemAligns:         ArgUint64(c.MaxMemAligns),
+		MaxArithmetics:       ArgUint64(c.MaxArithmetics),
+		MaxBinaries:          ArgUint64(c.MaxBinaries),
+		MaxSteps:             ArgUint64(c.MaxSteps),
+	}
+}
+
 // ToBatchNumArg converts a big.Int into a batch number rpc parameter
 func ToBatchNumArg(number *big.Int) string {
 	if number == nil {
@@ -679,3 +1159,32 @@ func ToBatchNumArg(number *big.Int) string {
 	}
 	return hex.EncodeBig(number)
 }
+
+// LogsPage is a page of logs returned by zkevm_getLogsPaginated, the cursor
+// must be provided to get the next page and it's nil when there are no more logs
+type LogsPage struct {
+	Logs   []Log   `json:"logs"`
+	Cursor *string `json:"cursor"`
+}
+
+// TransactionStatus is the status of a transaction returned by
+// zkevm_getTransactionStatus, the pool fields are nil when the transaction is
+// not in the pool anymore and the block and batch fields are nil until the
+// transaction is included in a block
+type TransactionStatus struct {
+	Hash         common.Hash                   `json:"hash"`
+	PoolStatus   *string                       `json:"poolStatus"`
+	FailedReason *string                       `json:"failedReason"`
+	BlockNumber  *ArgUint64                    `json:"blockNumber"`
+	BatchNumber  *ArgUint64                    `json:"batchNumber"`
+	Virtualized  bool                          `json:"virtualized"`
+	Verified     bool                          `json:"verified"`
+	History      []TransactionStatusTransition `json:"history"`
+}
+
+// TransactionStatusTransition is a change of the pool status of a transaction
+type TransactionStatusTransition struct {
+	Status       string    `json:"status"`
+	FailedReason *string   `json:"failedReason"`
+	ChangedAt    ArgUint64 `json:"changedAt"`
+}
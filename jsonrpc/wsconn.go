@@ -2,23 +2,66 @@ package jsonrpc
 
 import (
 	"sync"
+	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/gorilla/websocket"
 )
 
+// closeMessageTimeout is the time allowed to write the close message sent to
+// a web socket client before disconnecting it
+const closeMessageTimeout = time.Second
+
+// messageConn is a connection that exchanges whole messages with the
+// client, it's implemented by the web socket and the ipc connections
+type messageConn interface {
+	ReadMessage() (messageType int, p []byte, err error)
+	WriteMessage(messageType int, data []byte) error
+	Close() error
+	SetReadLimit(limit int64)
+}
+
 // concurrentWsConn is a wrapped web socket connection
 // that provide methods to deal with concurrency
 type concurrentWsConn struct {
-	wsConn *websocket.Conn
-	mutex  *sync.Mutex
+	wsConn    messageConn
+	mutex     *sync.Mutex
+	ipc       bool
+	closeOnce *sync.Once
+
+	// subscriptionQueue has the subscription notifications waiting to be sent
+	subscriptionQueue *subscriptionQueue
+	// requestMutex is held while a request is handled, so the notifications
+	// of a subscription are never sent before the subscription id
+	requestMutex *sync.Mutex
 }
 
 // NewConcurrentWsConn creates a new instance of concurrentWsConn
-func newConcurrentWsConn(wsConn *websocket.Conn) *concurrentWsConn {
-	return &concurrentWsConn{
-		wsConn: wsConn,
-		mutex:  &sync.Mutex{},
+func newConcurrentWsConn(wsConn *websocket.Conn, cfg WebSocketsConfig) *concurrentWsConn {
+	c := &concurrentWsConn{
+		wsConn:            wsConn,
+		mutex:             &sync.Mutex{},
+		closeOnce:         &sync.Once{},
+		subscriptionQueue: newSubscriptionQueue(cfg.SubscriptionQueueSize, cfg.SubscriptionQueueOverflowPolicy),
+		requestMutex:      &sync.Mutex{},
+	}
+	go c.sendSubscriptionMessages()
+	return c
+}
+
+// newConcurrentIPCConn creates a new instance of concurrentWsConn wrapping
+// an ipc connection, so the subscriptions are handled as the web socket ones
+func newConcurrentIPCConn(conn *ipcConn, cfg WebSocketsConfig) *concurrentWsConn {
+	c := &concurrentWsConn{
+		wsConn:            conn,
+		mutex:             &sync.Mutex{},
+		ipc:               true,
+		closeOnce:         &sync.Once{},
+		subscriptionQueue: newSubscriptionQueue(cfg.SubscriptionQueueSize, cfg.SubscriptionQueueOverflowPolicy),
+		requestMutex:      &sync.Mutex{},
 	}
+	go c.sendSubscriptionMessages()
+	return c
 }
 
 // ReadMessage reads a message from the inner web socket connection
@@ -33,14 +76,85 @@ func (c *concurrentWsConn) WriteMessage(messageType int, data []byte) error {
 	return c.wsConn.WriteMessage(messageType, data)
 }
 
-// Close closes the inner web socket connection
+// Close closes the inner web socket connection, it can be called concurrently
+// with a write, which fails when the connection is closed, and more than once,
+// in which case only the first call closes the connection
 func (c *concurrentWsConn) Close() error {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-	return c.wsConn.Close()
+	var err error
+	c.closeOnce.Do(func() {
+		c.subscriptionQueue.close()
+		err = c.wsConn.Close()
+	})
+	return err
+}
+
+// disconnect closes the connection, web socket clients get a close message
+// with the reason
+func (c *concurrentWsConn) disconnect(reason error) {
+	if wsConn, ok := c.wsConn.(*websocket.Conn); ok {
+		msg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason.Error())
+		_ = wsConn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(closeMessageTimeout))
+	}
+	if err := c.Close(); err != nil {
+		log.Errorf("Unable to close connection after %v, %s", reason, err.Error())
+	}
+}
+
+// handleRequest runs the request handler holding the subscription
+// notifications until it finishes
+func (c *concurrentWsConn) handleRequest(fn func()) {
+	c.requestMutex.Lock()
+	defer c.requestMutex.Unlock()
+	fn()
+}
+
+// enqueueSubscriptionMessage enqueues a subscription notification to be sent,
+// the connection is closed when the queue is full and its overflow policy is
+// to disconnect the client
+func (c *concurrentWsConn) enqueueSubscriptionMessage(message []byte) {
+	if err := c.subscriptionQueue.push(message); err != nil {
+		log.Warnf("Disconnecting client: %s", err.Error())
+		go c.disconnect(err)
+	}
+}
+
+// enqueueSubscriptionMessageWait enqueues a subscription notification to be
+// sent waiting for room in the queue when it's full, it returns false if the
+// connection is closed
+func (c *concurrentWsConn) enqueueSubscriptionMessageWait(message []byte) bool {
+	return c.subscriptionQueue.pushWait(message)
+}
+
+// sendSubscriptionMessages sends the enqueued subscription notifications
+// until the connection is closed
+func (c *concurrentWsConn) sendSubscriptionMessages() {
+	for {
+		select {
+		case <-c.subscriptionQueue.closed:
+			return
+		case <-c.subscriptionQueue.signal:
+		}
+
+		for _, message := range c.subscriptionQueue.popAll() {
+			if c.subscriptionQueue.isClosed() {
+				return
+			}
+			c.requestMutex.Lock()
+			err := c.WriteMessage(websocket.TextMessage, message)
+			c.requestMutex.Unlock()
+			if err != nil {
+				log.Errorf("Unable to write subscription message, %s", err.Error())
+			}
+		}
+	}
 }
 
 // SetReadLimit sets the read limit to the inner web socket connection
 func (c *concurrentWsConn) SetReadLimit(limit int64) {
 	c.wsConn.SetReadLimit(limit)
 }
+
+// IsIPC returns if the inner connection is an ipc connection
+func (c *concurrentWsConn) IsIPC() bool {
+	return c.ipc
+}
@@ -0,0 +1,210 @@
+package merkletree
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	poseidon "github.com/iden3/go-iden3-crypto/goldenposeidon"
+)
+
+// nodeLen is the number of field elements hashed for each node of the tree,
+// the ones of the poseidon input besides the capacity: the hashes of the left
+// and right children of an intermediate node, which is the sibling of a
+// proof, or the remaining key and the value hash of a leaf
+const nodeLen = 2 * poseidon.CAPLEN
+
+// maxLevels is the max depth of the tree, one level for each bit of the key
+const maxLevels = 256
+
+var (
+	// ErrInvalidProof indicates that the proof does not match the root
+	ErrInvalidProof = errors.New("invalid proof")
+)
+
+// ValueScalar returns the proof value as a scalar, which is zero when the key
+// is not in the tree.
+func (p *Proof) ValueScalar() *big.Int {
+	if p == nil || p.Value == nil {
+		return big.NewInt(0)
+	}
+	return fea2scalar(p.Value)
+}
+
+// NewProof builds the proof of the value stored at the key from its byte
+// encoding, see EncodedSiblings. The ins key and value must be nil unless the
+// proof ends in the leaf of another key.
+func NewProof(key []byte, value *big.Int, siblings [][]byte, insKey []byte, insValue *big.Int) (*Proof, error) {
+	proof := &Proof{
+		Key:      scalarToh4(new(big.Int).SetBytes(key)),
+		Value:    scalar2fea(value),
+		Siblings: make([][]uint64, 0, len(siblings)),
+		IsOld0:   insKey == nil,
+	}
+	for level, sibling := range siblings {
+		if len(sibling) != 2*maxBigIntLen {
+			return nil, fmt.Errorf("invalid sibling length at level %d: %d", level, len(sibling))
+		}
+		left := scalarToh4(new(big.Int).SetBytes(sibling[:maxBigIntLen]))
+		right := scalarToh4(new(big.Int).SetBytes(sibling[maxBigIntLen:]))
+		proof.Siblings = append(proof.Siblings, append(left, right...))
+	}
+	if insKey != nil {
+		if insValue == nil {
+			return nil, errors.New("missing ins value")
+		}
+		proof.InsKey = scalarToh4(new(big.Int).SetBytes(insKey))
+		proof.InsValue = scalar2fea(insValue)
+	}
+	return proof, nil
+}
+
+// EncodedSiblings returns the siblings of the proof, each one encoded as the 32
+// bytes of the hash of its left child followed by the 32 bytes of the hash of
+// its right child.
+func (p *Proof) EncodedSiblings() [][]byte {
+	siblings := make([][]byte, 0, len(p.Siblings))
+	for _, sibling := range p.Siblings {
+		encoded := make([]byte, 0, 2*maxBigIntLen)
+		encoded = append(encoded, h4ToFilledByteSlice(sibling[:poseidon.CAPLEN])...)
+		encoded = append(encoded, h4ToFilledByteSlice(sibling[poseidon.CAPLEN:nodeLen])...)
+		siblings = append(siblings, encoded)
+	}
+	return siblings
+}
+
+// EncodedKey returns the key of the proof as 32 bytes.
+func (p *Proof) EncodedKey() []byte {
+	return h4ToFilledByteSlice(p.Key)
+}
+
+// EncodedInsKey returns the ins key of the proof as 32 bytes, nil if the proof
+// does not end in the leaf of another key.
+func (p *Proof) EncodedInsKey() []byte {
+	if p.InsKey == nil {
+		return nil
+	}
+	return h4ToFilledByteSlice(p.InsKey)
+}
+
+// InsValueScalar returns the ins value of the proof as a scalar, nil if the
+// proof does not end in the leaf of another key.
+func (p *Proof) InsValueScalar() *big.Int {
+	if p.InsKey == nil {
+		return nil
+	}
+	return fea2scalar(p.InsValue)
+}
+
+// VerifyProof checks the proof of the value stored at the proof key against the
+// given root. A proof with a zero value proves the key is not in the tree, in
+// that case the proof either ends in an empty node or in the leaf of another
+// key sharing the path with the proof key.
+func VerifyProof(root []byte, proof *Proof) error {
+	if proof == nil || len(proof.Key) != poseidon.CAPLEN {
+		return fmt.Errorf("%w: missing key", ErrInvalidProof)
+	}
+	depth := len(proof.Siblings)
+	if depth > maxLevels {
+		return fmt.Errorf("%w: too many siblings", ErrInvalidProof)
+	}
+	keyBits := splitKey(proof.Key)
+
+	var node []uint64
+	var err error
+	if proof.ValueScalar().Sign() != 0 {
+		node, err = leafHash(removeKeyBits(proof.Key, depth), proof.Value)
+	} else if !proof.IsOld0 && proof.InsKey != nil {
+		if len(proof.InsKey) != poseidon.CAPLEN {
+			return fmt.Errorf("%w: invalid ins key", ErrInvalidProof)
+		}
+		insKeyBits := splitKey(proof.InsKey)
+		for level := 0; level < depth; level++ {
+			if insKeyBits[level] != keyBits[level] {
+				return fmt.Errorf("%w: ins key is not in the path of the key", ErrInvalidProof)
+			}
+		}
+		if h4ToScalar(proof.InsKey).Cmp(h4ToScalar(proof.Key)) == 0 {
+			return fmt.Errorf("%w: ins key matches the key", ErrInvalidProof)
+		}
+		node, err = leafHash(removeKeyBits(proof.InsKey, depth), proof.InsValue)
+	} else {
+		node = make([]uint64, poseidon.CAPLEN)
+	}
+	if err != nil {
+		return err
+	}
+
+	for level := depth - 1; level >= 0; level-- {
+		sibling := proof.Siblings[level]
+		if len(sibling) < nodeLen {
+			return fmt.Errorf("%w: invalid sibling at level %d", ErrInvalidProof, level)
+		}
+		child := sibling[keyBits[level]*poseidon.CAPLEN : (keyBits[level]+1)*poseidon.CAPLEN]
+		if h4ToScalar(child).Cmp(h4ToScalar(node)) != 0 {
+			return fmt.Errorf("%w: unexpected node at level %d", ErrInvalidProof, level)
+		}
+		node, err = hashNode(sibling[:nodeLen], [poseidon.CAPLEN]uint64{})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !bytes.Equal(h4ToFilledByteSlice(node), ScalarToFilledByteSlice(new(big.Int).SetBytes(root))) {
+		return fmt.Errorf("%w: root mismatch", ErrInvalidProof)
+	}
+	return nil
+}
+
+// leafHash computes the hash of a leaf:
+// hv: H(value, [0, 0, 0, 0])
+// leaf: H([rkey[0], rkey[1], rkey[2], rkey[3], hv[0], hv[1], hv[2], hv[3]], [1, 0, 0, 0])
+func leafHash(remainingKey []uint64, value []uint64) ([]uint64, error) {
+	if len(value) != nodeLen {
+		return nil, fmt.Errorf("%w: invalid value", ErrInvalidProof)
+	}
+	valueHash, err := hashNode(value, [poseidon.CAPLEN]uint64{})
+	if err != nil {
+		return nil, err
+	}
+	return hashNode(append(append([]uint64{}, remainingKey...), valueHash...), [poseidon.CAPLEN]uint64{1})
+}
+
+// hashNode computes the poseidon hash of the field elements of a node
+func hashNode(node []uint64, capacity [poseidon.CAPLEN]uint64) ([]uint64, error) {
+	var in [nodeLen]uint64
+	copy(in[:], node)
+	result, err := poseidon.Hash(in, capacity)
+	if err != nil {
+		return nil, err
+	}
+	return result[:], nil
+}
+
+// splitKey returns the path of a key, the bit of each level is taken from the
+// key elements in turns: level i uses the bit i/4 of the element i%4.
+func splitKey(key []uint64) []int {
+	bits := make([]int, maxLevels)
+	for i := 0; i < maxLevels/poseidon.CAPLEN; i++ {
+		for j := 0; j < poseidon.CAPLEN; j++ {
+			bits[i*poseidon.CAPLEN+j] = int((key[j] >> uint(i)) & 1)
+		}
+	}
+	return bits
+}
+
+// removeKeyBits returns the remaining key of a leaf placed at the given level,
+// removing from the key the bits already used by the path.
+func removeKeyBits(key []uint64, nBits int) []uint64 {
+	fullLevels := nBits / poseidon.CAPLEN
+	remainingKey := make([]uint64, poseidon.CAPLEN)
+	for i := 0; i < poseidon.CAPLEN; i++ {
+		n := fullLevels
+		if fullLevels*poseidon.CAPLEN+i < nBits {
+			n++
+		}
+		remainingKey[i] = key[i] >> uint(n)
+	}
+	return remainingKey
+}
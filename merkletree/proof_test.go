@@ -0,0 +1,220 @@
+package merkletree
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyProof(t *testing.T) {
+	// keyA and keyC go to the left of the root, keyB goes to the right
+	keyA := []uint64{2, 5, 7, 9}
+	keyB := []uint64{3, 5, 7, 9}
+	keyC := []uint64{4, 5, 7, 9}
+	valueA := scalar2fea(big.NewInt(100))
+	valueB := scalar2fea(big.NewInt(200))
+	zero := make([]uint64, 4)
+
+	leafA, err := leafHash(removeKeyBits(keyA, 1), valueA)
+	require.NoError(t, err)
+	leafB, err := leafHash(removeKeyBits(keyB, 1), valueB)
+	require.NoError(t, err)
+	rootNode := append(append([]uint64{}, leafA...), leafB...)
+	rootH4, err := hashNode(rootNode, [4]uint64{})
+	require.NoError(t, err)
+	root := h4ToFilledByteSlice(rootH4)
+
+	singleLeafH4, err := leafHash(keyA, valueA)
+	require.NoError(t, err)
+	singleLeafRoot := h4ToFilledByteSlice(singleLeafH4)
+
+	halfEmptyNode := append(append([]uint64{}, leafA...), zero...)
+	halfEmptyH4, err := hashNode(halfEmptyNode, [4]uint64{})
+	require.NoError(t, err)
+	halfEmptyRoot := h4ToFilledByteSlice(halfEmptyH4)
+
+	testCases := []struct {
+		name  string
+		root  []byte
+		proof *Proof
+		valid bool
+	}{
+		{
+			name:  "existing left leaf",
+			root:  root,
+			proof: &Proof{Key: keyA, Value: valueA, Siblings: [][]uint64{rootNode}, IsOld0: true},
+			valid: true,
+		},
+		{
+			name:  "existing right leaf",
+			root:  root,
+			proof: &Proof{Key: keyB, Value: valueB, Siblings: [][]uint64{rootNode}, IsOld0: true},
+			valid: true,
+		},
+		{
+			name:  "wrong value",
+			root:  root,
+			proof: &Proof{Key: keyA, Value: valueB, Siblings: [][]uint64{rootNode}, IsOld0: true},
+		},
+		{
+			name:  "wrong root",
+			root:  singleLeafRoot,
+			proof: &Proof{Key: keyA, Value: valueA, Siblings: [][]uint64{rootNode}, IsOld0: true},
+		},
+		{
+			name:  "missing key with another leaf in the path",
+			root:  root,
+			proof: &Proof{Key: keyC, Siblings: [][]uint64{rootNode}, InsKey: keyA, InsValue: valueA},
+			valid: true,
+		},
+		{
+			name:  "missing key claiming an empty node",
+			root:  root,
+			proof: &Proof{Key: keyC, Siblings: [][]uint64{rootNode}, IsOld0: true},
+		},
+		{
+			name:  "missing key with a leaf out of the path",
+			root:  root,
+			proof: &Proof{Key: keyC, Siblings: [][]uint64{rootNode}, InsKey: keyB, InsValue: valueB},
+		},
+		{
+			name:  "ins key matching the key",
+			root:  root,
+			proof: &Proof{Key: keyA, Siblings: [][]uint64{rootNode}, InsKey: keyA, InsValue: valueA},
+		},
+		{
+			name:  "single leaf tree",
+			root:  singleLeafRoot,
+			proof: &Proof{Key: keyA, Value: valueA, IsOld0: true},
+			valid: true,
+		},
+		{
+			name:  "missing key in single leaf tree",
+			root:  singleLeafRoot,
+			proof: &Proof{Key: keyB, InsKey: keyA, InsValue: valueA},
+			valid: true,
+		},
+		{
+			name:  "missing key in empty node",
+			root:  halfEmptyRoot,
+			proof: &Proof{Key: keyB, Siblings: [][]uint64{halfEmptyNode}, IsOld0: true},
+			valid: true,
+		},
+		{
+			name:  "missing key in empty tree",
+			root:  make([]byte, 32),
+			proof: &Proof{Key: keyA, IsOld0: true},
+			valid: true,
+		},
+		{
+			name:  "invalid sibling",
+			root:  root,
+			proof: &Proof{Key: keyA, Value: valueA, Siblings: [][]uint64{leafA}, IsOld0: true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := VerifyProof(tc.root, tc.proof)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidProof)
+			}
+		})
+	}
+}
+
+func TestVerifyProofReferenceVectors(t *testing.T) {
+	// the roots are the ones of test/vectors/src/merkle-tree/smt-raw.json and
+	// the siblings are encoded the way the executor returns them
+	const (
+		singleLeafRoot  = "0x42bb2f66296df03552203ae337815976ca9c1bf52cc1bdd59399ede8fea8a822"
+		threeLeavesRoot = "0xa7db6a59f3df30492054fe2419cf1584e4100f915c75e957938477562c2f2cea"
+		sibling         = "0x42bb2f66296df03552203ae337815976ca9c1bf52cc1bdd59399ede8fea8a8220a874fe43347908f073d2c5ce5243f33339470c80898da2940707f674ec5bd18"
+	)
+
+	testCases := []struct {
+		name     string
+		root     string
+		key      int64
+		value    int64
+		siblings []string
+		insKey   *big.Int
+		insValue *big.Int
+		valid    bool
+	}{
+		{name: "single leaf", root: singleLeafRoot, key: 0, value: 1, valid: true},
+		{name: "single leaf wrong value", root: singleLeafRoot, key: 0, value: 2},
+		{name: "missing key in single leaf tree", root: singleLeafRoot, key: 1, insKey: big.NewInt(0), insValue: big.NewInt(1), valid: true},
+		{name: "leaf of three leaves tree", root: threeLeavesRoot, key: 0, value: 1, siblings: []string{sibling}, valid: true},
+		{name: "leaf of three leaves tree wrong value", root: threeLeavesRoot, key: 0, value: 2, siblings: []string{sibling}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root, err := hex.DecodeHex(tc.root)
+			require.NoError(t, err)
+			siblings := make([][]byte, 0, len(tc.siblings))
+			for _, s := range tc.siblings {
+				sibling, err := hex.DecodeHex(s)
+				require.NoError(t, err)
+				siblings = append(siblings, sibling)
+			}
+			var insKey []byte
+			if tc.insKey != nil {
+				insKey = tc.insKey.Bytes()
+			}
+
+			proof, err := NewProof(big.NewInt(tc.key).Bytes(), big.NewInt(tc.value), siblings, insKey, tc.insValue)
+			require.NoError(t, err)
+
+			err = VerifyProof(root, proof)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestSplitKeyAndRemoveKeyBits(t *testing.T) {
+	key := []uint64{0b101, 0b010, 0b111, 0b000}
+
+	bits := splitKey(key)
+	assert.Equal(t, []int{1, 0, 1, 0, 0, 1, 1, 0, 1, 0, 1, 0}, bits[:12])
+
+	assert.Equal(t, key, removeKeyBits(key, 0))
+	assert.Equal(t, []uint64{0b10, 0b01, 0b111, 0b000}, removeKeyBits(key, 2))
+	assert.Equal(t, []uint64{0b1, 0b0, 0b11, 0b000}, removeKeyBits(key, 6))
+}
+
+func TestProofEncoding(t *testing.T) {
+	keyA := []uint64{2, 5, 7, 9}
+	keyB := []uint64{3, 5, 7, 9}
+	valueA := scalar2fea(big.NewInt(100))
+	valueB := scalar2fea(big.NewInt(200))
+
+	leafA, err := leafHash(removeKeyBits(keyA, 1), valueA)
+	require.NoError(t, err)
+	leafB, err := leafHash(removeKeyBits(keyB, 1), valueB)
+	require.NoError(t, err)
+	rootNode := append(append([]uint64{}, leafA...), leafB...)
+	rootH4, err := hashNode(rootNode, [4]uint64{})
+	require.NoError(t, err)
+	root := h4ToFilledByteSlice(rootH4)
+
+	proof := &Proof{Key: keyB, Value: valueB, Siblings: [][]uint64{rootNode}, IsOld0: true}
+
+	decoded, err := NewProof(proof.EncodedKey(), proof.ValueScalar(), proof.EncodedSiblings(), proof.EncodedInsKey(), proof.InsValueScalar())
+	require.NoError(t, err)
+	assert.Equal(t, proof, decoded)
+	assert.NoError(t, VerifyProof(root, decoded))
+
+	_, err = NewProof(proof.EncodedKey(), proof.ValueScalar(), [][]byte{{1}}, nil, nil)
+	assert.EqualError(t, err, "invalid sibling length at level 0: 1")
+}
@@ -123,6 +123,49 @@ func (tree *StateTree) GetStorageAt(ctx context.Context, address common.Address,
 	return fea2scalar(proof.Value), nil
 }
 
+// GetBalanceProof returns the proof of the balance leaf of the given address.
+func (tree *StateTree) GetBalanceProof(ctx context.Context, address common.Address, root []byte) (*Proof, error) {
+	key, err := KeyEthAddrBalance(address)
+	if err != nil {
+		return nil, err
+	}
+	return tree.getProofByKey(ctx, root, key)
+}
+
+// GetNonceProof returns the proof of the nonce leaf of the given address.
+func (tree *StateTree) GetNonceProof(ctx context.Context, address common.Address, root []byte) (*Proof, error) {
+	key, err := KeyEthAddrNonce(address)
+	if err != nil {
+		return nil, err
+	}
+	return tree.getProofByKey(ctx, root, key)
+}
+
+// GetCodeHashProof returns the proof of the code hash leaf of the given address.
+func (tree *StateTree) GetCodeHashProof(ctx context.Context, address common.Address, root []byte) (*Proof, error) {
+	key, err := KeyContractCode(address)
+	if err != nil {
+		return nil, err
+	}
+	return tree.getProofByKey(ctx, root, key)
+}
+
+// GetStorageProof returns the proof of the storage leaf of the given address
+// at the specified position.
+func (tree *StateTree) GetStorageProof(ctx context.Context, address common.Address, position *big.Int, root []byte) (*Proof, error) {
+	key, err := KeyContractStorage(address, position.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return tree.getProofByKey(ctx, root, key)
+}
+
+func (tree *StateTree) getProofByKey(ctx context.Context, root []byte, key []byte) (*Proof, error) {
+	r := new(big.Int).SetBytes(root)
+	k := new(big.Int).SetBytes(key)
+	return tree.getProof(ctx, scalarToh4(r), scalarToh4(k))
+}
+
 // SetBalance sets balance.
 func (tree *StateTree) SetBalance(ctx context.Context, address common.Address, balance *big.Int, root []byte, uuid string) (newRoot []byte, proof *UpdateProof, err error) {
 	if balance.Cmp(big.NewInt(0)) == -1 {
@@ -260,6 +303,49 @@ func (tree *StateTree) get(ctx context.Context, root, key []uint64) (*Proof, err
 	}, nil
 }
 
+// getProof works like get, but it also retrieves the siblings found in the
+// path of the key, so the result can be verified against the root.
+func (tree *StateTree) getProof(ctx context.Context, root, key []uint64) (*Proof, error) {
+	result, err := tree.grpcClient.Get(ctx, &hashdb.GetRequest{
+		Root:    &hashdb.Fea{Fe0: root[0], Fe1: root[1], Fe2: root[2], Fe3: root[3]},
+		Key:     &hashdb.Fea{Fe0: key[0], Fe1: key[1], Fe2: key[2], Fe3: key[3]},
+		Details: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := string2fea(result.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	siblings := make([][]uint64, len(result.Siblings))
+	for level := range siblings {
+		sibling, found := result.Siblings[uint64(level)]
+		if !found || len(sibling.Sibling) < nodeLen {
+			return nil, fmt.Errorf("missing sibling at level %d", level)
+		}
+		siblings[level] = sibling.Sibling[:nodeLen]
+	}
+
+	proof := &Proof{
+		Root:     []uint64{root[0], root[1], root[2], root[3]},
+		Key:      key,
+		Value:    value,
+		Siblings: siblings,
+		IsOld0:   result.IsOld0,
+	}
+	if !result.IsOld0 && result.InsKey != nil && result.InsValue != "" {
+		proof.InsKey = []uint64{result.InsKey.Fe0, result.InsKey.Fe1, result.InsKey.Fe2, result.InsKey.Fe3}
+		proof.InsValue, err = string2fea(result.InsValue)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return proof, nil
+}
+
 func (tree *StateTree) getProgram(ctx context.Context, key []uint64) (*ProgramProof, error) {
 	result, err := tree.grpcClient.GetProgram(ctx, &hashdb.GetProgramRequest{
 		Key: &hashdb.Fea{Fe0: key[0], Fe1: key[1], Fe2: key[2], Fe3: key[3]},
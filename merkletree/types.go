@@ -24,6 +24,18 @@ type Proof struct {
 	Key []uint64
 	// Value is the proof value.
 	Value []uint64
+	// Siblings are the intermediate nodes found in the path from the root to
+	// the key, sorted by level. Each node contains the hashes of its left and
+	// right children.
+	Siblings [][]uint64
+	// InsKey is the key of the leaf found in the path of the key when the key
+	// is not in the tree.
+	InsKey []uint64
+	// InsValue is the value of the leaf found in the path of the key when the
+	// key is not in the tree.
+	InsValue []uint64
+	// IsOld0 indicates that no leaf was found in the path of the key.
+	IsOld0 bool
 }
 
 // UpdateProof is a proof generated on Set operation.
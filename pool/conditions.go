@@ -0,0 +1,71 @@
+package pool
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TransactionConditions are the conditions a transaction sent with
+// eth_sendRawTransactionConditional requires to be included in a block: the
+// storage slots of the known accounts must hold the expected values and the
+// block number and timestamp must be in the given ranges
+type TransactionConditions struct {
+	KnownAccounts  map[common.Address]map[common.Hash]common.Hash `json:"knownAccounts,omitempty"`
+	BlockNumberMin *uint64                                        `json:"blockNumberMin,omitempty"`
+	BlockNumberMax *uint64                                        `json:"blockNumberMax,omitempty"`
+	TimestampMin   *uint64                                        `json:"timestampMin,omitempty"`
+	TimestampMax   *uint64                                        `json:"timestampMax,omitempty"`
+}
+
+// CheckBlockNumber checks the block number is in the range of the conditions, a
+// block number lower than the min returns ErrTxConditionsNotYetMet since the tx
+// can be included in a later block
+func (c *TransactionConditions) CheckBlockNumber(blockNumber uint64) error {
+	if c.BlockNumberMin != nil && blockNumber < *c.BlockNumberMin {
+		return fmt.Errorf("%w: block number %d is lower than the min block number %d", ErrTxConditionsNotYetMet, blockNumber, *c.BlockNumberMin)
+	}
+	if c.BlockNumberMax != nil && blockNumber > *c.BlockNumberMax {
+		return fmt.Errorf("%w: block number %d is greater than the max block number %d", ErrTxConditionsNotMet, blockNumber, *c.BlockNumberMax)
+	}
+	return nil
+}
+
+// CheckTimestamp checks the block timestamp is in the range of the conditions, a
+// timestamp lower than the min returns ErrTxConditionsNotYetMet since the tx can
+// be included in a later block
+func (c *TransactionConditions) CheckTimestamp(timestamp uint64) error {
+	if c.TimestampMin != nil && timestamp < *c.TimestampMin {
+		return fmt.Errorf("%w: timestamp %d is lower than the min timestamp %d", ErrTxConditionsNotYetMet, timestamp, *c.TimestampMin)
+	}
+	if c.TimestampMax != nil && timestamp > *c.TimestampMax {
+		return fmt.Errorf("%w: timestamp %d is greater than the max timestamp %d", ErrTxConditionsNotMet, timestamp, *c.TimestampMax)
+	}
+	return nil
+}
+
+// CheckKnownAccounts checks the storage slots of the known accounts hold the
+// expected values, the values are read with the given function
+func (c *TransactionConditions) CheckKnownAccounts(getStorageAt func(address common.Address, slot common.Hash) (common.Hash, error)) error {
+	for address, slots := range c.KnownAccounts {
+		for slot, expected := range slots {
+			value, err := getStorageAt(address, slot)
+			if err != nil {
+				return err
+			}
+			if value != expected {
+				return fmt.Errorf("%w: storage slot %s of account %s holds %s instead of %s", ErrTxConditionsNotMet, slot.String(), address.String(), value.String(), expected.String())
+			}
+		}
+	}
+	return nil
+}
+
+// KnownSlotsCount returns the number of storage slots of the known accounts
+func (c *TransactionConditions) KnownSlotsCount() int {
+	count := 0
+	for _, slots := range c.KnownAccounts {
+		count += len(slots)
+	}
+	return count
+}
@@ -0,0 +1,67 @@
+package pool
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TransactionConditions(t *testing.T) {
+	min, max := uint64(10), uint64(20)
+	conditions := TransactionConditions{
+		KnownAccounts: map[common.Address]map[common.Hash]common.Hash{
+			common.HexToAddress("0x1"): {common.HexToHash("0x1"): common.HexToHash("0x2")},
+		},
+		BlockNumberMin: &min,
+		BlockNumberMax: &max,
+		TimestampMin:   &min,
+		TimestampMax:   &max,
+	}
+
+	t.Run("block number", func(t *testing.T) {
+		assert.NoError(t, conditions.CheckBlockNumber(10))
+		assert.NoError(t, conditions.CheckBlockNumber(20))
+		err := conditions.CheckBlockNumber(9)
+		assert.ErrorIs(t, err, ErrTxConditionsNotYetMet)
+		assert.Equal(t, "transaction conditions not met yet: block number 9 is lower than the min block number 10", err.Error())
+		err = conditions.CheckBlockNumber(21)
+		assert.ErrorIs(t, err, ErrTxConditionsNotMet)
+		assert.Equal(t, "transaction conditions not met: block number 21 is greater than the max block number 20", err.Error())
+	})
+
+	t.Run("timestamp", func(t *testing.T) {
+		assert.NoError(t, conditions.CheckTimestamp(15))
+		assert.ErrorIs(t, conditions.CheckTimestamp(9), ErrTxConditionsNotYetMet)
+		assert.ErrorIs(t, conditions.CheckTimestamp(21), ErrTxConditionsNotMet)
+	})
+
+	t.Run("known accounts", func(t *testing.T) {
+		storage := map[common.Hash]common.Hash{common.HexToHash("0x1"): common.HexToHash("0x2")}
+		getStorageAt := func(address common.Address, slot common.Hash) (common.Hash, error) {
+			return storage[slot], nil
+		}
+		assert.NoError(t, conditions.CheckKnownAccounts(getStorageAt))
+
+		storage[common.HexToHash("0x1")] = common.HexToHash("0x3")
+		err := conditions.CheckKnownAccounts(getStorageAt)
+		assert.ErrorIs(t, err, ErrTxConditionsNotMet)
+		assert.Contains(t, err.Error(), "storage slot 0x0000000000000000000000000000000000000000000000000000000000000001 of account 0x0000000000000000000000000000000000000001 holds")
+
+		failure := errors.New("failed to get storage")
+		err = conditions.CheckKnownAccounts(func(address common.Address, slot common.Hash) (common.Hash, error) {
+			return common.Hash{}, failure
+		})
+		assert.ErrorIs(t, err, failure)
+	})
+
+	t.Run("no conditions", func(t *testing.T) {
+		empty := TransactionConditions{}
+		assert.NoError(t, empty.CheckBlockNumber(0))
+		assert.NoError(t, empty.CheckTimestamp(0))
+		assert.NoError(t, empty.CheckKnownAccounts(nil))
+		assert.Equal(t, 0, empty.KnownSlotsCount())
+		assert.Equal(t, 1, conditions.KnownSlotsCount())
+	})
+}
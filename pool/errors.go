@@ -76,4 +76,13 @@ var (
 
 	// ErrZeroL1GasPrice is returned if the L1 gas price is 0.
 	ErrZeroL1GasPrice = errors.New("L1 gas price 0")
+
+	// ErrTxConditionsNotMet is returned if the block or the state a conditional
+	// transaction is going to be included in don't meet its conditions.
+	ErrTxConditionsNotMet = errors.New("transaction conditions not met")
+
+	// ErrTxConditionsNotYetMet is returned if the block a conditional transaction
+	// is going to be included in is earlier than the min block number or timestamp
+	// of its conditions, the transaction can still be included in a later block.
+	ErrTxConditionsNotYetMet = errors.New("transaction conditions not met yet")
 )
@@ -3,6 +3,7 @@ package pgpoolstorage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -53,7 +54,17 @@ func (p *PostgresPoolStorage) AddTx(ctx context.Context, tx pool.Transaction) er
 	gasPrice := tx.GasPrice().Uint64()
 	nonce := tx.Nonce()
 
+	var conditions []byte
+	if tx.Conditions != nil {
+		conditions, err = json.Marshal(tx.Conditions)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the stored tx is also recorded in the status history
 	sql := `
+		WITH stored AS (
 		INSERT INTO pool.transaction 
 		(
 			hash,
@@ -74,10 +85,11 @@ func (p *PostgresPoolStorage) AddTx(ctx context.Context, tx pool.Transaction) er
 			from_address,
 			is_wip,
 			ip,
-			failed_reason
+			failed_reason,
+			conditions
 		) 
 		VALUES 
-			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, NULL)
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, NULL, $19)
 			ON CONFLICT (hash) DO UPDATE SET 
 			encoded = $2,
 			decoded = $3,
@@ -96,7 +108,12 @@ func (p *PostgresPoolStorage) AddTx(ctx context.Context, tx pool.Transaction) er
 			from_address = $16,
 			is_wip = $17,
 			ip = $18,
-			failed_reason = NULL
+			failed_reason = NULL,
+			conditions = $19
+		RETURNING hash, status, received_at
+		)
+		INSERT INTO pool.transaction_status_history (hash, status, changed_at)
+		SELECT hash, status, received_at FROM stored
 	`
 
 	// Get FromAddress from the JSON data
@@ -124,7 +141,8 @@ func (p *PostgresPoolStorage) AddTx(ctx context.Context, tx pool.Transaction) er
 		tx.ReceivedAt,
 		fromAddress,
 		tx.IsWIP,
-		tx.IP); err != nil {
+		tx.IP,
+		conditions); err != nil {
 		return err
 	}
 	return nil
@@ -141,11 +159,11 @@ func (p *PostgresPoolStorage) GetTxsByStatus(ctx context.Context, status pool.Tx
 	)
 	if limit == 0 {
 		sql = `SELECT encoded, status, received_at, is_wip, ip, cumulative_gas_used, used_keccak_hashes, used_poseidon_hashes, used_poseidon_paddings, used_mem_aligns,
-				used_arithmetics, used_binaries, used_steps, failed_reason FROM pool.transaction WHERE status = $1 ORDER BY gas_price DESC`
+				used_arithmetics, used_binaries, used_steps, failed_reason, conditions FROM pool.transaction WHERE status = $1 ORDER BY gas_price DESC`
 		rows, err = p.db.Query(ctx, sql, status.String())
 	} else {
 		sql = `SELECT encoded, status, received_at, is_wip, ip, cumulative_gas_used, used_keccak_hashes, used_poseidon_hashes, used_poseidon_paddings, used_mem_aligns,
-				used_arithmetics, used_binaries, used_steps, failed_reason FROM pool.transaction WHERE status = $1 ORDER BY gas_price DESC LIMIT $2`
+				used_arithmetics, used_binaries, used_steps, failed_reason, conditions FROM pool.transaction WHERE status = $1 ORDER BY gas_price DESC LIMIT $2`
 		rows, err = p.db.Query(ctx, sql, status.String(), limit)
 	}
 	if err != nil {
@@ -174,7 +192,7 @@ func (p *PostgresPoolStorage) GetNonWIPPendingTxs(ctx context.Context) ([]pool.T
 	)
 
 	sql = `SELECT encoded, status, received_at, is_wip, ip, cumulative_gas_used, used_keccak_hashes, used_poseidon_hashes, used_poseidon_paddings, used_mem_aligns,
-		used_arithmetics, used_binaries, used_steps, failed_reason FROM pool.transaction WHERE is_wip IS FALSE and status = $1`
+		used_arithmetics, used_binaries, used_steps, failed_reason, conditions FROM pool.transaction WHERE is_wip IS FALSE and status = $1`
 	rows, err = p.db.Query(ctx, sql, pool.TxStatusPending)
 
 	if err != nil {
@@ -371,7 +389,7 @@ func (p *PostgresPoolStorage) CountTransactionsByFromAndStatus(ctx context.Conte
 // UpdateTxStatus updates a transaction status accordingly to the
 // provided status and hash
 func (p *PostgresPoolStorage) UpdateTxStatus(ctx context.Context, updateInfo pool.TxStatusUpdateInfo) error {
-	sql := "UPDATE pool.transaction SET status = $1, is_wip = $2"
+	sql := "WITH updated AS (UPDATE pool.transaction SET status = $1, is_wip = $2"
 	args := []interface{}{updateInfo.NewStatus, updateInfo.IsWIP}
 
 	if updateInfo.FailedReason != nil {
@@ -382,6 +400,11 @@ func (p *PostgresPoolStorage) UpdateTxStatus(ctx context.Context, updateInfo poo
 		sql += " WHERE hash = $3"
 	}
 
+	// the new status is recorded in the status history only if the tx exists in the pool
+	sql += ` RETURNING hash, status, failed_reason)
+		INSERT INTO pool.transaction_status_history (hash, status, failed_reason, changed_at)
+		SELECT hash, status, failed_reason, NOW() FROM updated`
+
 	args = append(args, updateInfo.Hash.Hex())
 
 	if _, err := p.db.Exec(ctx, sql, args...); err != nil {
@@ -408,18 +431,38 @@ func (p *PostgresPoolStorage) DeleteTransactionsByHashes(ctx context.Context, ha
 		hh = append(hh, h.Hex())
 	}
 
-	query := "DELETE FROM pool.transaction WHERE hash = ANY ($1)"
-	if _, err := p.db.Exec(ctx, query, hh); err != nil {
+	// the deleted txs are recorded as removed in the status history
+	query := `
+		WITH deleted AS (
+			DELETE FROM pool.transaction WHERE hash = ANY ($1) RETURNING hash
+		)
+		INSERT INTO pool.transaction_status_history (hash, status, changed_at)
+		SELECT hash, $2::VARCHAR, NOW() FROM deleted`
+	if _, err := p.db.Exec(ctx, query, hh, pool.TxStatusRemoved); err != nil {
 		return err
 	}
 	return nil
 }
 
-// DeleteFailedTransactionsOlderThan deletes all failed transactions older than the given date
+// DeleteFailedTransactionsOlderThan deletes all failed transactions older than the given date,
+// and the status history of the txs removed from the pool before that date
 func (p *PostgresPoolStorage) DeleteFailedTransactionsOlderThan(ctx context.Context, date time.Time) error {
-	sql := `DELETE FROM pool.transaction WHERE status = 'failed' and received_at < $1`
+	// the deleted txs are recorded as removed in the status history, keeping
+	// the reason of the failure. The history of a tx is kept until the same
+	// time has passed since it was removed from the pool, unless it was added
+	// back to the pool
+	sql := `
+		WITH deleted AS (
+			DELETE FROM pool.transaction WHERE status = 'failed' and received_at < $1 RETURNING hash, failed_reason
+		), purged AS (
+			DELETE FROM pool.transaction_status_history h
+			 WHERE h.hash IN (SELECT hash FROM pool.transaction_status_history WHERE status = $2 AND changed_at < $1)
+			   AND NOT EXISTS (SELECT 1 FROM pool.transaction t WHERE t.hash = h.hash)
+		)
+		INSERT INTO pool.transaction_status_history (hash, status, failed_reason, changed_at)
+		SELECT hash, $2::VARCHAR, failed_reason, NOW() FROM deleted`
 
-	if _, err := p.db.Exec(ctx, sql, date); err != nil {
+	if _, err := p.db.Exec(ctx, sql, date, pool.TxStatusRemoved); err != nil {
 		return err
 	}
 	return nil
@@ -504,7 +547,7 @@ func (p *PostgresPoolStorage) IsTxPending(ctx context.Context, hash common.Hash)
 // GetTxsByFromAndNonce get all the transactions from the pool with the same from and nonce
 func (p *PostgresPoolStorage) GetTxsByFromAndNonce(ctx context.Context, from common.Address, nonce uint64) ([]pool.Transaction, error) {
 	sql := `SELECT encoded, status, received_at, is_wip, ip, cumulative_gas_used, used_keccak_hashes, used_poseidon_hashes, 
-				   used_poseidon_paddings, used_mem_aligns,	used_arithmetics, used_binaries, used_steps, failed_reason
+				   used_poseidon_paddings, used_mem_aligns,	used_arithmetics, used_binaries, used_steps, failed_reason, conditions
 	          FROM pool.transaction
 			 WHERE from_address = $1
 			   AND nonce = $2`
@@ -528,6 +571,36 @@ func (p *PostgresPoolStorage) GetTxsByFromAndNonce(ctx context.Context, from com
 	return txs, nil
 }
 
+// GetPendingTxsByFrom gets all the pending transactions from the pool sent by
+// the given address sorted by nonce
+func (p *PostgresPoolStorage) GetPendingTxsByFrom(ctx context.Context, from common.Address) ([]pool.Transaction, error) {
+	sql := `SELECT encoded, status, received_at, is_wip, ip, cumulative_gas_used, used_keccak_hashes, used_poseidon_hashes,
+				   used_poseidon_paddings, used_mem_aligns,	used_arithmetics, used_binaries, used_steps, failed_reason, conditions
+	          FROM pool.transaction
+			 WHERE from_address = $1
+			   AND status = $2
+		  ORDER BY nonce ASC`
+	rows, err := p.db.Query(ctx, sql, from.String(), pool.TxStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	txs := make([]pool.Transaction, 0, len(rows.RawValues()))
+	for rows.Next() {
+		tx, err := scanTx(rows)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, *tx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return txs, nil
+}
+
 // GetTxFromAddressFromByHash gets tx from address by hash
 func (p *PostgresPoolStorage) GetTxFromAddressFromByHash(ctx context.Context, hash common.Hash) (common.Address, uint64, error) {
 	query := `SELECT from_address, nonce
@@ -588,12 +661,13 @@ func (p *PostgresPoolStorage) GetTxByHash(ctx context.Context, hash common.Hash)
 		encoded, status, ip string
 		receivedAt          time.Time
 		isWIP               bool
+		failedReason        *string
 	)
 
-	sql := `SELECT encoded, status, received_at, is_wip, ip
+	sql := `SELECT encoded, status, received_at, is_wip, ip, failed_reason
 	          FROM pool.transaction
 			 WHERE hash = $1`
-	err := p.db.QueryRow(ctx, sql, hash.String()).Scan(&encoded, &status, &receivedAt, &isWIP, &ip)
+	err := p.db.QueryRow(ctx, sql, hash.String()).Scan(&encoded, &status, &receivedAt, &isWIP, &ip, &failedReason)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, pool.ErrNotFound
 	} else if err != nil {
@@ -611,16 +685,53 @@ func (p *PostgresPoolStorage) GetTxByHash(ctx context.Context, hash common.Hash)
 	}
 
 	poolTx := &pool.Transaction{
-		ReceivedAt:  receivedAt,
-		Status:      pool.TxStatus(status),
-		Transaction: *tx,
-		IsWIP:       isWIP,
-		IP:          ip,
+		ReceivedAt:   receivedAt,
+		Status:       pool.TxStatus(status),
+		Transaction:  *tx,
+		IsWIP:        isWIP,
+		IP:           ip,
+		FailedReason: failedReason,
 	}
 
 	return poolTx, nil
 }
 
+// GetTxStatusHistory returns the status transitions of a tx ordered from the oldest
+// to the newest, the history is kept after the tx is deleted from the pool
+func (p *PostgresPoolStorage) GetTxStatusHistory(ctx context.Context, hash common.Hash) ([]pool.TxStatusTransition, error) {
+	sql := `SELECT status, failed_reason, changed_at
+	          FROM pool.transaction_status_history
+			 WHERE hash = $1
+		  ORDER BY item_id ASC`
+	rows, err := p.db.Query(ctx, sql, hash.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := make([]pool.TxStatusTransition, 0, len(rows.RawValues()))
+	for rows.Next() {
+		var (
+			status       string
+			failedReason *string
+			changedAt    time.Time
+		)
+		if err := rows.Scan(&status, &failedReason, &changedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, pool.TxStatusTransition{
+			Status:       pool.TxStatus(status),
+			FailedReason: failedReason,
+			ChangedAt:    changedAt,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
 func scanTx(rows pgx.Rows) (*pool.Transaction, error) {
 	var (
 		encoded, status, ip  string
@@ -635,10 +746,11 @@ func scanTx(rows pgx.Rows) (*pool.Transaction, error) {
 		usedBinaries         uint32
 		usedSteps            uint32
 		failedReason         *string
+		conditions           []byte
 	)
 
 	if err := rows.Scan(&encoded, &status, &receivedAt, &isWIP, &ip, &cumulativeGasUsed, &usedKeccakHashes, &usedPoseidonHashes,
-		&usedPoseidonPaddings, &usedMemAligns, &usedArithmetics, &usedBinaries, &usedSteps, &failedReason); err != nil {
+		&usedPoseidonPaddings, &usedMemAligns, &usedArithmetics, &usedBinaries, &usedSteps, &failedReason, &conditions); err != nil {
 		return nil, err
 	}
 
@@ -667,13 +779,26 @@ func scanTx(rows pgx.Rows) (*pool.Transaction, error) {
 	tx.ZKCounters.UsedSteps = usedSteps
 	tx.FailedReason = failedReason
 
+	if conditions != nil {
+		tx.Conditions = new(pool.TransactionConditions)
+		if err := json.Unmarshal(conditions, tx.Conditions); err != nil {
+			return nil, err
+		}
+	}
+
 	return tx, nil
 }
 
 // DeleteTransactionByHash deletes tx by its hash
 func (p *PostgresPoolStorage) DeleteTransactionByHash(ctx context.Context, hash common.Hash) error {
-	query := "DELETE FROM pool.transaction WHERE hash = $1"
-	if _, err := p.db.Exec(ctx, query, hash); err != nil {
+	// the deleted tx is recorded as removed in the status history
+	query := `
+		WITH deleted AS (
+			DELETE FROM pool.transaction WHERE hash = $1 RETURNING hash
+		)
+		INSERT INTO pool.transaction_status_history (hash, status, changed_at)
+		SELECT hash, $2::VARCHAR, NOW() FROM deleted`
+	if _, err := p.db.Exec(ctx, query, hash.String(), pool.TxStatusRemoved); err != nil {
 		return err
 	}
 	return nil
@@ -699,7 +824,13 @@ func (p *PostgresPoolStorage) GetTxZkCountersByHash(ctx context.Context, hash co
 
 // MarkWIPTxsAsPending updates WIP status to non WIP
 func (p *PostgresPoolStorage) MarkWIPTxsAsPending(ctx context.Context) error {
-	const query = `UPDATE pool.transaction SET is_wip = false WHERE is_wip = true`
+	// the txs put back to be selected are recorded in the status history
+	const query = `
+		WITH updated AS (
+			UPDATE pool.transaction SET is_wip = false WHERE is_wip = true RETURNING hash, status, failed_reason
+		)
+		INSERT INTO pool.transaction_status_history (hash, status, failed_reason, changed_at)
+		SELECT hash, status, failed_reason, NOW() FROM updated`
 	if _, err := p.db.Exec(ctx, query); err != nil {
 		return err
 	}
@@ -170,16 +170,26 @@ func (p *Pool) StartPollingMinSuggestedGasPrice(ctx context.Context) {
 
 // AddTx adds a transaction to the pool with the pending state
 func (p *Pool) AddTx(ctx context.Context, tx types.Transaction, ip string) error {
+	return p.AddTxWithConditions(ctx, tx, ip, nil)
+}
+
+// AddTxWithConditions adds a transaction to the pool with the pending state,
+// the sequencer only includes it in a block that meets the given conditions
+func (p *Pool) AddTxWithConditions(ctx context.Context, tx types.Transaction, ip string, conditions *TransactionConditions) error {
 	poolTx := NewTransaction(tx, ip, false)
 	if err := p.validateTx(ctx, *poolTx); err != nil {
 		return err
 	}
 
-	return p.StoreTx(ctx, tx, ip, false)
+	return p.storeTx(ctx, tx, ip, false, conditions)
 }
 
 // StoreTx adds a transaction to the pool with the pending state
 func (p *Pool) StoreTx(ctx context.Context, tx types.Transaction, ip string, isWIP bool) error {
+	return p.storeTx(ctx, tx, ip, isWIP, nil)
+}
+
+func (p *Pool) storeTx(ctx context.Context, tx types.Transaction, ip string, isWIP bool, conditions *TransactionConditions) error {
 	// Execute transaction to calculate its zkCounters
 	preExecutionResponse, err := p.preExecuteTx(ctx, tx)
 	if errors.Is(err, runtime.ErrIntrinsicInvalidBatchGasLimit) {
@@ -238,6 +248,7 @@ func (p *Pool) StoreTx(ctx context.Context, tx types.Transaction, ip string, isW
 
 	poolTx := NewTransaction(tx, ip, isWIP)
 	poolTx.ZKCounters = preExecutionResponse.usedZkCounters
+	poolTx.Conditions = conditions
 
 	return p.storage.AddTx(ctx, *poolTx)
 }
@@ -262,6 +262,150 @@ func Test_AddTx(t *testing.T) {
 	assert.Equal(t, 1, c, "invalid number of txs in the pool")
 }
 
+func Test_AddTxWithConditions(t *testing.T) {
+	initOrResetDB(t)
+
+	stateSqlDB, err := db.NewSQLDB(stateDBCfg)
+	require.NoError(t, err)
+	defer stateSqlDB.Close() //nolint:gosec,errcheck
+
+	eventStorage, err := nileventstorage.NewNilEventStorage()
+	if err != nil {
+		log.Fatal(err)
+	}
+	eventLog := event.NewEventLog(event.Config{}, eventStorage)
+
+	st := newState(stateSqlDB, eventLog)
+
+	genesisBlock := state.Block{
+		BlockNumber: 0,
+		BlockHash:   state.ZeroHash,
+		ParentHash:  state.ZeroHash,
+		ReceivedAt:  time.Now(),
+	}
+	ctx := context.Background()
+	dbTx, err := st.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	genesis.FirstBatchData.Timestamp = uint64(time.Now().Unix())
+	_, err = st.SetGenesis(ctx, genesisBlock, genesis, metrics.SynchronizerCallerLabel, dbTx)
+	require.NoError(t, err)
+	require.NoError(t, dbTx.Commit(ctx))
+
+	s, err := pgpoolstorage.NewPostgresPoolStorage(poolDBCfg)
+	require.NoError(t, err)
+
+	const chainID = 2576980377
+	p := setupPool(t, cfg, bc, s, st, chainID, ctx, eventLog)
+
+	tx := new(ethTypes.Transaction)
+	b, err := hex.DecodeHex("0xf86880843b9aca008252089400000000000000000000000000000000000000008080850133333355a03ee24709870c8dbc67884c9c8acb864c1aceaaa7332b9a3db0d7a5d7c68eb8e4a0302980b070f5e3ffca3dc27b07daf69d66ab27d4df648e0b3ed059cf23aa168d")
+	require.NoError(t, err)
+	tx.UnmarshalBinary(b) //nolint:gosec,errcheck
+
+	blockNumberMax := uint64(100)
+	conditions := &pool.TransactionConditions{
+		KnownAccounts: map[common.Address]map[common.Hash]common.Hash{
+			common.HexToAddress("0x1"): {common.HexToHash("0x1"): common.HexToHash("0x2")},
+		},
+		BlockNumberMax: &blockNumberMax,
+	}
+	err = p.AddTxWithConditions(ctx, *tx, ip, conditions)
+	require.NoError(t, err)
+
+	txs, err := s.GetNonWIPPendingTxs(ctx)
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	assert.Equal(t, tx.Hash(), txs[0].Hash())
+	assert.Equal(t, conditions, txs[0].Conditions)
+}
+
+func Test_TxStatusHistory(t *testing.T) {
+	initOrResetDB(t)
+
+	stateSqlDB, err := db.NewSQLDB(stateDBCfg)
+	require.NoError(t, err)
+	defer stateSqlDB.Close() //nolint:gosec,errcheck
+
+	eventStorage, err := nileventstorage.NewNilEventStorage()
+	if err != nil {
+		log.Fatal(err)
+	}
+	eventLog := event.NewEventLog(event.Config{}, eventStorage)
+
+	st := newState(stateSqlDB, eventLog)
+
+	genesisBlock := state.Block{
+		BlockNumber: 0,
+		BlockHash:   state.ZeroHash,
+		ParentHash:  state.ZeroHash,
+		ReceivedAt:  time.Now(),
+	}
+	ctx := context.Background()
+	dbTx, err := st.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	genesis.FirstBatchData.Timestamp = uint64(time.Now().Unix())
+	_, err = st.SetGenesis(ctx, genesisBlock, genesis, metrics.SynchronizerCallerLabel, dbTx)
+	require.NoError(t, err)
+	require.NoError(t, dbTx.Commit(ctx))
+
+	s, err := pgpoolstorage.NewPostgresPoolStorage(poolDBCfg)
+	require.NoError(t, err)
+
+	const chainID = 2576980377
+	p := setupPool(t, cfg, bc, s, st, chainID, ctx, eventLog)
+
+	tx := new(ethTypes.Transaction)
+	b, err := hex.DecodeHex("0xf86880843b9aca008252089400000000000000000000000000000000000000008080850133333355a03ee24709870c8dbc67884c9c8acb864c1aceaaa7332b9a3db0d7a5d7c68eb8e4a0302980b070f5e3ffca3dc27b07daf69d66ab27d4df648e0b3ed059cf23aa168d")
+	require.NoError(t, err)
+	tx.UnmarshalBinary(b) //nolint:gosec,errcheck
+
+	err = p.AddTx(ctx, *tx, ip)
+	require.NoError(t, err)
+
+	failedReason := "out of counters"
+	require.NoError(t, p.UpdateTxStatus(ctx, tx.Hash(), pool.TxStatusSelected, true, nil))
+	require.NoError(t, p.UpdateTxStatus(ctx, tx.Hash(), pool.TxStatusFailed, false, &failedReason))
+
+	// updating the status of an unknown tx doesn't record anything
+	unknownTxHash := common.HexToHash("0x1")
+	require.NoError(t, p.UpdateTxStatus(ctx, unknownTxHash, pool.TxStatusFailed, false, &failedReason))
+	history, err := p.GetTxStatusHistory(ctx, unknownTxHash)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	poolTx, err := p.GetTxByHash(ctx, tx.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, pool.TxStatusFailed, poolTx.Status)
+	assert.Equal(t, &failedReason, poolTx.FailedReason)
+
+	// the deletion is recorded and the history is kept after the tx is deleted from the pool
+	require.NoError(t, p.DeleteTransactionByHash(ctx, tx.Hash()))
+
+	history, err = p.GetTxStatusHistory(ctx, tx.Hash())
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+	assert.Equal(t, pool.TxStatusPending, history[0].Status)
+	assert.Nil(t, history[0].FailedReason)
+	assert.Equal(t, pool.TxStatusSelected, history[1].Status)
+	assert.Nil(t, history[1].FailedReason)
+	assert.Equal(t, pool.TxStatusFailed, history[2].Status)
+	assert.Equal(t, &failedReason, history[2].FailedReason)
+	assert.False(t, history[2].ChangedAt.Before(history[0].ChangedAt))
+	assert.Equal(t, pool.TxStatusRemoved, history[3].Status)
+	assert.Nil(t, history[3].FailedReason)
+
+	// the history is purged with the failed txs once the tx was removed before the given date
+	require.NoError(t, s.DeleteFailedTransactionsOlderThan(ctx, time.Now().Add(-time.Hour)))
+	history, err = p.GetTxStatusHistory(ctx, tx.Hash())
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+
+	require.NoError(t, s.DeleteFailedTransactionsOlderThan(ctx, time.Now().Add(time.Second)))
+	history, err = p.GetTxStatusHistory(ctx, tx.Hash())
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
 func Test_AddTx_OversizedData(t *testing.T) {
 	initOrResetDB(t)
 
@@ -981,6 +1125,44 @@ func Test_DeleteTransactionsByHashes(t *testing.T) {
 	err = poolSqlDB.QueryRow(ctx, "SELECT COUNT(*) FROM pool.transaction").Scan(&count)
 	require.NoError(t, err)
 	assert.Equal(t, 0, count)
+
+	// the deleted txs are recorded as removed in the status history
+	for _, hash := range []common.Hash{signedTx1.Hash(), signedTx2.Hash()} {
+		history, err := p.GetTxStatusHistory(ctx, hash)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, pool.TxStatusRemoved, history[1].Status)
+	}
+}
+
+func Test_GetPendingTxsByFrom(t *testing.T) {
+	ctx := context.Background()
+	initOrResetDB(t)
+
+	s, err := pgpoolstorage.NewPostgresPoolStorage(poolDBCfg)
+	require.NoError(t, err)
+
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(senderPrivateKey, "0x"))
+	require.NoError(t, err)
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	require.NoError(t, err)
+
+	// the txs are added out of order and returned sorted by nonce
+	hashes := []common.Hash{}
+	for _, nonce := range []uint64{3, 0, 1} {
+		tx := ethTypes.NewTransaction(nonce, common.Address{}, big.NewInt(10), gasLimit, gasPrice, []byte{})
+		signedTx, err := auth.Signer(auth.From, tx)
+		require.NoError(t, err)
+		require.NoError(t, s.AddTx(ctx, *pool.NewTransaction(*signedTx, ip, false)))
+		hashes = append(hashes, signedTx.Hash())
+	}
+
+	txs, err := s.GetPendingTxsByFrom(ctx, auth.From)
+	require.NoError(t, err)
+	require.Len(t, txs, 3)
+	assert.Equal(t, hashes[1], txs[0].Hash())
+	assert.Equal(t, hashes[2], txs[1].Hash())
+	assert.Equal(t, hashes[0], txs[2].Hash())
 }
 
 func Test_TryAddIncompatibleTxs(t *testing.T) {
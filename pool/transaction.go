@@ -17,6 +17,9 @@ const (
 	TxStatusSelected TxStatus = "selected"
 	// TxStatusFailed represents a tx that has been failed after processing
 	TxStatusFailed TxStatus = "failed"
+	// TxStatusRemoved represents a tx that has been deleted from the pool, it's
+	// only recorded in the status history
+	TxStatusRemoved TxStatus = "removed"
 )
 
 // TxStatus represents the state of a tx
@@ -35,6 +38,13 @@ type TxStatusUpdateInfo struct {
 	FailedReason *string
 }
 
+// TxStatusTransition represents a change of the status of a tx in the pool
+type TxStatusTransition struct {
+	Status       TxStatus
+	FailedReason *string
+	ChangedAt    time.Time
+}
+
 // Transaction represents a pool tx
 type Transaction struct {
 	types.Transaction
@@ -45,6 +55,7 @@ type Transaction struct {
 	IsWIP                 bool
 	IP                    string
 	FailedReason          *string
+	Conditions            *TransactionConditions
 }
 
 // NewTransaction creates a new transaction
@@ -237,6 +237,7 @@ func (d *dbManager) addTxToWorker(tx pool.Transaction) error {
 	if err != nil {
 		return err
 	}
+	txTracker.Conditions = tx.Conditions
 	replacedTx, dropReason := d.worker.AddTxTracker(d.ctx, txTracker)
 	if dropReason != nil {
 		failedReason := dropReason.Error()
@@ -681,6 +682,10 @@ func (d *dbManager) GetBalanceByStateRoot(ctx context.Context, address common.Ad
 	return d.state.GetBalanceByStateRoot(ctx, address, root)
 }
 
+func (d *dbManager) GetStorageAtByStateRoot(ctx context.Context, address common.Address, position *big.Int, root common.Hash) (*big.Int, error) {
+	return d.state.GetStorageAt(ctx, address, position, root)
+}
+
 func (d *dbManager) GetTransactionsByBatchNumber(ctx context.Context, batchNumber uint64) (txs []types.Transaction, effectivePercentages []uint8, err error) {
 	return d.state.GetTransactionsByBatchNumber(ctx, batchNumber, nil)
 }
@@ -346,6 +346,9 @@ func (f *finalizer) finalizeBatches(ctx context.Context) {
 						firstTxProcess = false
 						log.Info("reprocessing tx because of effective gas price calculation: %s", tx.Hash.Hex())
 						continue
+					} else if errors.Is(err, pool.ErrTxConditionsNotYetMet) {
+						log.Debugf("skipping tx %s in the wip L2 block, Err: %v", tx.Hash.Hex(), err)
+						break
 					} else {
 						log.Errorf("failed to process transaction in finalizeBatches, Err: %v", err)
 						break
@@ -446,6 +449,17 @@ func (f *finalizer) processTransaction(ctx context.Context, tx *TxTracker, first
 		metrics.ProcessingTime(time.Since(start))
 	}()
 
+	if tx != nil && tx.Conditions != nil {
+		err = f.checkTxConditions(ctx, tx)
+		if errors.Is(err, pool.ErrTxConditionsNotMet) || errors.Is(err, pool.ErrTxConditionsNotYetMet) {
+			f.handleTxConditionsNotMet(ctx, tx, err)
+			return nil, err
+		} else if err != nil {
+			log.Errorf("failed to check the conditions of the tx: %v", err)
+			return nil, err
+		}
+	}
+
 	executorBatchRequest := state.ProcessRequest{
 		BatchNumber:       f.wipBatch.batchNumber,
 		OldStateRoot:      f.wipBatch.stateRoot,
@@ -581,6 +595,15 @@ func (f *finalizer) processTransaction(ctx context.Context, tx *TxTracker, first
 
 // handleProcessTransactionResponse handles the response of transaction processing.
 func (f *finalizer) handleProcessTransactionResponse(ctx context.Context, tx *TxTracker, result *state.ProcessBatchResponse, oldStateRoot common.Hash) (errWg *sync.WaitGroup, err error) {
+	// The block number of a conditional tx is checked once it's known from the executor response
+	if tx.Conditions != nil {
+		err = tx.Conditions.CheckBlockNumber(result.BlockResponses[0].BlockNumber)
+		if err != nil {
+			f.handleTxConditionsNotMet(ctx, tx, err)
+			return nil, err
+		}
+	}
+
 	// Handle Transaction Error
 	errorCode := executor.RomErrorCode(result.BlockResponses[0].TransactionResponses[0].RomError)
 	if !state.IsStateRootChanged(errorCode) {
@@ -796,6 +819,47 @@ func (f *finalizer) updateWorkerAfterSuccessfulProcessing(ctx context.Context, t
 	metrics.WorkerProcessingTime(time.Since(start))
 }
 
+// checkTxConditions checks the conditions of a conditional transaction against the timestamp of the wip L2 block
+// and the storage of the current state root. The block number is checked after processing the transaction
+func (f *finalizer) checkTxConditions(ctx context.Context, tx *TxTracker) error {
+	err := tx.Conditions.CheckTimestamp(uint64(f.wipL2Block.timestamp.Unix()))
+	if err != nil {
+		return err
+	}
+
+	return tx.Conditions.CheckKnownAccounts(func(address common.Address, slot common.Hash) (common.Hash, error) {
+		value, err := f.dbManager.GetStorageAtByStateRoot(ctx, address, slot.Big(), f.wipBatch.stateRoot)
+		if errors.Is(err, state.ErrNotFound) {
+			return common.Hash{}, nil
+		} else if err != nil {
+			return common.Hash{}, err
+		}
+		return common.BigToHash(value), nil
+	})
+}
+
+// handleTxConditionsNotMet handles a conditional transaction whose conditions are not met. If the block is earlier than
+// the min block number or timestamp the tx is skipped until the next L2 block, otherwise the conditions can't be met
+// anymore and the tx is dropped, it's set as failed in the pool with the unmet condition as the failed reason
+func (f *finalizer) handleTxConditionsNotMet(ctx context.Context, tx *TxTracker, conditionsErr error) {
+	if errors.Is(conditionsErr, pool.ErrTxConditionsNotYetMet) {
+		log.Debugf("conditions of tx %s not met yet, skipping it until the next L2 block. Error: %v", tx.HashStr, conditionsErr)
+		f.worker.SkipTx(tx.Hash)
+		return
+	}
+
+	log.Infof("conditions of tx %s not met, dropping it. Error: %v", tx.HashStr, conditionsErr)
+	f.worker.DeleteTx(tx.Hash, tx.From)
+
+	failedReason := conditionsErr.Error()
+	err := f.dbManager.UpdateTxStatus(ctx, tx.Hash, pool.TxStatusFailed, false, &failedReason)
+	if err != nil {
+		log.Errorf("failed to update status to failed in the pool for tx: %s, err: %s", tx.Hash.String(), err)
+	} else {
+		metrics.TxProcessed(metrics.TxProcessedLabelFailed, 1)
+	}
+}
+
 // handleProcessTransactionError handles the error of a transaction
 func (f *finalizer) handleProcessTransactionError(ctx context.Context, result *state.ProcessBatchResponse, tx *TxTracker) *sync.WaitGroup {
 	txResponse := result.BlockResponses[0].TransactionResponses[0]
@@ -1239,6 +1239,130 @@ func TestFinalizer_handleTransactionError(t *testing.T) {
 	}
 }
 
+func TestFinalizer_checkTxConditions(t *testing.T) {
+	min, max := uint64(10), uint64(20)
+	slot := common.HexToHash("0x1")
+	conditions := &pool.TransactionConditions{
+		KnownAccounts: map[common.Address]map[common.Hash]common.Hash{
+			receiverAddr: {slot: common.HexToHash("0x2")},
+		},
+		TimestampMin: &min,
+		TimestampMax: &max,
+	}
+	testCases := []struct {
+		name          string
+		timestamp     int64
+		storageValue  *big.Int
+		storageErr    error
+		expectedErr   error
+		expectStorage bool
+	}{
+		{
+			name:          "Conditions met",
+			timestamp:     15,
+			storageValue:  big.NewInt(2),
+			expectStorage: true,
+		},
+		{
+			name:        "Timestamp lower than the min",
+			timestamp:   9,
+			expectedErr: pool.ErrTxConditionsNotYetMet,
+		},
+		{
+			name:        "Timestamp greater than the max",
+			timestamp:   21,
+			expectedErr: pool.ErrTxConditionsNotMet,
+		},
+		{
+			name:          "Storage slot holds another value",
+			timestamp:     15,
+			storageValue:  big.NewInt(3),
+			expectedErr:   pool.ErrTxConditionsNotMet,
+			expectStorage: true,
+		},
+		{
+			name:          "Error reading the storage",
+			timestamp:     15,
+			storageErr:    testErr,
+			expectedErr:   testErr,
+			expectStorage: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			f = setupFinalizer(true)
+			f.wipL2Block = &L2Block{timestamp: time.Unix(tc.timestamp, 0)}
+			txTracker := &TxTracker{Hash: txHash, From: senderAddr, Conditions: conditions}
+			if tc.expectStorage {
+				dbManagerMock.On("GetStorageAtByStateRoot", ctx, receiverAddr, slot.Big(), f.wipBatch.stateRoot).Return(tc.storageValue, tc.storageErr).Once()
+			}
+
+			// act
+			err := f.checkTxConditions(ctx, txTracker)
+
+			// assert
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			dbManagerMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFinalizer_handleTxConditionsNotMet(t *testing.T) {
+	// arrange
+	f = setupFinalizer(true)
+	max := uint64(10)
+	txTracker := &TxTracker{Hash: txHash, From: senderAddr, Conditions: &pool.TransactionConditions{BlockNumberMax: &max}}
+	failedReason := "transaction conditions not met: block number 11 is greater than the max block number 10"
+	workerMock.On("DeleteTx", txHash, senderAddr).Return().Once()
+	dbManagerMock.On("UpdateTxStatus", ctx, txHash, pool.TxStatusFailed, false, &failedReason).Return(nil).Once()
+	result := &state.ProcessBatchResponse{
+		BlockResponses: []*state.ProcessBlockResponse{
+			{
+				BlockNumber:          11,
+				TransactionResponses: []*state.ProcessTransactionResponse{{}},
+			},
+		},
+	}
+
+	// act
+	_, err := f.handleProcessTransactionResponse(ctx, txTracker, result, oldHash)
+
+	// assert
+	assert.ErrorIs(t, err, pool.ErrTxConditionsNotMet)
+	workerMock.AssertExpectations(t)
+	dbManagerMock.AssertExpectations(t)
+}
+
+func TestFinalizer_handleTxConditionsNotMetYet(t *testing.T) {
+	// arrange
+	f = setupFinalizer(true)
+	min := uint64(12)
+	txTracker := &TxTracker{Hash: txHash, From: senderAddr, Conditions: &pool.TransactionConditions{BlockNumberMin: &min}}
+	workerMock.On("SkipTx", txHash).Return().Once()
+	result := &state.ProcessBatchResponse{
+		BlockResponses: []*state.ProcessBlockResponse{
+			{
+				BlockNumber:          11,
+				TransactionResponses: []*state.ProcessTransactionResponse{{}},
+			},
+		},
+	}
+
+	// act
+	_, err := f.handleProcessTransactionResponse(ctx, txTracker, result, oldHash)
+
+	// assert
+	assert.ErrorIs(t, err, pool.ErrTxConditionsNotYetMet)
+	workerMock.AssertExpectations(t)
+	workerMock.AssertNotCalled(t, "DeleteTx", txHash, senderAddr)
+	dbManagerMock.AssertNotCalled(t, "UpdateTxStatus", ctx, txHash, pool.TxStatusFailed, false, mock.Anything)
+}
+
 /*func Test_processTransaction(t *testing.T) {
 	f = setupFinalizer(true)
 	gasUsed := uint64(100000)
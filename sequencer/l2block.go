@@ -405,6 +405,9 @@ func (f *finalizer) openNewWIPL2Block(ctx context.Context, prevTimestamp *time.T
 		f.finalizeBatch(ctx)
 	}
 
+	// The txs skipped in the previous L2 block can be included in the new one
+	f.worker.ResetSkippedTxs()
+
 	// Initialize wipL2Block to a new L2 block
 	newL2Block := &L2Block{}
 
@@ -625,6 +625,32 @@ func (_m *DbManagerMock) GetLatestVirtualBatchTimestamp(ctx context.Context, dbT
 	return r0, r1
 }
 
+// GetStorageAtByStateRoot provides a mock function with given fields: ctx, address, position, root
+func (_m *DbManagerMock) GetStorageAtByStateRoot(ctx context.Context, address common.Address, position *big.Int, root common.Hash) (*big.Int, error) {
+	ret := _m.Called(ctx, address, position, root)
+
+	var r0 *big.Int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, *big.Int, common.Hash) (*big.Int, error)); ok {
+		return rf(ctx, address, position, root)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, *big.Int, common.Hash) *big.Int); ok {
+		r0 = rf(ctx, address, position, root)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, *big.Int, common.Hash) error); ok {
+		r1 = rf(ctx, address, position, root)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStoredFlushID provides a mock function with given fields: ctx
 func (_m *DbManagerMock) GetStoredFlushID(ctx context.Context) (uint64, string, error) {
 	ret := _m.Called(ctx)
@@ -144,6 +144,16 @@ func (_m *WorkerMock) NewTxTracker(tx types.Transaction, counters state.ZKCounte
 	return r0, r1
 }
 
+// ResetSkippedTxs provides a mock function with given fields:
+func (_m *WorkerMock) ResetSkippedTxs() {
+	_m.Called()
+}
+
+// SkipTx provides a mock function with given fields: txHash
+func (_m *WorkerMock) SkipTx(txHash common.Hash) {
+	_m.Called(txHash)
+}
+
 // UpdateAfterSingleSuccessfulTxExecution provides a mock function with given fields: from, touchedAddresses
 func (_m *WorkerMock) UpdateAfterSingleSuccessfulTxExecution(from common.Address, touchedAddresses map[common.Address]*state.InfoReadWrite) []*TxTracker {
 	ret := _m.Called(from, touchedAddresses)
@@ -4,6 +4,7 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -30,6 +31,7 @@ type TxTracker struct {
 	L1GasPrice        uint64
 	L2GasPrice        uint64
 	FlushId           uint64
+	Conditions        *pool.TransactionConditions // Conditions the block must meet to include the tx, if it's a conditional tx
 }
 
 // newTxTracker creates and inti a TxTracker
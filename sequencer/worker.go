@@ -19,6 +19,7 @@ import (
 type Worker struct {
 	pool             map[string]*addrQueue
 	txSortedList     *txSortedList
+	skippedTxs       map[common.Hash]struct{}
 	workerMutex      sync.Mutex
 	state            stateInterface
 	batchConstraints state.BatchConstraintsCfg
@@ -29,6 +30,7 @@ func NewWorker(state stateInterface, constraints state.BatchConstraintsCfg) *Wor
 	w := Worker{
 		pool:             make(map[string]*addrQueue),
 		txSortedList:     newTxSortedList(),
+		skippedTxs:       make(map[common.Hash]struct{}),
 		state:            state,
 		batchConstraints: constraints,
 	}
@@ -285,6 +287,25 @@ func (w *Worker) DeletePendingTxToStore(txHash common.Hash, addr common.Address)
 	}
 }
 
+// SkipTx leaves a tx in the worker but excludes it from GetBestFittingTx until ResetSkippedTxs is called,
+// it's used for the txs that can't be included in the wip L2 block but can be included in a later one
+func (w *Worker) SkipTx(txHash common.Hash) {
+	w.workerMutex.Lock()
+	defer w.workerMutex.Unlock()
+
+	w.skippedTxs[txHash] = struct{}{}
+}
+
+// ResetSkippedTxs makes the skipped txs available again for GetBestFittingTx
+func (w *Worker) ResetSkippedTxs() {
+	w.workerMutex.Lock()
+	defer w.workerMutex.Unlock()
+
+	if len(w.skippedTxs) > 0 {
+		w.skippedTxs = make(map[common.Hash]struct{})
+	}
+}
+
 // GetBestFittingTx gets the most efficient tx that fits in the available batch resources
 func (w *Worker) GetBestFittingTx(resources state.BatchResources) (*TxTracker, error) {
 	w.workerMutex.Lock()
@@ -297,6 +318,7 @@ func (w *Worker) GetBestFittingTx(resources state.BatchResources) (*TxTracker, e
 	var (
 		tx         *TxTracker
 		foundMutex sync.RWMutex
+		notFitting bool
 	)
 
 	nGoRoutines := runtime.NumCPU()
@@ -318,9 +340,16 @@ func (w *Worker) GetBestFittingTx(resources state.BatchResources) (*TxTracker, e
 				foundMutex.RUnlock()
 
 				txCandidate := w.txSortedList.getByIndex(i)
+				if _, skipped := w.skippedTxs[txCandidate.Hash]; skipped {
+					continue
+				}
+
 				err := bresources.Sub(txCandidate.BatchResources)
 				if err != nil {
 					// We don't add this Tx
+					foundMutex.Lock()
+					notFitting = true
+					foundMutex.Unlock()
 					continue
 				}
 
@@ -340,6 +369,9 @@ func (w *Worker) GetBestFittingTx(resources state.BatchResources) (*TxTracker, e
 	if foundAt != -1 {
 		log.Debugf("[GetBestFittingTx] found tx(%s) at index(%d) with gasPrice(%d)", tx.Hash.String(), foundAt, tx.GasPrice)
 		return tx, nil
+	} else if !notFitting {
+		// All the txs are skipped
+		return nil, ErrTransactionsListEmpty
 	} else {
 		return nil, ErrNoFittingTransaction
 	}
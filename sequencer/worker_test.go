@@ -286,6 +286,67 @@ func TestWorkerGetBestTx(t *testing.T) {
 	}
 }
 
+func TestWorkerSkipTx(t *testing.T) {
+	var nilErr error
+
+	rc := state.BatchResources{
+		ZKCounters: state.ZKCounters{GasUsed: 10, UsedKeccakHashes: 10, UsedPoseidonHashes: 10, UsedPoseidonPaddings: 10, UsedMemAligns: 10, UsedArithmetics: 10, UsedBinaries: 10, UsedSteps: 10},
+		Bytes:      10,
+	}
+
+	stateMock := NewStateMock(t)
+	worker := initWorker(stateMock, rcMax)
+
+	ctx := context.Background()
+
+	stateMock.On("GetLastStateRoot", ctx, nil).Return(common.Hash{0}, nilErr)
+	for _, from := range []common.Address{{1}, {2}} {
+		stateMock.On("GetNonceByStateRoot", ctx, from, common.Hash{0}).Return(new(big.Int).SetInt64(1), nilErr)
+		stateMock.On("GetBalanceByStateRoot", ctx, from, common.Hash{0}).Return(new(big.Int).SetInt64(10), nilErr)
+	}
+
+	addTxsTC := []workerAddTxTestCase{
+		{
+			name: "Adding from:0x01, tx:0x01/gp:10", from: common.Address{1}, txHash: common.Hash{1}, nonce: 1, gasPrice: new(big.Int).SetInt64(10),
+			cost:      new(big.Int).SetInt64(5),
+			counters:  state.ZKCounters{GasUsed: 1, UsedKeccakHashes: 1, UsedPoseidonHashes: 1, UsedPoseidonPaddings: 1, UsedMemAligns: 1, UsedArithmetics: 1, UsedBinaries: 1, UsedSteps: 1},
+			usedBytes: 1,
+			expectedTxSortedList: []common.Hash{
+				{1},
+			},
+		},
+		{
+			name: "Adding from:0x02, tx:0x02/gp:12", from: common.Address{2}, txHash: common.Hash{2}, nonce: 1, gasPrice: new(big.Int).SetInt64(12),
+			cost:      new(big.Int).SetInt64(5),
+			counters:  state.ZKCounters{GasUsed: 1, UsedKeccakHashes: 1, UsedPoseidonHashes: 1, UsedPoseidonPaddings: 1, UsedMemAligns: 1, UsedArithmetics: 1, UsedBinaries: 1, UsedSteps: 1},
+			usedBytes: 1,
+			expectedTxSortedList: []common.Hash{
+				{2}, {1},
+			},
+		},
+	}
+
+	processWorkerAddTxTestCases(ctx, t, worker, addTxsTC)
+
+	// the skipped tx stays in the worker but it's not returned
+	worker.SkipTx(common.Hash{2})
+	tx, err := worker.GetBestFittingTx(rc)
+	assert.NoError(t, err)
+	assert.Equal(t, common.Hash{1}, tx.Hash)
+	assert.Equal(t, 2, worker.txSortedList.len())
+
+	// when all the txs are skipped there are no txs to process, but it's not because they don't fit
+	worker.SkipTx(common.Hash{1})
+	tx, err = worker.GetBestFittingTx(rc)
+	assert.Nil(t, tx)
+	assert.ErrorIs(t, err, ErrTransactionsListEmpty)
+
+	worker.ResetSkippedTxs()
+	tx, err = worker.GetBestFittingTx(rc)
+	assert.NoError(t, err)
+	assert.Equal(t, common.Hash{2}, tx.Hash)
+}
+
 func initWorker(stateMock *StateMock, rcMax state.BatchConstraintsCfg) *Worker {
 	worker := NewWorker(stateMock, rcMax)
 	return worker
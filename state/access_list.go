@@ -0,0 +1,143 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/fakevm"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/instrumentation"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+)
+
+const noopTracer = "noopTracer"
+
+// AccessListResult is the access list created for an unsigned transaction
+type AccessListResult struct {
+	AccessList types.AccessList
+	// GasUsed is the gas used by the execution of the transaction
+	GasUsed uint64
+	// Err is the error of the execution, if it failed or was reverted
+	Err error
+}
+
+// CreateAccessList executes the given unsigned transaction on top of the state
+// of the provided l2 block and creates the access list of the accounts and
+// storage slots it touches, collected from the steps of the execution trace
+// like the access list tracer of geth does.
+//
+// The sender, the recipient and the precompiled contracts are always warm, so
+// they are only part of the access list along with the storage slots touched
+// on them. The given access list, usually the one provided by the user along
+// with the transaction, is merged into the result.
+//
+// The gas used is the one of the execution of the transaction, the executor
+// runs unsigned transactions as legacy ones, so the access list isn't applied.
+func (s *State) CreateAccessList(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, providedAccessList types.AccessList, dbTx pgx.Tx) (*AccessListResult, error) {
+	tracer := noopTracer
+	result, err := s.DebugCall(ctx, tx, senderAddress, l2BlockNumber, TraceConfig{Tracer: &tracer}, dbTx)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := map[common.Address]struct{}{
+		senderAddress: {},
+	}
+	if tx.To() != nil {
+		excluded[*tx.To()] = struct{}{}
+	} else {
+		excluded[result.CreateAddress] = struct{}{}
+	}
+	for _, address := range fakevm.PrecompiledAddressesBerlin {
+		excluded[address] = struct{}{}
+	}
+
+	accessList := newAccessListBuilder(excluded)
+	for _, step := range result.FullTrace.Steps {
+		accessList.addStep(step)
+	}
+	for _, tuple := range providedAccessList {
+		accessList.add(tuple.Address)
+		for _, key := range tuple.StorageKeys {
+			accessList.addSlot(tuple.Address, key)
+		}
+	}
+
+	return &AccessListResult{
+		AccessList: accessList.accessList(),
+		GasUsed:    result.GasUsed,
+		Err:        result.Err,
+	}, nil
+}
+
+// accessListBuilder collects the accounts and storage slots of an access list,
+// the excluded accounts are skipped unless storage slots of them are added,
+// like the access list tracer of geth does
+type accessListBuilder struct {
+	excluded map[common.Address]struct{}
+	slots    map[common.Address]map[common.Hash]struct{}
+}
+
+func newAccessListBuilder(excluded map[common.Address]struct{}) *accessListBuilder {
+	return &accessListBuilder{
+		excluded: excluded,
+		slots:    map[common.Address]map[common.Hash]struct{}{},
+	}
+}
+
+func (b *accessListBuilder) add(address common.Address) {
+	if _, found := b.excluded[address]; found {
+		return
+	}
+	if _, found := b.slots[address]; !found {
+		b.slots[address] = map[common.Hash]struct{}{}
+	}
+}
+
+// addStep adds the account or the storage slot accessed by the opcode of the
+// given step, the stack of the step is the one before running the opcode
+func (b *accessListBuilder) addStep(step instrumentation.Step) {
+	stackLen := len(step.Stack)
+	switch step.OpCode {
+	case "SLOAD", "SSTORE":
+		if stackLen >= 1 {
+			b.addSlot(step.Contract.Address, common.BigToHash(step.Stack[stackLen-1]))
+		}
+	case "EXTCODECOPY", "EXTCODEHASH", "EXTCODESIZE", "BALANCE", "SELFDESTRUCT":
+		if stackLen >= 1 {
+			b.add(common.BigToAddress(step.Stack[stackLen-1]))
+		}
+	case "DELEGATECALL", "CALL", "STATICCALL", "CALLCODE":
+		if stackLen >= 2 { //nolint:gomnd
+			b.add(common.BigToAddress(step.Stack[stackLen-2])) //nolint:gomnd
+		}
+	}
+}
+
+func (b *accessListBuilder) addSlot(address common.Address, key common.Hash) {
+	if _, found := b.slots[address]; !found {
+		b.slots[address] = map[common.Hash]struct{}{}
+	}
+	b.slots[address][key] = struct{}{}
+}
+
+// accessList returns the collected access list sorted by address and key
+func (b *accessListBuilder) accessList() types.AccessList {
+	accessList := make(types.AccessList, 0, len(b.slots))
+	for address, keys := range b.slots {
+		tuple := types.AccessTuple{Address: address, StorageKeys: make([]common.Hash, 0, len(keys))}
+		for key := range keys {
+			tuple.StorageKeys = append(tuple.StorageKeys, key)
+		}
+		sort.Slice(tuple.StorageKeys, func(i, j int) bool {
+			return bytes.Compare(tuple.StorageKeys[i].Bytes(), tuple.StorageKeys[j].Bytes()) < 0
+		})
+		accessList = append(accessList, tuple)
+	}
+	sort.Slice(accessList, func(i, j int) bool {
+		return bytes.Compare(accessList[i].Address.Bytes(), accessList[j].Address.Bytes()) < 0
+	})
+	return accessList
+}
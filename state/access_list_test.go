@@ -0,0 +1,62 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/instrumentation"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessListBuilder(t *testing.T) {
+	sender := common.HexToAddress("0x1")
+	recipient := common.HexToAddress("0x2")
+	other := common.HexToAddress("0x3")
+	key1, key2 := common.HexToHash("0x1"), common.HexToHash("0x2")
+
+	b := newAccessListBuilder(map[common.Address]struct{}{sender: {}, recipient: {}})
+	b.add(sender)
+	b.add(recipient)
+	// the slots of the excluded accounts are part of the access list
+	b.addSlot(recipient, key2)
+	b.addSlot(recipient, key1)
+	b.add(other)
+	b.addSlot(other, key1)
+	b.addSlot(other, key1)
+
+	expected := types.AccessList{
+		{Address: recipient, StorageKeys: []common.Hash{key1, key2}},
+		{Address: other, StorageKeys: []common.Hash{key1}},
+	}
+	assert.Equal(t, expected, b.accessList())
+}
+
+func TestAccessListBuilderAddStep(t *testing.T) {
+	sender := common.HexToAddress("0x1")
+	contract := common.HexToAddress("0x2")
+	other := common.HexToAddress("0x3")
+	key := common.HexToHash("0x4")
+
+	steps := []instrumentation.Step{
+		{OpCode: "SLOAD", Contract: instrumentation.Contract{Address: contract}, Stack: []*big.Int{key.Big()}},
+		{OpCode: "BALANCE", Contract: instrumentation.Contract{Address: contract}, Stack: []*big.Int{sender.Big()}},
+		{OpCode: "EXTCODESIZE", Contract: instrumentation.Contract{Address: contract}, Stack: []*big.Int{other.Big()}},
+		// the zero address is a regular account when it's accessed
+		{OpCode: "CALL", Contract: instrumentation.Contract{Address: contract}, Stack: []*big.Int{big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), ZeroAddress.Big(), big.NewInt(21000)}},
+		{OpCode: "ADD", Contract: instrumentation.Contract{Address: contract}, Stack: []*big.Int{other.Big(), other.Big()}},
+	}
+
+	b := newAccessListBuilder(map[common.Address]struct{}{sender: {}, contract: {}})
+	for _, step := range steps {
+		b.addStep(step)
+	}
+
+	expected := types.AccessList{
+		{Address: ZeroAddress, StorageKeys: []common.Hash{}},
+		{Address: contract, StorageKeys: []common.Hash{key}},
+		{Address: other, StorageKeys: []common.Hash{}},
+	}
+	assert.Equal(t, expected, b.accessList())
+}
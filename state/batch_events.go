@@ -0,0 +1,199 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	newBatchesCheckInterval     = time.Second
+	newBatchEventBufferSize     = 500
+	maxBatchEventsPerStageCheck = 100
+)
+
+// BatchEventType is the stage of the batch lifecycle reported by a NewBatchEvent
+type BatchEventType string
+
+const (
+	// BatchEventClosed is triggered when a trusted batch is closed
+	BatchEventClosed BatchEventType = "closed"
+	// BatchEventVirtualized is triggered when a batch is sequenced on L1
+	BatchEventVirtualized BatchEventType = "virtualized"
+	// BatchEventVerified is triggered when a batch is verified on L1
+	BatchEventVerified BatchEventType = "verified"
+)
+
+// NewBatchEventHandler represent a func that will be called by the
+// state when a NewBatchEvent is triggered
+type NewBatchEventHandler func(e NewBatchEvent)
+
+// NewBatchEvent is a struct provided from the state to the NewBatchEventHandler
+// when a batch reaches a new stage of its lifecycle.
+type NewBatchEvent struct {
+	Type        BatchEventType
+	BatchNumber uint64
+	// TxHash is the hash of the L1 tx that virtualized or verified the batch,
+	// it is empty for closed batches
+	TxHash common.Hash
+	// BlockNumber is the number of the L1 block that virtualized or verified
+	// the batch, it is zero for closed batches
+	BlockNumber uint64
+}
+
+// StartToMonitorNewBatches starts 2 go routines that will monitor the
+// batches closed, virtualized and verified by the synchronizer and execute
+// the handlers registered to be executed when a batch reaches a new stage.
+// This is used by the RPC WebSocket batch subscriptions.
+func (s *State) StartToMonitorNewBatches() {
+	go InfiniteSafeRun(s.monitorNewBatches, "fail to monitor new batches: %v:", time.Second)
+	go InfiniteSafeRun(s.handleBatchEvents, "fail to handle batch events: %v", time.Second)
+}
+
+// RegisterNewBatchEventHandler add the provided handler to the list of handlers
+// that will be triggered when a new batch event is triggered
+func (s *State) RegisterNewBatchEventHandler(h NewBatchEventHandler) {
+	log.Info("new batch event handler registered")
+	s.newBatchEventHandlers = append(s.newBatchEventHandlers, h)
+}
+
+// batchStageMonitor tracks the last batch seen in a stage of the batch lifecycle
+type batchStageMonitor struct {
+	eventType BatchEventType
+	// lastBatchNumber returns the last batch number in the stage
+	lastBatchNumber func(ctx context.Context) (uint64, error)
+	// newEvent builds the event of the batch reaching the stage
+	newEvent func(ctx context.Context, batchNumber uint64) (NewBatchEvent, error)
+
+	lastBatchNumberSeen uint64
+}
+
+func (s *State) monitorNewBatches() {
+	ctx := context.Background()
+	monitors := []*batchStageMonitor{
+		{
+			eventType: BatchEventClosed,
+			lastBatchNumber: func(ctx context.Context) (uint64, error) {
+				return s.GetLastClosedBatchNumber(ctx, nil)
+			},
+			newEvent: func(ctx context.Context, batchNumber uint64) (NewBatchEvent, error) {
+				return NewBatchEvent{Type: BatchEventClosed, BatchNumber: batchNumber}, nil
+			},
+		},
+		{
+			eventType: BatchEventVirtualized,
+			lastBatchNumber: func(ctx context.Context) (uint64, error) {
+				return s.GetLastVirtualBatchNum(ctx, nil)
+			},
+			newEvent: func(ctx context.Context, batchNumber uint64) (NewBatchEvent, error) {
+				virtualBatch, err := s.GetVirtualBatch(ctx, batchNumber, nil)
+				if err != nil {
+					return NewBatchEvent{}, err
+				}
+				return NewBatchEvent{Type: BatchEventVirtualized, BatchNumber: batchNumber, TxHash: virtualBatch.TxHash, BlockNumber: virtualBatch.BlockNumber}, nil
+			},
+		},
+		{
+			eventType: BatchEventVerified,
+			lastBatchNumber: func(ctx context.Context) (uint64, error) {
+				verifiedBatch, err := s.GetLastVerifiedBatch(ctx, nil)
+				if errors.Is(err, ErrNotFound) {
+					return 0, nil
+				} else if err != nil {
+					return 0, err
+				}
+				return verifiedBatch.BatchNumber, nil
+			},
+			newEvent: func(ctx context.Context, batchNumber uint64) (NewBatchEvent, error) {
+				verifiedBatch, err := s.GetVerifiedBatch(ctx, batchNumber, nil)
+				if err != nil {
+					return NewBatchEvent{}, err
+				}
+				return NewBatchEvent{Type: BatchEventVerified, BatchNumber: batchNumber, TxHash: verifiedBatch.TxHash, BlockNumber: verifiedBatch.BlockNumber}, nil
+			},
+		},
+	}
+
+	for _, m := range monitors {
+		lastBatchNumber, err := m.lastBatchNumber(ctx)
+		if err != nil && !errors.Is(err, ErrStateNotSynchronized) && !errors.Is(err, ErrNotFound) {
+			log.Fatalf("failed to load the last %v batch: %v", m.eventType, err)
+		}
+		m.lastBatchNumberSeen = lastBatchNumber
+	}
+
+	for {
+		time.Sleep(newBatchesCheckInterval)
+		if len(s.newBatchEventHandlers) == 0 {
+			continue
+		}
+
+		for _, m := range monitors {
+			s.checkNewBatches(ctx, m)
+		}
+	}
+}
+
+// checkNewBatches triggers the events of the batches that reached the stage of
+// the monitor since the last check
+func (s *State) checkNewBatches(ctx context.Context, m *batchStageMonitor) {
+	lastBatchNumber, err := m.lastBatchNumber(ctx)
+	if errors.Is(err, ErrStateNotSynchronized) || errors.Is(err, ErrNotFound) {
+		return
+	} else if err != nil {
+		log.Errorf("failed to get the last %v batch while monitoring new batches: %v", m.eventType, err)
+		return
+	}
+
+	// the batches were reorganized, the events are triggered again when
+	// the batches reach the stage after the reorg
+	if lastBatchNumber < m.lastBatchNumberSeen {
+		log.Infof("[monitorNewBatches] last %v batch moved back from %v to %v", m.eventType, m.lastBatchNumberSeen, lastBatchNumber)
+		m.lastBatchNumberSeen = lastBatchNumber
+		return
+	}
+
+	toBatchNumber := lastBatchNumber
+	if toBatchNumber-m.lastBatchNumberSeen > maxBatchEventsPerStageCheck {
+		toBatchNumber = m.lastBatchNumberSeen + maxBatchEventsPerStageCheck
+	}
+
+	for bn := m.lastBatchNumberSeen + 1; bn <= toBatchNumber; bn++ {
+		event, err := m.newEvent(ctx, bn)
+		if err != nil {
+			log.Errorf("failed to get %v batch %v while monitoring new batches: %v", m.eventType, bn, err)
+			return
+		}
+
+		log.Debugf("[monitorNewBatches] sending NewBatchEvent for %v batch %v", m.eventType, bn)
+		s.newBatchEvents <- event
+		m.lastBatchNumberSeen = bn
+	}
+}
+
+func (s *State) handleBatchEvents() {
+	for newBatchEvent := range s.newBatchEvents {
+		if len(s.newBatchEventHandlers) == 0 {
+			continue
+		}
+
+		wg := sync.WaitGroup{}
+		for _, handler := range s.newBatchEventHandlers {
+			wg.Add(1)
+			go func(h NewBatchEventHandler, e NewBatchEvent) {
+				defer func() {
+					wg.Done()
+					if r := recover(); r != nil {
+						log.Errorf("failed and recovered in NewBatchEventHandler: %v", r)
+					}
+				}()
+				h(e)
+			}(handler, newBatchEvent)
+		}
+		wg.Wait()
+	}
+}
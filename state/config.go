@@ -1,6 +1,9 @@
 package state
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/0xPolygonHermez/zkevm-node/config/types"
 	"github.com/0xPolygonHermez/zkevm-node/db"
 )
@@ -77,3 +80,27 @@ func (c BatchConstraintsCfg) IsWithinConstraints(counters ZKCounters) bool {
 		counters.UsedBinaries <= c.MaxBinaries &&
 		counters.UsedSteps <= c.MaxSteps
 }
+
+// CheckConstraints returns an error describing every counter exceeding the
+// batch constraints and by how much, or nil if the counters are within them
+func (c BatchConstraintsCfg) CheckConstraints(counters ZKCounters) error {
+	var exceeded []string
+	check := func(name string, used, limit uint64) {
+		if used > limit {
+			exceeded = append(exceeded, fmt.Sprintf("%s %d exceeds the limit %d by %d", name, used, limit, used-limit))
+		}
+	}
+	check("gas", counters.GasUsed, c.MaxCumulativeGasUsed)
+	check("keccak hashes", uint64(counters.UsedKeccakHashes), uint64(c.MaxKeccakHashes))
+	check("poseidon hashes", uint64(counters.UsedPoseidonHashes), uint64(c.MaxPoseidonHashes))
+	check("poseidon paddings", uint64(counters.UsedPoseidonPaddings), uint64(c.MaxPoseidonPaddings))
+	check("mem aligns", uint64(counters.UsedMemAligns), uint64(c.MaxMemAligns))
+	check("arithmetics", uint64(counters.UsedArithmetics), uint64(c.MaxArithmetics))
+	check("binaries", uint64(counters.UsedBinaries), uint64(c.MaxBinaries))
+	check("steps", uint64(counters.UsedSteps), uint64(c.MaxSteps))
+
+	if len(exceeded) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrBatchConstraintsExceeded, strings.Join(exceeded, ", "))
+}
@@ -69,6 +69,21 @@ var (
 	// ErrMaxNativeBlockHashBlockRangeLimitExceeded returned when the range between block number range
 	// to filter native block hashes is bigger than the configured limit
 	ErrMaxNativeBlockHashBlockRangeLimitExceeded = errors.New("native block hashes are limited to a %v block range")
+	// ErrMaxTraceFilterBlockRangeLimitExceeded returned when the range between block number range
+	// to filter traces is bigger than the configured limit
+	ErrMaxTraceFilterBlockRangeLimitExceeded = errors.New("traces are limited to a %v block range")
+	// ErrMaxTraceFilterTxsLimitExceeded returned when the number of transactions
+	// to trace to filter traces is bigger than the configured limit
+	ErrMaxTraceFilterTxsLimitExceeded = errors.New("traces are limited to %v transactions, reduce the block range")
+	// ErrStateOverrideStateAndStateDiff indicates an account of a state override has
+	// both the state and the state diff set
+	ErrStateOverrideStateAndStateDiff = errors.New("account has both state and stateDiff overrides")
+	// ErrBatchConstraintsExceeded indicates the zk counters used exceed the
+	// limits of the batch constraints configured in the node
+	ErrBatchConstraintsExceeded = errors.New("out of counters at node level")
+	// ErrInvalidL1InfoTreeRootIndex indicates the root index of a L1InfoTree
+	// proof is lower than the index of the proven leaf
+	ErrInvalidL1InfoTreeRootIndex = errors.New("the root index must be greater than or equal to the leaf index")
 
 	zkCounterErrPrefix = "ZKCounter: "
 )
@@ -51,6 +51,11 @@ type storage interface {
 	GetProcessingContext(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*ProcessingContext, error)
 	GetEncodedTransactionsByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (encodedTxs []string, effectivePercentages []uint8, err error)
 	GetTransactionsByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (txs []types.Transaction, effectivePercentages []uint8, err error)
+	GetL2TxHashesInRange(ctx context.Context, fromBlock, toBlock uint64, addresses []common.Address, dbTx pgx.Tx) ([]common.Hash, error)
+	IndexTxAddressesBefore(ctx context.Context, blockCount uint64, dbTx pgx.Tx) (uint64, error)
+	GetL2TxHashesByAddressBefore(ctx context.Context, address common.Address, blockNumber uint64, limit uint64, dbTx pgx.Tx) ([]common.Hash, bool, error)
+	GetL2TxHashesByAddressAfter(ctx context.Context, address common.Address, blockNumber uint64, limit uint64, dbTx pgx.Tx) ([]common.Hash, bool, error)
+	GetTxHashByContractAddress(ctx context.Context, address common.Address, dbTx pgx.Tx) (common.Hash, error)
 	GetTxsHashesByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (encoded []common.Hash, err error)
 	AddVirtualBatch(ctx context.Context, virtualBatch *VirtualBatch, dbTx pgx.Tx) error
 	GetVirtualBatch(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*VirtualBatch, error)
@@ -83,6 +88,8 @@ type storage interface {
 	GetBlockNumVirtualBatchByBatchNum(ctx context.Context, batchNum uint64, dbTx pgx.Tx) (uint64, error)
 	GetL2BlockByHash(ctx context.Context, hash common.Hash, dbTx pgx.Tx) (*L2Block, error)
 	GetTxsByBlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) ([]*types.Transaction, error)
+	GetEffectiveGasPricesByL2BlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) ([]L2TxEffectiveGasPrice, error)
+	GetReceiptsByL2BlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) ([]*types.Receipt, error)
 	GetTxsByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]*types.Transaction, error)
 	GetL2BlockHeaderByHash(ctx context.Context, hash common.Hash, dbTx pgx.Tx) (*L2Header, error)
 	GetL2BlockHeaderByNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (*L2Header, error)
@@ -90,6 +97,7 @@ type storage interface {
 	IsL2BlockConsolidated(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (bool, error)
 	IsL2BlockVirtualized(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (bool, error)
 	GetLogs(ctx context.Context, fromBlock uint64, toBlock uint64, addresses []common.Address, topics [][]common.Hash, blockHash *common.Hash, since *time.Time, dbTx pgx.Tx) ([]*types.Log, error)
+	GetLogsPage(ctx context.Context, fromBlock uint64, fromLogIndex uint64, toBlock uint64, addresses []common.Address, topics [][]common.Hash, limit uint64, dbTx pgx.Tx) ([]*types.Log, error)
 	GetSyncingInfo(ctx context.Context, dbTx pgx.Tx) (SyncingInfo, error)
 	AddReceipt(ctx context.Context, receipt *types.Receipt, dbTx pgx.Tx) error
 	AddLog(ctx context.Context, l *types.Log, dbTx pgx.Tx) error
@@ -131,6 +139,9 @@ type storage interface {
 	AddL1InfoRootToExitRoot(ctx context.Context, exitRoot *L1InfoTreeExitRootStorageEntry, dbTx pgx.Tx) error
 	GetAllL1InfoRootEntries(ctx context.Context, dbTx pgx.Tx) ([]L1InfoTreeExitRootStorageEntry, error)
 	GetLatestL1InfoRoot(ctx context.Context, maxBlockNumber uint64) (L1InfoTreeExitRootStorageEntry, error)
+	GetL1InfoRootLeafByIndex(ctx context.Context, l1InfoTreeIndex uint32, dbTx pgx.Tx) (L1InfoTreeExitRootStorageEntry, error)
+	GetL1InfoRootLeafByGlobalExitRoot(ctx context.Context, globalExitRoot common.Hash, dbTx pgx.Tx) (L1InfoTreeExitRootStorageEntry, error)
+	GetL1InfoRootLeavesUntilIndex(ctx context.Context, l1InfoTreeIndex uint32, dbTx pgx.Tx) ([]L1InfoTreeExitRootStorageEntry, error)
 	UpdateForkIDIntervalsInMemory(intervals []ForkIDInterval)
 	AddForkIDInterval(ctx context.Context, newForkID ForkIDInterval, dbTx pgx.Tx) error
 	GetForkIDByBlockNumber(blockNumber uint64) uint64
@@ -3,6 +3,7 @@ package state
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/0xPolygonHermez/zkevm-node/l1infotree"
 	"github.com/0xPolygonHermez/zkevm-node/log"
@@ -57,3 +58,63 @@ func (s *State) AddL1InfoTreeLeaf(ctx context.Context, l1InfoTreeLeaf *L1InfoTre
 	}
 	return &entry, nil
 }
+
+// L1InfoTreeProof is the merkle proof of a leaf of the L1InfoTree against the
+// root of the tree after adding the leaf with the root index
+type L1InfoTreeProof struct {
+	Leaf      L1InfoTreeExitRootStorageEntry
+	Root      common.Hash
+	RootIndex uint32
+	Siblings  [][32]byte
+}
+
+// GetL1InfoTreeProof computes the merkle proof of the leaf with the given
+// index from the leaves stored by the synchronizer. The proof is computed
+// against the root of the tree after adding the leaf with the root index,
+// or the current root when the root index is not provided.
+func (s *State) GetL1InfoTreeProof(ctx context.Context, index uint32, rootIndex *uint32, dbTx pgx.Tx) (*L1InfoTreeProof, error) {
+	leaf, err := s.GetL1InfoRootLeafByIndex(ctx, index, dbTx)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastIndex uint32
+	if rootIndex != nil {
+		lastIndex = *rootIndex
+	} else {
+		lastIndex, err = s.GetLatestIndex(ctx, dbTx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if lastIndex < index {
+		return nil, ErrInvalidL1InfoTreeRootIndex
+	}
+
+	entries, err := s.GetL1InfoRootLeavesUntilIndex(ctx, lastIndex, dbTx)
+	if err != nil {
+		return nil, err
+	}
+	if uint32(len(entries)) != lastIndex+1 {
+		return nil, ErrNotFound
+	}
+
+	leaves := make([][32]byte, 0, len(entries))
+	for _, entry := range entries {
+		leaves = append(leaves, entry.Hash())
+	}
+	siblings, root, err := s.l1InfoTree.ComputeMerkleProof(index, leaves)
+	if err != nil {
+		return nil, err
+	}
+	if root != entries[lastIndex].L1InfoTreeRoot {
+		return nil, fmt.Errorf("computed L1InfoTree root %s doesn't match the stored root %s for index %d", root.String(), entries[lastIndex].L1InfoTreeRoot.String(), lastIndex)
+	}
+
+	return &L1InfoTreeProof{
+		Leaf:      leaf,
+		Root:      root,
+		RootIndex: lastIndex,
+		Siblings:  siblings,
+	}, nil
+}
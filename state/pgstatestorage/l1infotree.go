@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/jackc/pgx/v4"
 )
 
@@ -27,8 +28,23 @@ func (p *PostgresStorage) GetAllL1InfoRootEntries(ctx context.Context, dbTx pgx.
 		WHERE l1_info_tree_index IS NOT NULL
 		ORDER BY l1_info_tree_index`
 
+	return p.queryL1InfoRootEntries(ctx, getL1InfoRootSQL, dbTx)
+}
+
+// GetL1InfoRootLeavesUntilIndex returns the entries of the L1InfoTree from the
+// first one to the one with the given index, both included
+func (p *PostgresStorage) GetL1InfoRootLeavesUntilIndex(ctx context.Context, l1InfoTreeIndex uint32, dbTx pgx.Tx) ([]state.L1InfoTreeExitRootStorageEntry, error) {
+	const getL1InfoRootSQL = `SELECT block_num, timestamp, mainnet_exit_root, rollup_exit_root, global_exit_root, prev_block_hash, l1_info_root, l1_info_tree_index
+		FROM state.exit_root 
+		WHERE l1_info_tree_index IS NOT NULL AND l1_info_tree_index <= $1
+		ORDER BY l1_info_tree_index`
+
+	return p.queryL1InfoRootEntries(ctx, getL1InfoRootSQL, dbTx, l1InfoTreeIndex)
+}
+
+func (p *PostgresStorage) queryL1InfoRootEntries(ctx context.Context, sql string, dbTx pgx.Tx, args ...interface{}) ([]state.L1InfoTreeExitRootStorageEntry, error) {
 	e := p.getExecQuerier(dbTx)
-	rows, err := e.Query(ctx, getL1InfoRootSQL)
+	rows, err := e.Query(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -47,6 +63,39 @@ func (p *PostgresStorage) GetAllL1InfoRootEntries(ctx context.Context, dbTx pgx.
 	return entries, nil
 }
 
+// GetL1InfoRootLeafByIndex returns the entry of the L1InfoTree with the given index
+func (p *PostgresStorage) GetL1InfoRootLeafByIndex(ctx context.Context, l1InfoTreeIndex uint32, dbTx pgx.Tx) (state.L1InfoTreeExitRootStorageEntry, error) {
+	const getL1InfoRootSQL = `SELECT block_num, timestamp, mainnet_exit_root, rollup_exit_root, global_exit_root, prev_block_hash, l1_info_root, l1_info_tree_index
+		FROM state.exit_root 
+		WHERE l1_info_tree_index = $1`
+
+	return p.queryL1InfoRootEntry(ctx, getL1InfoRootSQL, dbTx, l1InfoTreeIndex)
+}
+
+// GetL1InfoRootLeafByGlobalExitRoot returns the first entry of the L1InfoTree
+// with the given global exit root
+func (p *PostgresStorage) GetL1InfoRootLeafByGlobalExitRoot(ctx context.Context, globalExitRoot common.Hash, dbTx pgx.Tx) (state.L1InfoTreeExitRootStorageEntry, error) {
+	const getL1InfoRootSQL = `SELECT block_num, timestamp, mainnet_exit_root, rollup_exit_root, global_exit_root, prev_block_hash, l1_info_root, l1_info_tree_index
+		FROM state.exit_root 
+		WHERE l1_info_tree_index IS NOT NULL AND global_exit_root = $1
+		ORDER BY l1_info_tree_index
+		LIMIT 1`
+
+	return p.queryL1InfoRootEntry(ctx, getL1InfoRootSQL, dbTx, globalExitRoot.Bytes())
+}
+
+func (p *PostgresStorage) queryL1InfoRootEntry(ctx context.Context, sql string, dbTx pgx.Tx, args ...interface{}) (state.L1InfoTreeExitRootStorageEntry, error) {
+	entry := state.L1InfoTreeExitRootStorageEntry{}
+
+	e := p.getExecQuerier(dbTx)
+	err := e.QueryRow(ctx, sql, args...).Scan(&entry.BlockNumber, &entry.Timestamp, &entry.MainnetExitRoot, &entry.RollupExitRoot, &entry.GlobalExitRoot.GlobalExitRoot,
+		&entry.PreviousBlockHash, &entry.L1InfoTreeRoot, &entry.L1InfoTreeIndex)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entry, state.ErrNotFound
+	}
+	return entry, err
+}
+
 // GetLatestL1InfoRoot is used to get the latest L1InfoRoot
 func (p *PostgresStorage) GetLatestL1InfoRoot(ctx context.Context, maxBlockNumber uint64) (state.L1InfoTreeExitRootStorageEntry, error) {
 	const getL1InfoRootSQL = `SELECT block_num, timestamp, mainnet_exit_root, rollup_exit_root, global_exit_root, prev_block_hash, l1_info_root, l1_info_tree_index
@@ -11,6 +11,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/jackc/pgx/v4"
 )
 
@@ -227,6 +228,11 @@ func (p *PostgresStorage) AddL2Block(ctx context.Context, batchNumber uint64, l2
 		if err != nil {
 			return err
 		}
+
+		err = addTxAddresses(ctx, e, tx.Hash().String(), tx, l2Block.Number().Uint64())
+		if err != nil {
+			return err
+		}
 	}
 
 	for _, receipt := range receipts {
@@ -246,6 +252,103 @@ func (p *PostgresStorage) AddL2Block(ctx context.Context, batchNumber uint64, l2
 	return nil
 }
 
+// addTxAddresses indexes the tx by its sender and its recipient, or the
+// contract it creates, so the txs of an address can be found without tracing
+// every tx. If the sender can't be recovered, the tx is indexed only by its
+// recipient.
+func addTxAddresses(ctx context.Context, e ExecQuerier, txHash string, tx *types.Transaction, l2BlockNumber uint64) error {
+	const addTransactionAddressSQL = "INSERT INTO state.transaction_address (tx_hash, l2_block_num, address) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING"
+
+	addresses := make([]common.Address, 0, 2) //nolint:gomnd
+	sender, err := state.GetSender(*tx)
+	if err != nil {
+		log.Warnf("error getting sender of tx %v to index it by address: %v", txHash, err)
+	} else {
+		addresses = append(addresses, sender)
+	}
+	if tx.To() != nil {
+		addresses = append(addresses, *tx.To())
+	} else if err == nil {
+		addresses = append(addresses, crypto.CreateAddress(sender, tx.Nonce()))
+	}
+	for _, address := range addresses {
+		if _, err := e.Exec(ctx, addTransactionAddressSQL, txHash, l2BlockNumber, address.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IndexTxAddressesBefore indexes by address the txs of the given number of l2
+// blocks before the first indexed block, recovering their senders from the
+// encoded txs, and returns the new first indexed block. The first indexed
+// block is locked until the db transaction ends, so the same blocks aren't
+// indexed twice at the same time.
+func (p *PostgresStorage) IndexTxAddressesBefore(ctx context.Context, blockCount uint64, dbTx pgx.Tx) (uint64, error) {
+	const getTxAddressIndexStartSQL = "SELECT l2_block_num FROM state.transaction_address_start FOR UPDATE"
+	const getTxsSQL = "SELECT hash, encoded, l2_block_num FROM state.transaction WHERE l2_block_num >= $1 AND l2_block_num < $2"
+	const updateTxAddressIndexStartSQL = "UPDATE state.transaction_address_start SET l2_block_num = $1"
+
+	e := p.getExecQuerier(dbTx)
+	var indexStart uint64
+	err := e.QueryRow(ctx, getTxAddressIndexStartSQL).Scan(&indexStart)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, state.ErrNotFound
+	} else if err != nil {
+		return 0, err
+	}
+	if indexStart == 0 {
+		return 0, nil
+	}
+
+	var fromBlock uint64
+	if indexStart > blockCount {
+		fromBlock = indexStart - blockCount
+	}
+
+	type storedTx struct {
+		hash          string
+		encoded       string
+		l2BlockNumber uint64
+	}
+	rows, err := e.Query(ctx, getTxsSQL, fromBlock, indexStart)
+	if err != nil {
+		return 0, err
+	}
+	storedTxs := []storedTx{}
+	for rows.Next() {
+		var stored storedTx
+		if err := rows.Scan(&stored.hash, &stored.encoded, &stored.l2BlockNumber); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		storedTxs = append(storedTxs, stored)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, stored := range storedTxs {
+		binary, err := hex.DecodeHex(stored.encoded)
+		if err != nil {
+			return 0, err
+		}
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(binary); err != nil {
+			return 0, err
+		}
+		if err := addTxAddresses(ctx, e, stored.hash, tx, stored.l2BlockNumber); err != nil {
+			return 0, err
+		}
+	}
+
+	if _, err := e.Exec(ctx, updateTxAddressIndexStartSQL, fromBlock); err != nil {
+		return 0, err
+	}
+	return fromBlock, nil
+}
+
 // GetLastVirtualizedL2BlockNumber gets the last l2 block virtualized
 func (p *PostgresStorage) GetLastVirtualizedL2BlockNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
 	var lastVirtualizedBlockNumber uint64
@@ -181,23 +181,7 @@ func (p *PostgresStorage) GetLogs(ctx context.Context, fromBlock uint64, toBlock
 		queryFilterByBlockNumbers +
 		queryOrder
 
-	args := []interface{}{}
-
-	// address filter
-	if len(addresses) > 0 {
-		args = append(args, p.addressesToHex(addresses))
-	} else {
-		args = append(args, nil)
-	}
-
-	// topic filters
-	for i := 0; i < maxTopics; i++ {
-		if len(topics) > i && len(topics[i]) > 0 {
-			args = append(args, p.hashesToHex(topics[i]))
-		} else {
-			args = append(args, nil)
-		}
-	}
+	args := p.logFilterArgs(addresses, topics)
 
 	// since filter
 	args = append(args, since)
@@ -244,6 +228,67 @@ func (p *PostgresStorage) GetLogs(ctx context.Context, fromBlock uint64, toBlock
 	return scanLogs(rows)
 }
 
+// GetLogsPage returns up to limit logs that match the addresses and topics
+// filters, starting at the log with the provided log index of the from block
+// up to the to block, ordered by block number and log index.
+//
+// The logs are read page by page following the block number index, so the
+// MaxLogsCount and MaxLogsBlockRange limits are not applied.
+func (p *PostgresStorage) GetLogsPage(ctx context.Context, fromBlock uint64, fromLogIndex uint64, toBlock uint64, addresses []common.Address, topics [][]common.Hash, limit uint64, dbTx pgx.Tx) ([]*types.Log, error) {
+	const query = `
+      SELECT t.l2_block_num, b.block_hash, l.tx_hash, l.log_index, l.address, l.data, l.topic0, l.topic1, l.topic2, l.topic3
+        FROM state.log l
+       INNER JOIN state.transaction t ON t.hash = l.tx_hash
+       INNER JOIN state.l2block b ON b.block_num = t.l2_block_num
+       WHERE (l.address = any($1) OR $1 IS NULL)
+         AND (l.topic0 = any($2) OR $2 IS NULL)
+         AND (l.topic1 = any($3) OR $3 IS NULL)
+         AND (l.topic2 = any($4) OR $4 IS NULL)
+         AND (l.topic3 = any($5) OR $5 IS NULL)
+         AND b.block_num BETWEEN $6 AND $7
+         AND (b.block_num > $6 OR l.log_index >= $8)
+       ORDER BY b.block_num ASC, l.log_index ASC
+       LIMIT $9`
+
+	if toBlock < fromBlock {
+		return nil, state.ErrInvalidBlockRange
+	}
+
+	args := p.logFilterArgs(addresses, topics)
+	args = append(args, fromBlock, toBlock, fromLogIndex, limit)
+
+	q := p.getExecQuerier(dbTx)
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanLogs(rows)
+}
+
+// logFilterArgs returns the query args of the addresses and topics filters of
+// the logs, nil when the filter is not set
+func (p *PostgresStorage) logFilterArgs(addresses []common.Address, topics [][]common.Hash) []interface{} {
+	args := []interface{}{}
+
+	// address filter
+	if len(addresses) > 0 {
+		args = append(args, p.addressesToHex(addresses))
+	} else {
+		args = append(args, nil)
+	}
+
+	// topic filters
+	for i := 0; i < maxTopics; i++ {
+		if len(topics) > i && len(topics[i]) > 0 {
+			args = append(args, p.hashesToHex(topics[i]))
+		} else {
+			args = append(args, nil)
+		}
+	}
+
+	return args
+}
+
 func (p *PostgresStorage) addressesToHex(addresses []common.Address) []string {
 	converted := make([]string, 0, len(addresses))
 
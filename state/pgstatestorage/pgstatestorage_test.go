@@ -24,6 +24,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/test/testutils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/stretchr/testify/assert"
@@ -207,6 +208,173 @@ func TestGetBatchByL2BlockNumber(t *testing.T) {
 	require.NoError(t, dbTx.Commit(ctx))
 }
 
+func TestGetEffectiveGasPricesByL2BlockNumber(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	err = testState.AddBlock(ctx, block, dbTx)
+	assert.NoError(t, err)
+
+	batchNumber := uint64(1)
+	_, err = testState.Exec(ctx, "INSERT INTO state.batch (batch_num) VALUES ($1)", batchNumber)
+	assert.NoError(t, err)
+
+	time := time.Now()
+	blockNumber := big.NewInt(1)
+
+	transactions := []*types.Transaction{
+		types.NewTx(&types.LegacyTx{Nonce: 0, Value: new(big.Int), Gas: 21000, GasPrice: big.NewInt(10)}),
+		types.NewTx(&types.LegacyTx{Nonce: 1, Value: new(big.Int), Gas: 22000, GasPrice: big.NewInt(20)}),
+	}
+	effectiveGasPrices := []*big.Int{big.NewInt(5), nil}
+
+	receipts := []*types.Receipt{}
+	for i, tx := range transactions {
+		receipts = append(receipts, &types.Receipt{
+			Type:              uint8(tx.Type()),
+			PostState:         state.ZeroHash.Bytes(),
+			CumulativeGasUsed: 0,
+			EffectiveGasPrice: effectiveGasPrices[i],
+			BlockNumber:       blockNumber,
+			GasUsed:           tx.Gas(),
+			TxHash:            tx.Hash(),
+			TransactionIndex:  uint(i),
+			Status:            types.ReceiptStatusSuccessful,
+		})
+	}
+
+	header := state.NewL2Header(&types.Header{
+		Number:     big.NewInt(1),
+		ParentHash: state.ZeroHash,
+		Coinbase:   state.ZeroAddress,
+		Root:       state.ZeroHash,
+		GasUsed:    43000,
+		GasLimit:   100000,
+		Time:       uint64(time.Unix()),
+	})
+
+	l2Block := state.NewL2Block(header, transactions, []*state.L2Header{}, receipts, &trie.StackTrie{})
+	for _, receipt := range receipts {
+		receipt.BlockHash = l2Block.Hash()
+	}
+
+	storeTxsEGPData := []state.StoreTxEGPData{}
+	for range transactions {
+		storeTxsEGPData = append(storeTxsEGPData, state.StoreTxEGPData{EGPLog: nil, EffectivePercentage: state.MaxEffectivePercentage})
+	}
+
+	err = pgStateStorage.AddL2Block(ctx, batchNumber, l2Block, receipts, storeTxsEGPData, dbTx)
+	require.NoError(t, err)
+
+	gasPrices, err := pgStateStorage.GetEffectiveGasPricesByL2BlockNumber(ctx, l2Block.Number().Uint64(), dbTx)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(gasPrices))
+	assert.Equal(t, transactions[0].Hash(), gasPrices[0].TxHash)
+	assert.Equal(t, uint64(21000), gasPrices[0].GasUsed)
+	assert.Equal(t, big.NewInt(5), gasPrices[0].EffectiveGasPrice)
+	// the tx stored without effective gas price returns its gas price
+	assert.Equal(t, transactions[1].Hash(), gasPrices[1].TxHash)
+	assert.Equal(t, uint64(22000), gasPrices[1].GasUsed)
+	assert.Equal(t, big.NewInt(20), gasPrices[1].EffectiveGasPrice)
+
+	gasPrices, err = pgStateStorage.GetEffectiveGasPricesByL2BlockNumber(ctx, l2Block.Number().Uint64()+1, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(gasPrices))
+	require.NoError(t, dbTx.Commit(ctx))
+}
+
+func TestGetReceiptsByL2BlockNumber(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	err = testState.AddBlock(ctx, block, dbTx)
+	assert.NoError(t, err)
+
+	batchNumber := uint64(1)
+	_, err = testState.Exec(ctx, "INSERT INTO state.batch (batch_num) VALUES ($1)", batchNumber)
+	assert.NoError(t, err)
+
+	time := time.Now()
+	blockNumber := big.NewInt(1)
+
+	transactions := []*types.Transaction{
+		types.NewTx(&types.LegacyTx{Nonce: 0, Value: new(big.Int), Gas: 21000, GasPrice: big.NewInt(10)}),
+		types.NewTx(&types.LegacyTx{Nonce: 1, Value: new(big.Int), Gas: 22000, GasPrice: big.NewInt(20)}),
+	}
+
+	receipts := []*types.Receipt{}
+	for i, tx := range transactions {
+		receipts = append(receipts, &types.Receipt{
+			Type:              uint8(tx.Type()),
+			PostState:         state.ZeroHash.Bytes(),
+			CumulativeGasUsed: 0,
+			EffectiveGasPrice: big.NewInt(0),
+			BlockNumber:       blockNumber,
+			GasUsed:           tx.Gas(),
+			TxHash:            tx.Hash(),
+			TransactionIndex:  uint(i),
+			Status:            types.ReceiptStatusSuccessful,
+		})
+	}
+	receipts[1].Logs = []*types.Log{
+		{Address: common.HexToAddress("0x1"), Topics: []common.Hash{common.HexToHash("0x2")}, Data: []byte{}, BlockNumber: blockNumber.Uint64(), TxHash: transactions[1].Hash(), TxIndex: 1, Index: 0},
+		{Address: common.HexToAddress("0x3"), Topics: []common.Hash{}, Data: []byte{0x4}, BlockNumber: blockNumber.Uint64(), TxHash: transactions[1].Hash(), TxIndex: 1, Index: 1},
+	}
+
+	header := state.NewL2Header(&types.Header{
+		Number:     big.NewInt(1),
+		ParentHash: state.ZeroHash,
+		Coinbase:   state.ZeroAddress,
+		Root:       state.ZeroHash,
+		GasUsed:    43000,
+		GasLimit:   100000,
+		Time:       uint64(time.Unix()),
+	})
+
+	l2Block := state.NewL2Block(header, transactions, []*state.L2Header{}, receipts, &trie.StackTrie{})
+	for _, receipt := range receipts {
+		receipt.BlockHash = l2Block.Hash()
+		for _, log := range receipt.Logs {
+			log.BlockHash = l2Block.Hash()
+		}
+	}
+
+	storeTxsEGPData := []state.StoreTxEGPData{}
+	for range transactions {
+		storeTxsEGPData = append(storeTxsEGPData, state.StoreTxEGPData{EGPLog: nil, EffectivePercentage: state.MaxEffectivePercentage})
+	}
+
+	err = pgStateStorage.AddL2Block(ctx, batchNumber, l2Block, receipts, storeTxsEGPData, dbTx)
+	require.NoError(t, err)
+
+	blockReceipts, err := pgStateStorage.GetReceiptsByL2BlockNumber(ctx, l2Block.Number().Uint64(), dbTx)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(blockReceipts))
+	for i, receipt := range blockReceipts {
+		assert.Equal(t, transactions[i].Hash(), receipt.TxHash)
+		assert.Equal(t, uint(i), receipt.TransactionIndex)
+		assert.Equal(t, l2Block.Hash(), receipt.BlockHash)
+		assert.Equal(t, transactions[i].Gas(), receipt.GasUsed)
+		assert.Equal(t, types.CreateBloom(types.Receipts{receipt}), receipt.Bloom)
+
+		singleReceipt, err := pgStateStorage.GetTransactionReceipt(ctx, transactions[i].Hash(), dbTx)
+		require.NoError(t, err)
+		assert.Equal(t, singleReceipt.Bloom, receipt.Bloom)
+		assert.Equal(t, len(singleReceipt.Logs), len(receipt.Logs))
+	}
+	assert.Equal(t, 0, len(blockReceipts[0].Logs))
+	require.Equal(t, 2, len(blockReceipts[1].Logs))
+	assert.Equal(t, uint(1), blockReceipts[1].Logs[0].TxIndex)
+	assert.Equal(t, common.HexToAddress("0x3"), blockReceipts[1].Logs[1].Address)
+
+	blockReceipts, err = pgStateStorage.GetReceiptsByL2BlockNumber(ctx, l2Block.Number().Uint64()+1, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(blockReceipts))
+	require.NoError(t, dbTx.Commit(ctx))
+}
+
 func TestAddAndGetSequences(t *testing.T) {
 	initOrResetDB()
 
@@ -960,6 +1128,70 @@ func TestGetLogs(t *testing.T) {
 			assert.Equal(t, testCase.expectedError, err)
 		})
 	}
+
+	type pageTestCase struct {
+		name              string
+		fromBlock         uint64
+		fromLogIndex      uint64
+		toBlock           uint64
+		limit             uint64
+		expectedPositions [][2]uint64
+		expectedError     error
+	}
+
+	pageTestCases := []pageTestCase{
+		{
+			name:          "invalid block range",
+			fromBlock:     2,
+			toBlock:       1,
+			limit:         10,
+			expectedError: state.ErrInvalidBlockRange,
+		},
+		{
+			name:              "first page",
+			fromBlock:         1,
+			toBlock:           3,
+			limit:             5,
+			expectedPositions: [][2]uint64{{1, 0}, {1, 1}, {1, 2}, {1, 3}, {2, 0}},
+		},
+		{
+			name:              "page starting at a log index",
+			fromBlock:         2,
+			fromLogIndex:      1,
+			toBlock:           3,
+			limit:             5,
+			expectedPositions: [][2]uint64{{2, 1}, {2, 2}, {2, 3}, {3, 0}, {3, 1}},
+		},
+		{
+			name:              "page ending at the to block",
+			fromBlock:         3,
+			fromLogIndex:      2,
+			toBlock:           3,
+			limit:             5,
+			expectedPositions: [][2]uint64{{3, 2}, {3, 3}},
+		},
+	}
+
+	for _, testCase := range pageTestCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			logs, err := testState.GetLogsPage(ctx, testCase.fromBlock, testCase.fromLogIndex, testCase.toBlock, []common.Address{}, [][]common.Hash{}, testCase.limit, dbTx)
+			assert.Equal(t, testCase.expectedError, err)
+
+			positions := [][2]uint64{}
+			for _, l := range logs {
+				positions = append(positions, [2]uint64{l.BlockNumber, uint64(l.Index)})
+			}
+			if testCase.expectedError == nil {
+				assert.Equal(t, testCase.expectedPositions, positions)
+			}
+		})
+	}
+
+	// the pages aren't limited by the max logs count
+	logs, err := testState.GetLogsPage(ctx, 1, 0, 3, []common.Address{}, [][]common.Hash{}, 20, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, 12, len(logs))
+
 	require.NoError(t, dbTx.Commit(ctx))
 }
 
@@ -1086,6 +1318,459 @@ func TestGetNativeBlockHashesInRange(t *testing.T) {
 	require.NoError(t, dbTx.Commit(ctx))
 }
 
+func TestGetL2TxHashesInRange(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+
+	mt, err := l1infotree.NewL1InfoTree(32, [][32]byte{})
+	if err != nil {
+		panic(err)
+	}
+	testState = state.NewState(stateCfg, pgstatestorage.NewPostgresStorage(stateCfg, stateDb), executorClient, stateTree, nil, mt)
+
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Rollback(ctx)) }()
+	err = testState.AddBlock(ctx, block, dbTx)
+	assert.NoError(t, err)
+
+	batchNumber := uint64(1)
+	_, err = testState.Exec(ctx, "INSERT INTO state.batch (batch_num) VALUES ($1)", batchNumber)
+	assert.NoError(t, err)
+
+	privateKey, err := crypto.HexToECDSA("28b2b0318721be8c8339199172cd7cc8f5e273800a35616ec893083a4b32c02e")
+	require.NoError(t, err)
+	signer := types.NewEIP155Signer(big.NewInt(1000))
+	recipient := common.HexToAddress("0x1")
+
+	txHashes := []common.Hash{}
+	for i := 0; i < 3; i++ {
+		var to *common.Address
+		if i != 1 {
+			to = &recipient
+		}
+		tx, err := types.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       to,
+			Value:    new(big.Int),
+			Gas:      0,
+			GasPrice: big.NewInt(0),
+		}), signer, privateKey)
+		require.NoError(t, err)
+		txHashes = append(txHashes, tx.Hash())
+
+		receipt := &types.Receipt{
+			Type:              uint8(tx.Type()),
+			PostState:         state.ZeroHash.Bytes(),
+			CumulativeGasUsed: 0,
+			EffectiveGasPrice: big.NewInt(0),
+			BlockNumber:       big.NewInt(int64(i) + 1),
+			GasUsed:           tx.Gas(),
+			TxHash:            tx.Hash(),
+			TransactionIndex:  0,
+			Status:            types.ReceiptStatusSuccessful,
+		}
+
+		header := state.NewL2Header(&types.Header{
+			Number:     big.NewInt(int64(i) + 1),
+			ParentHash: state.ZeroHash,
+			Coinbase:   state.ZeroAddress,
+			Root:       state.ZeroHash,
+			GasUsed:    1,
+			GasLimit:   10,
+			Time:       uint64(time.Now().Unix()),
+		})
+
+		transactions := []*types.Transaction{tx}
+		receipts := []*types.Receipt{receipt}
+		l2Block := state.NewL2Block(header, transactions, []*state.L2Header{}, receipts, &trie.StackTrie{})
+		for _, receipt := range receipts {
+			receipt.BlockHash = l2Block.Hash()
+		}
+
+		storeTxsEGPData := []state.StoreTxEGPData{{EGPLog: nil, EffectivePercentage: state.MaxEffectivePercentage}}
+		err = testState.AddL2Block(ctx, batchNumber, l2Block, receipts, storeTxsEGPData, dbTx)
+		require.NoError(t, err)
+	}
+
+	type testCase struct {
+		name           string
+		from           uint64
+		to             uint64
+		addresses      []common.Address
+		expectedHashes []common.Hash
+		expectedError  error
+	}
+
+	testCases := []testCase{
+		{
+			name:          "invalid block range",
+			from:          2,
+			to:            1,
+			expectedError: state.ErrInvalidBlockRange,
+		},
+		{
+			name:           "all the txs of the range",
+			from:           2,
+			to:             3,
+			expectedHashes: txHashes[1:3],
+		},
+		{
+			name:           "all the txs",
+			from:           1,
+			to:             3,
+			expectedHashes: txHashes,
+		},
+		{
+			name:           "no txs in the range",
+			from:           4,
+			to:             5,
+			expectedHashes: []common.Hash{},
+		},
+		{
+			name:           "txs to the recipient",
+			from:           1,
+			to:             3,
+			addresses:      []common.Address{recipient},
+			expectedHashes: []common.Hash{txHashes[0], txHashes[2]},
+		},
+		{
+			name:           "txs of the sender in the range",
+			from:           2,
+			to:             3,
+			addresses:      []common.Address{crypto.PubkeyToAddress(privateKey.PublicKey), recipient},
+			expectedHashes: txHashes[1:3],
+		},
+		{
+			name:           "tx creating the contract",
+			from:           1,
+			to:             3,
+			addresses:      []common.Address{crypto.CreateAddress(crypto.PubkeyToAddress(privateKey.PublicKey), 1)},
+			expectedHashes: txHashes[1:2],
+		},
+		{
+			name:           "no txs of the address",
+			from:           1,
+			to:             3,
+			addresses:      []common.Address{common.HexToAddress("0x2")},
+			expectedHashes: []common.Hash{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			hashes, err := testState.GetL2TxHashesInRange(ctx, testCase.from, testCase.to, testCase.addresses, dbTx)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedHashes, hashes)
+		})
+	}
+}
+
+func TestIndexTxAddressesBefore(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+
+	mt, err := l1infotree.NewL1InfoTree(32, [][32]byte{})
+	if err != nil {
+		panic(err)
+	}
+	testState = state.NewState(stateCfg, pgstatestorage.NewPostgresStorage(stateCfg, stateDb), executorClient, stateTree, nil, mt)
+
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Rollback(ctx)) }()
+	err = testState.AddBlock(ctx, block, dbTx)
+	assert.NoError(t, err)
+
+	batchNumber := uint64(1)
+	_, err = testState.Exec(ctx, "INSERT INTO state.batch (batch_num) VALUES ($1)", batchNumber)
+	assert.NoError(t, err)
+
+	privateKey, err := crypto.HexToECDSA("28b2b0318721be8c8339199172cd7cc8f5e273800a35616ec893083a4b32c02e")
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(privateKey.PublicKey)
+	signer := types.NewEIP155Signer(big.NewInt(1000))
+	recipient := common.HexToAddress("0x1")
+
+	// the blocks 1 to 3 have one tx each, the tx of the block 2 deploys a contract
+	txHashes := []common.Hash{}
+	for i := 0; i < 3; i++ {
+		var to *common.Address
+		if i != 1 {
+			to = &recipient
+		}
+		tx, err := types.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       to,
+			Value:    new(big.Int),
+			Gas:      0,
+			GasPrice: big.NewInt(0),
+		}), signer, privateKey)
+		require.NoError(t, err)
+		txHashes = append(txHashes, tx.Hash())
+
+		receipt := &types.Receipt{
+			Type:              uint8(tx.Type()),
+			PostState:         state.ZeroHash.Bytes(),
+			CumulativeGasUsed: 0,
+			EffectiveGasPrice: big.NewInt(0),
+			BlockNumber:       big.NewInt(int64(i) + 1),
+			GasUsed:           tx.Gas(),
+			TxHash:            tx.Hash(),
+			TransactionIndex:  0,
+			Status:            types.ReceiptStatusSuccessful,
+		}
+
+		header := state.NewL2Header(&types.Header{
+			Number:     big.NewInt(int64(i) + 1),
+			ParentHash: state.ZeroHash,
+			Coinbase:   state.ZeroAddress,
+			Root:       state.ZeroHash,
+			GasUsed:    1,
+			GasLimit:   10,
+			Time:       uint64(time.Now().Unix()),
+		})
+
+		transactions := []*types.Transaction{tx}
+		receipts := []*types.Receipt{receipt}
+		l2Block := state.NewL2Block(header, transactions, []*state.L2Header{}, receipts, &trie.StackTrie{})
+		for _, receipt := range receipts {
+			receipt.BlockHash = l2Block.Hash()
+		}
+
+		storeTxsEGPData := []state.StoreTxEGPData{{EGPLog: nil, EffectivePercentage: state.MaxEffectivePercentage}}
+		err = testState.AddL2Block(ctx, batchNumber, l2Block, receipts, storeTxsEGPData, dbTx)
+		require.NoError(t, err)
+	}
+
+	// the blocks were stored before the index was created
+	_, err = dbTx.Exec(ctx, "DELETE FROM state.transaction_address")
+	require.NoError(t, err)
+	_, err = dbTx.Exec(ctx, "UPDATE state.transaction_address_start SET l2_block_num = 4")
+	require.NoError(t, err)
+
+	indexStart, err := testState.IndexTxAddressesBefore(ctx, 2, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), indexStart)
+	hashes, err := testState.GetL2TxHashesInRange(ctx, 1, 3, []common.Address{sender}, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, txHashes[1:3], hashes)
+
+	indexStart, err = testState.IndexTxAddressesBefore(ctx, 2, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), indexStart)
+	hashes, err = testState.GetL2TxHashesInRange(ctx, 1, 3, []common.Address{recipient}, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{txHashes[0], txHashes[2]}, hashes)
+	hashes, err = testState.GetL2TxHashesInRange(ctx, 1, 3, []common.Address{crypto.CreateAddress(sender, 1)}, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, txHashes[1:2], hashes)
+
+	// the whole history is indexed
+	indexStart, err = testState.IndexTxAddressesBefore(ctx, 2, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), indexStart)
+}
+
+func TestGetL2TxHashesByAddress(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+
+	mt, err := l1infotree.NewL1InfoTree(32, [][32]byte{})
+	if err != nil {
+		panic(err)
+	}
+	testState = state.NewState(stateCfg, pgstatestorage.NewPostgresStorage(stateCfg, stateDb), executorClient, stateTree, nil, mt)
+
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Rollback(ctx)) }()
+	err = testState.AddBlock(ctx, block, dbTx)
+	assert.NoError(t, err)
+
+	batchNumber := uint64(1)
+	_, err = testState.Exec(ctx, "INSERT INTO state.batch (batch_num) VALUES ($1)", batchNumber)
+	assert.NoError(t, err)
+
+	privateKey, err := crypto.HexToECDSA("28b2b0318721be8c8339199172cd7cc8f5e273800a35616ec893083a4b32c02e")
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(privateKey.PublicKey)
+	signer := types.NewEIP155Signer(big.NewInt(1000))
+	recipient := common.HexToAddress("0x1")
+	contract := crypto.CreateAddress(sender, 1)
+
+	// block 1 has the tx 0, block 2 has the txs 1 and 2 and block 3 has the tx 3,
+	// the tx 1 deploys a contract and the others are sent to the recipient
+	txsPerBlock := [][]uint64{{0}, {1, 2}, {3}}
+	txHashes := []common.Hash{}
+	for i, nonces := range txsPerBlock {
+		transactions := []*types.Transaction{}
+		receipts := []*types.Receipt{}
+		for index, nonce := range nonces {
+			to := &recipient
+			contractAddress := state.ZeroAddress
+			if nonce == 1 {
+				to = nil
+				contractAddress = contract
+			}
+			tx, err := types.SignTx(types.NewTx(&types.LegacyTx{
+				Nonce:    nonce,
+				To:       to,
+				Value:    new(big.Int),
+				Gas:      0,
+				GasPrice: big.NewInt(0),
+			}), signer, privateKey)
+			require.NoError(t, err)
+			txHashes = append(txHashes, tx.Hash())
+			transactions = append(transactions, tx)
+
+			receipts = append(receipts, &types.Receipt{
+				Type:              uint8(tx.Type()),
+				PostState:         state.ZeroHash.Bytes(),
+				CumulativeGasUsed: 0,
+				EffectiveGasPrice: big.NewInt(0),
+				BlockNumber:       big.NewInt(int64(i) + 1),
+				GasUsed:           tx.Gas(),
+				TxHash:            tx.Hash(),
+				TransactionIndex:  uint(index),
+				ContractAddress:   contractAddress,
+				Status:            types.ReceiptStatusSuccessful,
+			})
+		}
+
+		header := state.NewL2Header(&types.Header{
+			Number:     big.NewInt(int64(i) + 1),
+			ParentHash: state.ZeroHash,
+			Coinbase:   state.ZeroAddress,
+			Root:       state.ZeroHash,
+			GasUsed:    1,
+			GasLimit:   10,
+			Time:       uint64(time.Now().Unix()),
+		})
+
+		l2Block := state.NewL2Block(header, transactions, []*state.L2Header{}, receipts, &trie.StackTrie{})
+		for _, receipt := range receipts {
+			receipt.BlockHash = l2Block.Hash()
+		}
+
+		storeTxsEGPData := make([]state.StoreTxEGPData, len(transactions))
+		for i := range storeTxsEGPData {
+			storeTxsEGPData[i] = state.StoreTxEGPData{EGPLog: nil, EffectivePercentage: state.MaxEffectivePercentage}
+		}
+		err = testState.AddL2Block(ctx, batchNumber, l2Block, receipts, storeTxsEGPData, dbTx)
+		require.NoError(t, err)
+	}
+
+	type testCase struct {
+		name            string
+		before          bool
+		address         common.Address
+		blockNumber     uint64
+		limit           uint64
+		expectedHashes  []common.Hash
+		expectedHasMore bool
+	}
+
+	testCases := []testCase{
+		{
+			name:            "latest tx of the sender",
+			before:          true,
+			address:         sender,
+			blockNumber:     4,
+			limit:           1,
+			expectedHashes:  []common.Hash{txHashes[3]},
+			expectedHasMore: true,
+		},
+		{
+			name:            "whole blocks are returned",
+			before:          true,
+			address:         sender,
+			blockNumber:     4,
+			limit:           2,
+			expectedHashes:  []common.Hash{txHashes[3], txHashes[2], txHashes[1]},
+			expectedHasMore: true,
+		},
+		{
+			name:            "more txs in the last block than the limit",
+			before:          true,
+			address:         sender,
+			blockNumber:     3,
+			limit:           1,
+			expectedHashes:  []common.Hash{txHashes[2], txHashes[1]},
+			expectedHasMore: true,
+		},
+		{
+			name:            "txs of the sender before a block",
+			before:          true,
+			address:         sender,
+			blockNumber:     3,
+			limit:           10,
+			expectedHashes:  []common.Hash{txHashes[2], txHashes[1], txHashes[0]},
+			expectedHasMore: false,
+		},
+		{
+			name:            "first tx of the sender",
+			address:         sender,
+			blockNumber:     0,
+			limit:           1,
+			expectedHashes:  []common.Hash{txHashes[0]},
+			expectedHasMore: true,
+		},
+		{
+			name:            "txs of the recipient after a block",
+			address:         recipient,
+			blockNumber:     1,
+			limit:           10,
+			expectedHashes:  []common.Hash{txHashes[2], txHashes[3]},
+			expectedHasMore: false,
+		},
+		{
+			name:            "txs of the created contract",
+			before:          true,
+			address:         contract,
+			blockNumber:     4,
+			limit:           10,
+			expectedHashes:  []common.Hash{txHashes[1]},
+			expectedHasMore: false,
+		},
+		{
+			name:            "no txs of the address",
+			before:          true,
+			address:         common.HexToAddress("0x2"),
+			blockNumber:     4,
+			limit:           10,
+			expectedHashes:  []common.Hash{},
+			expectedHasMore: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var hashes []common.Hash
+			var hasMore bool
+			var err error
+			if testCase.before {
+				hashes, hasMore, err = testState.GetL2TxHashesByAddressBefore(ctx, testCase.address, testCase.blockNumber, testCase.limit, dbTx)
+			} else {
+				hashes, hasMore, err = testState.GetL2TxHashesByAddressAfter(ctx, testCase.address, testCase.blockNumber, testCase.limit, dbTx)
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedHashes, hashes)
+			assert.Equal(t, testCase.expectedHasMore, hasMore)
+		})
+	}
+
+	txHash, err := testState.GetTxHashByContractAddress(ctx, contract, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, txHashes[1], txHash)
+
+	_, err = testState.GetTxHashByContractAddress(ctx, recipient, dbTx)
+	assert.ErrorIs(t, err, state.ErrNotFound)
+}
+
 func createL1InfoTreeExitRootStorageEntryForTest(blockNumber uint64, index uint32) *state.L1InfoTreeExitRootStorageEntry {
 	exitRoot := state.L1InfoTreeExitRootStorageEntry{
 		L1InfoTreeLeaf: state.L1InfoTreeLeaf{
@@ -1145,6 +1830,45 @@ func TestGetAllL1InfoRootEntries(t *testing.T) {
 	require.NoError(t, dbTx.Commit(ctx))
 }
 
+func TestGetL1InfoRootLeaves(t *testing.T) {
+	setup()
+	initOrResetDB()
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Rollback(ctx)) }()
+
+	block1 := *block
+	block1.BlockNumber = 2002
+	err = testState.AddBlock(ctx, &block1, dbTx)
+	require.NoError(t, err)
+	l1InfoTreeEntry1 := createL1InfoTreeExitRootStorageEntryForTest(block1.BlockNumber, 0)
+	l1InfoTreeEntry2 := createL1InfoTreeExitRootStorageEntryForTest(block1.BlockNumber, 1)
+	l1InfoTreeEntry2.GlobalExitRoot.GlobalExitRoot = common.HexToHash("0x05")
+	err = testState.AddL1InfoRootToExitRoot(ctx, l1InfoTreeEntry1, dbTx)
+	require.NoError(t, err)
+	err = testState.AddL1InfoRootToExitRoot(ctx, l1InfoTreeEntry2, dbTx)
+	require.NoError(t, err)
+
+	entry, err := testState.GetL1InfoRootLeafByIndex(ctx, 1, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, *l1InfoTreeEntry2, entry)
+
+	entry, err = testState.GetL1InfoRootLeafByGlobalExitRoot(ctx, common.HexToHash("0x02"), dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, *l1InfoTreeEntry1, entry)
+
+	entries, err := testState.GetL1InfoRootLeavesUntilIndex(ctx, 0, dbTx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(entries))
+	assert.Equal(t, *l1InfoTreeEntry1, entries[0])
+
+	_, err = testState.GetL1InfoRootLeafByIndex(ctx, 2, dbTx)
+	require.ErrorIs(t, err, state.ErrNotFound)
+	_, err = testState.GetL1InfoRootLeafByGlobalExitRoot(ctx, common.HexToHash("0x06"), dbTx)
+	require.ErrorIs(t, err, state.ErrNotFound)
+}
+
 func TestGetLatestIndex(t *testing.T) {
 	setup()
 	initOrResetDB()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/0xPolygonHermez/zkevm-node/hex"
@@ -137,6 +138,188 @@ func (p *PostgresStorage) GetTxsHashesByBatchNumber(ctx context.Context, batchNu
 	return txs, nil
 }
 
+// GetL2TxHashesInRange returns the hashes of the l2 transactions of the given
+// block range, sorted by block number and transaction index. If addresses are
+// provided, only the transactions whose sender, recipient or created contract
+// is one of them are returned.
+func (p *PostgresStorage) GetL2TxHashesInRange(ctx context.Context, fromBlock, toBlock uint64, addresses []common.Address, dbTx pgx.Tx) ([]common.Hash, error) {
+	const getTxHashesSQL = `
+        SELECT r.tx_hash
+          FROM state.receipt r
+         WHERE r.block_num BETWEEN $1 AND $2
+         ORDER BY r.block_num ASC, r.tx_index ASC`
+	const getTxHashesByAddressesSQL = `
+        SELECT r.tx_hash
+          FROM state.receipt r
+         WHERE r.tx_hash IN (SELECT a.tx_hash
+                               FROM state.transaction_address a
+                              WHERE a.address = ANY($3)
+                                AND a.l2_block_num BETWEEN $1 AND $2)
+         ORDER BY r.block_num ASC, r.tx_index ASC`
+
+	if toBlock < fromBlock {
+		return nil, state.ErrInvalidBlockRange
+	}
+
+	query, args := getTxHashesSQL, []interface{}{fromBlock, toBlock}
+	if len(addresses) > 0 {
+		addressesBytes := make([][]byte, 0, len(addresses))
+		for _, address := range addresses {
+			addressesBytes = append(addressesBytes, address.Bytes())
+		}
+		query, args = getTxHashesByAddressesSQL, append(args, addressesBytes)
+	}
+
+	e := p.getExecQuerier(dbTx)
+	rows, err := e.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hashes := []common.Hash{}
+	for rows.Next() {
+		var hexHash string
+		if err := rows.Scan(&hexHash); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, common.HexToHash(hexHash))
+	}
+	return hashes, rows.Err()
+}
+
+// GetL2TxHashesByAddressBefore returns the hashes of the l2 transactions whose
+// sender, recipient or created contract is the given address in the blocks
+// before the given block, from the most recent one. The transactions of whole
+// blocks are returned until reaching the limit, so the result can exceed it,
+// and it's also returned if there are more transactions beyond them.
+func (p *PostgresStorage) GetL2TxHashesByAddressBefore(ctx context.Context, address common.Address, blockNumber uint64, limit uint64, dbTx pgx.Tx) ([]common.Hash, bool, error) {
+	return p.getL2TxHashesByAddress(ctx, address, blockNumber, limit, "<", "DESC", dbTx)
+}
+
+// GetL2TxHashesByAddressAfter returns the hashes of the l2 transactions whose
+// sender, recipient or created contract is the given address in the blocks
+// after the given block, from the oldest one. The transactions of whole
+// blocks are returned until reaching the limit, so the result can exceed it,
+// and it's also returned if there are more transactions beyond them.
+func (p *PostgresStorage) GetL2TxHashesByAddressAfter(ctx context.Context, address common.Address, blockNumber uint64, limit uint64, dbTx pgx.Tx) ([]common.Hash, bool, error) {
+	return p.getL2TxHashesByAddress(ctx, address, blockNumber, limit, ">", "ASC", dbTx)
+}
+
+func (p *PostgresStorage) getL2TxHashesByAddress(ctx context.Context, address common.Address, blockNumber uint64, limit uint64, comparison, order string, dbTx pgx.Tx) ([]common.Hash, bool, error) {
+	// the block numbers of one more tx than the limit are enough to know the
+	// last block of the page and if there are txs beyond it, without going
+	// through the whole history of the address
+	const getBlockNumbersByAddressSQL = `
+        SELECT l2_block_num
+          FROM state.transaction_address
+         WHERE address = $1
+           AND l2_block_num %[1]s $2
+         ORDER BY l2_block_num %[2]s
+         LIMIT $3`
+	const getTxHashesByAddressSQL = `
+        SELECT a.tx_hash
+          FROM state.transaction_address a
+         INNER JOIN state.receipt r ON r.tx_hash = a.tx_hash
+         WHERE a.address = $1
+           AND a.l2_block_num %[1]s $2
+           AND a.l2_block_num %[3]s= $3
+         ORDER BY a.l2_block_num %[2]s, r.tx_index %[2]s`
+	const existsTxByAddressSQL = `
+        SELECT EXISTS (SELECT 1
+                         FROM state.transaction_address
+                        WHERE address = $1
+                          AND l2_block_num %[1]s $2)`
+
+	if limit == 0 {
+		return []common.Hash{}, false, nil
+	}
+
+	e := p.getExecQuerier(dbTx)
+	blockRows, err := e.Query(ctx, fmt.Sprintf(getBlockNumbersByAddressSQL, comparison, order), address.Bytes(), blockNumber, limit+1)
+	if err != nil {
+		return nil, false, err
+	}
+	defer blockRows.Close()
+
+	blockNumbers := make([]uint64, 0, limit+1)
+	for blockRows.Next() {
+		var number uint64
+		if err := blockRows.Scan(&number); err != nil {
+			return nil, false, err
+		}
+		blockNumbers = append(blockNumbers, number)
+	}
+	if err := blockRows.Err(); err != nil {
+		return nil, false, err
+	}
+	if len(blockNumbers) == 0 {
+		return []common.Hash{}, false, nil
+	}
+
+	// all the txs of the last block of the page are returned, so there are
+	// more txs only if there are txs beyond that block
+	var lastBlockNumber uint64
+	var hasMore bool
+	if uint64(len(blockNumbers)) <= limit {
+		lastBlockNumber = blockNumbers[len(blockNumbers)-1]
+	} else {
+		lastBlockNumber = blockNumbers[limit-1]
+		hasMore = blockNumbers[limit] != lastBlockNumber
+		if !hasMore {
+			err := e.QueryRow(ctx, fmt.Sprintf(existsTxByAddressSQL, comparison), address.Bytes(), lastBlockNumber).Scan(&hasMore)
+			if err != nil {
+				return nil, false, err
+			}
+		}
+	}
+
+	// the txs of the page are bounded by its last block
+	reversed := ">"
+	if comparison == ">" {
+		reversed = "<"
+	}
+	rows, err := e.Query(ctx, fmt.Sprintf(getTxHashesByAddressSQL, comparison, order, reversed), address.Bytes(), blockNumber, lastBlockNumber)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	hashes := []common.Hash{}
+	for rows.Next() {
+		var hexHash string
+		if err := rows.Scan(&hexHash); err != nil {
+			return nil, false, err
+		}
+		hashes = append(hashes, common.HexToHash(hexHash))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+	return hashes, hasMore, nil
+}
+
+// GetTxHashByContractAddress returns the hash of the l2 transaction that
+// deployed the given contract, contracts created by internal calls aren't found
+func (p *PostgresStorage) GetTxHashByContractAddress(ctx context.Context, address common.Address, dbTx pgx.Tx) (common.Hash, error) {
+	const getTxHashByContractAddressSQL = `
+        SELECT tx_hash
+          FROM state.receipt
+         WHERE contract_address = $1
+           AND contract_address <> '0x0000000000000000000000000000000000000000'
+         LIMIT 1`
+
+	var hexHash string
+	e := p.getExecQuerier(dbTx)
+	err := e.QueryRow(ctx, getTxHashByContractAddressSQL, address.String()).Scan(&hexHash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return common.Hash{}, state.ErrNotFound
+	} else if err != nil {
+		return common.Hash{}, err
+	}
+	return common.HexToHash(hexHash), nil
+}
+
 // GetTransactionByHash gets a transaction accordingly to the provided transaction hash
 func (p *PostgresStorage) GetTransactionByHash(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (*types.Transaction, error) {
 	var encoded string
@@ -385,6 +568,155 @@ func (p *PostgresStorage) GetTxsByBlockNumber(ctx context.Context, blockNumber u
 	return txs, nil
 }
 
+// GetEffectiveGasPricesByL2BlockNumber returns the gas used and the effective gas price of all the txs in a given block.
+// For the txs stored without effective gas price, the gas price of the tx is returned
+func (p *PostgresStorage) GetEffectiveGasPricesByL2BlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) ([]state.L2TxEffectiveGasPrice, error) {
+	const getEffectiveGasPricesByBlockNumSQL = `
+        SELECT r.tx_hash, r.gas_used, r.effective_gas_price, t.encoded
+          FROM state.receipt r
+         INNER JOIN state.transaction t
+            ON t.hash = r.tx_hash
+         WHERE t.l2_block_num = $1
+         ORDER BY r.tx_index ASC`
+
+	q := p.getExecQuerier(dbTx)
+	rows, err := q.Query(ctx, getEffectiveGasPricesByBlockNumSQL, blockNumber)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, state.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	gasPrices := make([]state.L2TxEffectiveGasPrice, 0, len(rows.RawValues()))
+	var (
+		txHash, encoded   string
+		gasUsed           uint64
+		effectiveGasPrice *uint64
+	)
+	for rows.Next() {
+		if err = rows.Scan(&txHash, &gasUsed, &effectiveGasPrice, &encoded); err != nil {
+			return nil, err
+		}
+
+		gasPrice := state.L2TxEffectiveGasPrice{
+			TxHash:  common.HexToHash(txHash),
+			GasUsed: gasUsed,
+		}
+		if effectiveGasPrice != nil {
+			gasPrice.EffectiveGasPrice = new(big.Int).SetUint64(*effectiveGasPrice)
+		} else {
+			tx, err := state.DecodeTx(encoded)
+			if err != nil {
+				return nil, err
+			}
+			gasPrice.EffectiveGasPrice = tx.GasPrice()
+		}
+		gasPrices = append(gasPrices, gasPrice)
+	}
+
+	return gasPrices, nil
+}
+
+// GetReceiptsByL2BlockNumber returns the receipts of all the txs in a given block,
+// ordered by tx index and including their logs
+func (p *PostgresStorage) GetReceiptsByL2BlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) ([]*types.Receipt, error) {
+	const getReceiptsByBlockNumSQL = `
+        SELECT r.tx_index, r.tx_hash, r.type, r.post_state, r.status, r.cumulative_gas_used, r.gas_used, r.contract_address, r.effective_gas_price, b.block_hash
+          FROM state.receipt r
+         INNER JOIN state.transaction t
+            ON t.hash = r.tx_hash
+         INNER JOIN state.l2block b
+            ON b.block_num = t.l2_block_num
+         WHERE t.l2_block_num = $1
+         ORDER BY r.tx_index ASC`
+
+	q := p.getExecQuerier(dbTx)
+	rows, err := q.Query(ctx, getReceiptsByBlockNumSQL, blockNumber)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, state.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	receipts := make([]*types.Receipt, 0, len(rows.RawValues()))
+	receiptsByTxHash := make(map[common.Hash]*types.Receipt)
+	for rows.Next() {
+		var txHash, contractAddress, l2BlockHash string
+		var effectiveGasPrice *uint64
+		receipt := &types.Receipt{}
+		err := rows.Scan(&receipt.TransactionIndex,
+			&txHash,
+			&receipt.Type,
+			&receipt.PostState,
+			&receipt.Status,
+			&receipt.CumulativeGasUsed,
+			&receipt.GasUsed,
+			&contractAddress,
+			&effectiveGasPrice,
+			&l2BlockHash,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		receipt.TxHash = common.HexToHash(txHash)
+		receipt.ContractAddress = common.HexToAddress(contractAddress)
+		receipt.BlockNumber = big.NewInt(0).SetUint64(blockNumber)
+		receipt.BlockHash = common.HexToHash(l2BlockHash)
+		if effectiveGasPrice != nil {
+			receipt.EffectiveGasPrice = big.NewInt(0).SetUint64(*effectiveGasPrice)
+		}
+		receipt.Logs = []*types.Log{}
+
+		receipts = append(receipts, receipt)
+		receiptsByTxHash[receipt.TxHash] = receipt
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	logs, err := p.getL2BlockLogs(ctx, blockNumber, dbTx)
+	if !errors.Is(err, pgx.ErrNoRows) && err != nil {
+		return nil, err
+	}
+	for _, log := range logs {
+		receipt, found := receiptsByTxHash[log.TxHash]
+		if !found {
+			continue
+		}
+		log.TxIndex = receipt.TransactionIndex
+		receipt.Logs = append(receipt.Logs, log)
+	}
+
+	for _, receipt := range receipts {
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	}
+
+	return receipts, nil
+}
+
+func (p *PostgresStorage) getL2BlockLogs(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) ([]*types.Log, error) {
+	q := p.getExecQuerier(dbTx)
+
+	const getL2BlockLogsSQL = `
+	SELECT t.l2_block_num, b.block_hash, l.tx_hash, l.log_index, l.address, l.data, l.topic0, l.topic1, l.topic2, l.topic3
+	FROM state.log l
+	INNER JOIN state.transaction t ON t.hash = l.tx_hash
+	INNER JOIN state.l2block b ON b.block_num = t.l2_block_num 
+	WHERE t.l2_block_num = $1
+	ORDER BY l.log_index ASC`
+	rows, err := q.Query(ctx, getL2BlockLogsSQL, blockNumber)
+	if !errors.Is(err, pgx.ErrNoRows) && err != nil {
+		return nil, err
+	}
+	return scanLogs(rows)
+}
+
 // GetTxsByBatchNumber returns all the txs in a given batch
 func (p *PostgresStorage) GetTxsByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]*types.Transaction, error) {
 	q := p.getExecQuerier(dbTx)
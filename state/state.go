@@ -35,6 +35,9 @@ type State struct {
 
 	newL2BlockEvents        chan NewL2BlockEvent
 	newL2BlockEventHandlers []NewL2BlockEventHandler
+
+	newBatchEvents        chan NewBatchEvent
+	newBatchEventHandlers []NewBatchEventHandler
 }
 
 // NewState creates a new State
@@ -52,6 +55,8 @@ func NewState(cfg Config, storage storage, executorClient executor.ExecutorServi
 		eventLog:                eventLog,
 		newL2BlockEvents:        make(chan NewL2BlockEvent, newL2BlockEventBufferSize),
 		newL2BlockEventHandlers: []NewL2BlockEventHandler{},
+		newBatchEvents:          make(chan NewBatchEvent, newBatchEventBufferSize),
+		newBatchEventHandlers:   []NewBatchEventHandler{},
 		l1InfoTree:              mt,
 	}
 
@@ -103,6 +108,53 @@ func (s *State) GetStorageAt(ctx context.Context, address common.Address, positi
 	return s.tree.GetStorageAt(ctx, address, position, root.Bytes())
 }
 
+// AccountProof contains the merkle tree proofs of the leaves of an account
+type AccountProof struct {
+	Balance  *merkletree.Proof
+	Nonce    *merkletree.Proof
+	CodeHash *merkletree.Proof
+	// Storage contains the proofs of the requested storage positions, in the
+	// same order they were requested
+	Storage []*merkletree.Proof
+}
+
+// GetAccountProof returns the merkle tree proofs of the balance, nonce, code
+// hash and the given storage positions of an account at the given state root
+func (s *State) GetAccountProof(ctx context.Context, address common.Address, positions []*big.Int, root common.Hash) (*AccountProof, error) {
+	if s.tree == nil {
+		return nil, ErrStateTreeNil
+	}
+
+	balance, err := s.tree.GetBalanceProof(ctx, address, root.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := s.tree.GetNonceProof(ctx, address, root.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	codeHash, err := s.tree.GetCodeHashProof(ctx, address, root.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	storage := make([]*merkletree.Proof, 0, len(positions))
+	for _, position := range positions {
+		proof, err := s.tree.GetStorageProof(ctx, address, position, root.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		storage = append(storage, proof)
+	}
+
+	return &AccountProof{
+		Balance:  balance,
+		Nonce:    nonce,
+		CodeHash: codeHash,
+		Storage:  storage,
+	}, nil
+}
+
 // GetLastStateRoot returns the latest state root
 func (s *State) GetLastStateRoot(ctx context.Context, dbTx pgx.Tx) (common.Hash, error) {
 	lastBlockHeader, err := s.GetLastL2BlockHeader(ctx, dbTx)
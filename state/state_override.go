@@ -0,0 +1,132 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// StateOverride is the set of accounts overridden before executing a message
+// call, keyed by the account address
+type StateOverride map[common.Address]OverrideAccount
+
+// OverrideAccount indicates the overriding fields of an account during the
+// execution of a message call. State and StateDiff can't be set at the same
+// time: State replaces the whole account storage, while StateDiff only
+// replaces the given slots.
+type OverrideAccount struct {
+	Nonce     *uint64
+	Code      *[]byte
+	Balance   *big.Int
+	State     *map[common.Hash]common.Hash
+	StateDiff *map[common.Hash]common.Hash
+}
+
+// Validate checks the state override can be applied
+func (so StateOverride) Validate() error {
+	for address, account := range so {
+		if account.State != nil && account.StateDiff != nil {
+			return fmt.Errorf("%w: %v", ErrStateOverrideStateAndStateDiff, address.String())
+		}
+	}
+	return nil
+}
+
+// nonce returns the overridden nonce of the account, or the given loaded
+// nonce if it is not overridden
+func (so StateOverride) nonce(address common.Address, loaded uint64) uint64 {
+	if account, found := so[address]; found && account.Nonce != nil {
+		return *account.Nonce
+	}
+	return loaded
+}
+
+// balance returns the overridden balance of the account, or the given loaded
+// balance if it is not overridden
+func (so StateOverride) balance(address common.Address, loaded *big.Int) *big.Int {
+	if account, found := so[address]; found && account.Balance != nil {
+		return new(big.Int).Set(account.Balance)
+	}
+	return loaded
+}
+
+// code returns the overridden code of the account, or the given loaded code if
+// it is not overridden
+func (so StateOverride) code(address common.Address, loaded []byte) []byte {
+	if account, found := so[address]; found && account.Code != nil {
+		return *account.Code
+	}
+	return loaded
+}
+
+// toExecutorStateOverride converts the state override into the executor
+// request format. The executor can't tell a zero value from a missing one, so
+// the nonce, the balance and the code not overridden are loaded from the tree
+// at the given state root. The storage is kept unless State or StateDiff is
+// given.
+func (s *State) toExecutorStateOverride(ctx context.Context, so StateOverride, stateRoot common.Hash) (map[string]*executor.OverrideAccount, error) {
+	if len(so) == 0 {
+		return nil, nil
+	}
+	if err := so.Validate(); err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]*executor.OverrideAccount, len(so))
+	for address, account := range so {
+		overrideAccount := &executor.OverrideAccount{}
+
+		if account.Nonce != nil {
+			overrideAccount.Nonce = *account.Nonce
+		} else {
+			nonce, err := s.tree.GetNonce(ctx, address, stateRoot.Bytes())
+			if err != nil {
+				return nil, err
+			}
+			overrideAccount.Nonce = nonce.Uint64()
+		}
+
+		balance := account.Balance
+		if balance == nil {
+			var err error
+			balance, err = s.tree.GetBalance(ctx, address, stateRoot.Bytes())
+			if err != nil {
+				return nil, err
+			}
+		}
+		overrideAccount.Balance = balance.Bytes()
+		if len(overrideAccount.Balance) == 0 {
+			overrideAccount.Balance = []byte{0}
+		}
+
+		if account.Code != nil {
+			overrideAccount.Code = *account.Code
+		} else {
+			code, err := s.tree.GetCode(ctx, address, stateRoot.Bytes())
+			if err != nil {
+				return nil, err
+			}
+			overrideAccount.Code = code
+		}
+		if account.State != nil {
+			overrideAccount.State = toExecutorStorage(*account.State)
+		}
+		if account.StateDiff != nil {
+			overrideAccount.StateDiff = toExecutorStorage(*account.StateDiff)
+		}
+
+		res[address.String()] = overrideAccount
+	}
+	return res, nil
+}
+
+func toExecutorStorage(storage map[common.Hash]common.Hash) map[string]string {
+	res := make(map[string]string, len(storage))
+	for key, value := range storage {
+		res[key.String()] = value.String()
+	}
+	return res
+}
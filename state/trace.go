@@ -79,32 +79,8 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 		return nil, err
 	}
 
-	traceConfigRequest := &executor.TraceConfig{
-		TxHashToGenerateFullTrace: transactionHash.Bytes(),
-		// set the defaults to the maximum information we can have.
-		// this is needed to process custom tracers later
-		DisableStorage:   cFalse,
-		DisableStack:     cFalse,
-		EnableMemory:     cTrue,
-		EnableReturnData: cTrue,
-	}
-
-	// if the default tracer is used, then we review the information
-	// we want to have in the trace related to the parameters we received.
-	if traceConfig.IsDefaultTracer() {
-		if traceConfig.DisableStorage {
-			traceConfigRequest.DisableStorage = cTrue
-		}
-		if traceConfig.DisableStack {
-			traceConfigRequest.DisableStack = cTrue
-		}
-		if !traceConfig.EnableMemory {
-			traceConfigRequest.EnableMemory = cFalse
-		}
-		if !traceConfig.EnableReturnData {
-			traceConfigRequest.EnableReturnData = cFalse
-		}
-	}
+	traceConfigRequest := newExecutorTraceConfig(traceConfig)
+	traceConfigRequest.TxHashToGenerateFullTrace = transactionHash.Bytes()
 
 	// prepare process batch request
 	oldStateRoot := previousBlock.Root()
@@ -197,14 +173,6 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 
 	result.FullTrace.Context = context
 
-	gasPrice, ok := new(big.Int).SetString(context.GasPrice, encoding.Base10)
-	if !ok {
-		log.Errorf("debug transaction: failed to parse gasPrice")
-		return nil, fmt.Errorf("failed to parse gasPrice")
-	}
-
-	// select and prepare tracer
-	var tracer tracers.Tracer
 	tracerContext := &tracers.Context{
 		BlockHash:   receipt.BlockHash,
 		BlockNumber: receipt.BlockNumber,
@@ -212,6 +180,142 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 		TxHash:      transactionHash,
 	}
 
+	traceResult, err := s.traceExecutionResult(result, *receipt, traceConfig, tracerContext, batch.StateRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	result.TraceResult = traceResult
+
+	return result, nil
+}
+
+// DebugCall executes the given unsigned transaction on top of the state of the
+// provided l2 block, without storing it, to generate its trace
+func (s *State) DebugCall(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, traceConfig TraceConfig, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
+	var l2Block *L2Block
+	var err error
+	if l2BlockNumber != nil {
+		l2Block, err = s.GetL2BlockByNumber(ctx, *l2BlockNumber, dbTx)
+	} else {
+		l2Block, err = s.GetLastL2Block(ctx, dbTx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	stateRoot := l2Block.Root()
+	blockNumber := l2Block.Number()
+
+	traceConfigRequest := newExecutorTraceConfig(traceConfig)
+
+	startTime := time.Now()
+	response, err := s.internalProcessUnsignedTransaction(ctx, tx, senderAddress, l2BlockNumber, true, nil, traceConfigRequest, dbTx)
+	endTime := time.Now()
+	if err != nil && response == nil {
+		return nil, err
+	}
+
+	r := response.BlockResponses[0].TransactionResponses[0]
+	if executor.IsIntrinsicError(executor.RomErrorCode(r.RomError)) {
+		return nil, r.RomError
+	}
+
+	result := &runtime.ExecutionResult{
+		CreateAddress: r.CreateAddress,
+		GasLeft:       r.GasLeft,
+		GasUsed:       r.GasUsed,
+		ReturnValue:   r.ReturnValue,
+		StateRoot:     r.StateRoot.Bytes(),
+		FullTrace:     r.FullTrace,
+		Err:           r.RomError,
+	}
+
+	context := instrumentation.Context{
+		From:         senderAddress.String(),
+		Input:        tx.Data(),
+		Gas:          tx.Gas(),
+		Value:        tx.Value(),
+		Output:       result.ReturnValue,
+		GasPrice:     tx.GasPrice().String(),
+		OldStateRoot: stateRoot,
+		Time:         uint64(endTime.Sub(startTime)),
+		GasUsed:      result.GasUsed,
+	}
+
+	// Fill trace context
+	if tx.To() == nil {
+		context.Type = "CREATE"
+		context.To = result.CreateAddress.Hex()
+	} else {
+		context.Type = "CALL"
+		context.To = tx.To().Hex()
+	}
+
+	result.FullTrace.Context = context
+
+	// the call is not part of any block, so a receipt is built
+	// with the information required by the tracers
+	receipt := types.Receipt{
+		Type:        tx.Type(),
+		Status:      types.ReceiptStatusSuccessful,
+		TxHash:      r.TxHash,
+		GasUsed:     result.GasUsed,
+		BlockNumber: blockNumber,
+	}
+	if result.Failed() {
+		receipt.Status = types.ReceiptStatusFailed
+	}
+
+	tracerContext := &tracers.Context{
+		BlockNumber: blockNumber,
+		TxHash:      r.TxHash,
+	}
+
+	traceResult, err := s.traceExecutionResult(result, receipt, traceConfig, tracerContext, stateRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	result.TraceResult = traceResult
+
+	return result, nil
+}
+
+// newExecutorTraceConfig creates the trace config to be sent to the executor
+// based on the trace config requested by the user
+func newExecutorTraceConfig(traceConfig TraceConfig) *executor.TraceConfig {
+	traceConfigRequest := &executor.TraceConfig{
+		// set the defaults to the maximum information we can have.
+		// this is needed to process custom tracers later
+		DisableStorage:   cFalse,
+		DisableStack:     cFalse,
+		EnableMemory:     cTrue,
+		EnableReturnData: cTrue,
+	}
+
+	// if the default tracer is used, then we review the information
+	// we want to have in the trace related to the parameters we received.
+	if traceConfig.IsDefaultTracer() {
+		if traceConfig.DisableStorage {
+			traceConfigRequest.DisableStorage = cTrue
+		}
+		if traceConfig.DisableStack {
+			traceConfigRequest.DisableStack = cTrue
+		}
+		if !traceConfig.EnableMemory {
+			traceConfigRequest.EnableMemory = cFalse
+		}
+		if !traceConfig.EnableReturnData {
+			traceConfigRequest.EnableReturnData = cFalse
+		}
+	}
+
+	return traceConfigRequest
+}
+
+// traceExecutionResult parses the full trace of the execution result
+// using the tracer selected in the trace config
+func (s *State) traceExecutionResult(result *runtime.ExecutionResult, receipt types.Receipt, traceConfig TraceConfig, tracerContext *tracers.Context, stateRoot common.Hash) (json.RawMessage, error) {
 	if traceConfig.IsDefaultTracer() {
 		structLoggerCfg := structlogger.Config{
 			EnableMemory:     traceConfig.EnableMemory,
@@ -220,13 +324,19 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 			EnableReturnData: traceConfig.EnableReturnData,
 		}
 		tracer := structlogger.NewStructLogger(structLoggerCfg)
-		traceResult, err := tracer.ParseTrace(result, *receipt)
-		if err != nil {
-			return nil, err
-		}
-		result.TraceResult = traceResult
-		return result, nil
-	} else if traceConfig.Is4ByteTracer() {
+		return tracer.ParseTrace(result, receipt)
+	}
+
+	gasPrice, ok := new(big.Int).SetString(result.FullTrace.Context.GasPrice, encoding.Base10)
+	if !ok {
+		log.Errorf("debug transaction: failed to parse gasPrice")
+		return nil, fmt.Errorf("failed to parse gasPrice")
+	}
+
+	// select and prepare tracer
+	var tracer tracers.Tracer
+	var err error
+	if traceConfig.Is4ByteTracer() {
 		tracer, err = native.NewFourByteTracer(tracerContext, traceConfig.TracerConfig)
 		if err != nil {
 			log.Errorf("debug transaction: failed to create 4byteTracer, err: %v", err)
@@ -238,6 +348,12 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 			log.Errorf("debug transaction: failed to create callTracer, err: %v", err)
 			return nil, fmt.Errorf("failed to create callTracer, err: %v", err)
 		}
+	} else if traceConfig.IsFlatCallTracer() {
+		tracer, err = native.NewFlatCallTracer(tracerContext, traceConfig.TracerConfig)
+		if err != nil {
+			log.Errorf("debug transaction: failed to create flatCallTracer, err: %v", err)
+			return nil, fmt.Errorf("failed to create flatCallTracer, err: %v", err)
+		}
 	} else if traceConfig.IsNoopTracer() {
 		tracer, err = native.NewNoopTracer(tracerContext, traceConfig.TracerConfig)
 		if err != nil {
@@ -250,6 +366,12 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 			log.Errorf("debug transaction: failed to create prestateTracer, err: %v", err)
 			return nil, fmt.Errorf("failed to create prestateTracer, err: %v", err)
 		}
+	} else if traceConfig.IsMuxTracer() {
+		tracer, err = native.NewMuxTracer(tracerContext, traceConfig.TracerConfig)
+		if err != nil {
+			log.Errorf("debug transaction: failed to create muxTracer, err: %v", err)
+			return nil, fmt.Errorf("failed to create muxTracer, err: %v", err)
+		}
 	} else if traceConfig.IsJSCustomTracer() {
 		tracer, err = js.NewJsTracer(*traceConfig.Tracer, tracerContext, traceConfig.TracerConfig)
 		if err != nil {
@@ -260,7 +382,7 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 		return nil, fmt.Errorf("invalid tracer: %v, err: %v", traceConfig.Tracer, err)
 	}
 
-	fakeDB := &FakeDB{State: s, stateRoot: batch.StateRoot.Bytes()}
+	fakeDB := &FakeDB{State: s, stateRoot: stateRoot.Bytes()}
 	evm := fakevm.NewFakeEVM(fakevm.BlockContext{BlockNumber: big.NewInt(1)}, fakevm.TxContext{GasPrice: gasPrice}, fakeDB, params.TestChainConfig, fakevm.Config{Debug: true, Tracer: tracer})
 
 	traceResult, err := s.buildTrace(evm, result, tracer)
@@ -269,9 +391,7 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 		return nil, fmt.Errorf("failed parse the trace using the tracer: %v", err)
 	}
 
-	result.TraceResult = traceResult
-
-	return result, nil
+	return traceResult, nil
 }
 
 // ParseTheTraceUsingTheTracer parses the given trace with the given tracer.
@@ -24,6 +24,10 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// txAddressIndexBackfillBlocks is the number of l2 blocks whose txs are
+// indexed by address in each db transaction of the backfill
+const txAddressIndexBackfillBlocks = 1000
+
 // TestGetL2Hash computes the l2 hash of a transaction for testing purposes
 func TestGetL2Hash(tx types.Transaction, sender common.Address) (common.Hash, error) {
 	return getL2Hash(tx, sender)
@@ -306,7 +310,7 @@ func (s *State) StoreL2Block(ctx context.Context, batchNumber uint64, l2Block *P
 			storeTxsEGPData[i].EGPLog = txsEGPLog[i]
 		}
 
-		receipt := generateReceipt(header.Number, txResponse)
+		receipt := GenerateReceipt(header.Number, txResponse)
 		receipts = append(receipts, receipt)
 	}
 
@@ -335,7 +339,7 @@ func (s *State) PreProcessTransaction(ctx context.Context, tx *types.Transaction
 		return nil, err
 	}
 
-	response, err := s.internalProcessUnsignedTransaction(ctx, tx, sender, nil, false, dbTx)
+	response, err := s.internalProcessUnsignedTransaction(ctx, tx, sender, nil, false, nil, nil, dbTx)
 	if err != nil {
 		return response, err
 	}
@@ -344,9 +348,10 @@ func (s *State) PreProcessTransaction(ctx context.Context, tx *types.Transaction
 }
 
 // ProcessUnsignedTransaction processes the given unsigned transaction.
-func (s *State) ProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
+// The accounts of the state override, if any, are overridden before the execution.
+func (s *State) ProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, stateOverride StateOverride, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
 	result := new(runtime.ExecutionResult)
-	response, err := s.internalProcessUnsignedTransaction(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, dbTx)
+	response, err := s.internalProcessUnsignedTransaction(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, stateOverride, nil, dbTx)
 	if err != nil {
 		return nil, err
 	}
@@ -367,8 +372,37 @@ func (s *State) ProcessUnsignedTransaction(ctx context.Context, tx *types.Transa
 	return result, nil
 }
 
-// ProcessUnsignedTransaction processes the given unsigned transaction.
-func (s *State) internalProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, dbTx pgx.Tx) (*ProcessBatchResponse, error) {
+// EstimateZKCounters processes the given unsigned transaction with the zk
+// counters enabled and returns the counters it uses along with its execution
+// result. When the execution fails in the ROM, like when it's reverted or runs
+// out of counters, the counters used until then are returned and the error is
+// set in the execution result.
+func (s *State) EstimateZKCounters(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, dbTx pgx.Tx) (ZKCounters, *runtime.ExecutionResult, error) {
+	response, err := s.internalProcessUnsignedTransaction(ctx, tx, senderAddress, l2BlockNumber, false, nil, nil, dbTx)
+	if err != nil && response == nil {
+		return ZKCounters{}, nil, err
+	}
+
+	r := response.BlockResponses[0].TransactionResponses[0]
+	result := &runtime.ExecutionResult{
+		ReturnValue:   r.ReturnValue,
+		GasLeft:       r.GasLeft,
+		GasUsed:       r.GasUsed,
+		CreateAddress: r.CreateAddress,
+		StateRoot:     r.StateRoot.Bytes(),
+		Err:           r.RomError,
+	}
+	if errors.Is(r.RomError, runtime.ErrExecutionReverted) {
+		result.Err = constructErrorFromRevert(r.RomError, r.ReturnValue)
+	}
+
+	return response.UsedZkCounters, result, nil
+}
+
+// internalProcessUnsignedTransaction processes the given unsigned transaction.
+// When stateOverride is provided, the executor overrides the accounts before the execution.
+// When traceConfig is provided, the executor is requested to generate the full trace of the transaction.
+func (s *State) internalProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, stateOverride StateOverride, traceConfig *executor.TraceConfig, dbTx pgx.Tx) (*ProcessBatchResponse, error) {
 	var attempts = 1
 
 	if s.executorClient == nil {
@@ -415,7 +449,12 @@ func (s *State) internalProcessUnsignedTransaction(ctx context.Context, tx *type
 	if err != nil {
 		return nil, err
 	}
-	nonce := loadedNonce.Uint64()
+	nonce := stateOverride.nonce(senderAddress, loadedNonce.Uint64())
+
+	executorStateOverride, err := s.toExecutorStateOverride(ctx, stateOverride, stateRoot)
+	if err != nil {
+		return nil, err
+	}
 
 	batchL2Data, err := EncodeUnsignedTransaction(*tx, s.cfg.ChainID, &nonce, forkID)
 	if err != nil {
@@ -437,12 +476,27 @@ func (s *State) internalProcessUnsignedTransaction(ctx context.Context, tx *type
 		ChainId:          s.cfg.ChainID,
 		ForkId:           forkID,
 		ContextId:        uuid.NewString(),
+		StateOverride:    executorStateOverride,
 	}
 
 	if noZKEVMCounters {
 		processBatchRequest.NoCounters = cTrue
 	}
 
+	if traceConfig != nil {
+		// the executor identifies the tx to be traced by its hash, so we decode
+		// the encoded unsigned tx to get the hash the executor will compute for it
+		txs, _, _, err := DecodeTxs(batchL2Data, forkID)
+		if err != nil {
+			return nil, err
+		}
+		if len(txs) == 0 {
+			return nil, ErrInvalidData
+		}
+		traceConfig.TxHashToGenerateFullTrace = txs[0].Hash().Bytes()
+		processBatchRequest.TraceConfig = traceConfig
+	}
+
 	log.Debugf("internalProcessUnsignedTransaction[processBatchRequest.OldBatchNum]: %v", processBatchRequest.OldBatchNum)
 	log.Debugf("internalProcessUnsignedTransaction[processBatchRequest.From]: %v", processBatchRequest.From)
 	log.Debugf("internalProcessUnsignedTransaction[processBatchRequest.OldStateRoot]: %v", hex.EncodeToHex(processBatchRequest.OldStateRoot))
@@ -584,8 +638,9 @@ func CheckSupersetBatchTransactions(existingTxHashes []common.Hash, processedTxs
 	return nil
 }
 
-// EstimateGas for a transaction
-func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, dbTx pgx.Tx) (uint64, []byte, error) {
+// EstimateGas for a transaction, the accounts of the state override, if any,
+// are overridden before each execution
+func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, stateOverride StateOverride, dbTx pgx.Tx) (uint64, []byte, error) {
 	const ethTransferGas = 21000
 
 	var lowEnd uint64
@@ -611,7 +666,12 @@ func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common
 	if err != nil {
 		return 0, nil, err
 	}
-	nonce := loadedNonce.Uint64()
+	nonce := stateOverride.nonce(senderAddress, loadedNonce.Uint64())
+
+	executorStateOverride, err := s.toExecutorStateOverride(ctx, stateOverride, stateRoot)
+	if err != nil {
+		return 0, nil, err
+	}
 
 	// Get latest batch from the database to get globalExitRoot and Timestamp
 	lastBatch := lastBatches[0]
@@ -631,7 +691,7 @@ func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common
 		code, err := s.tree.GetCode(ctx, *transaction.To(), stateRoot.Bytes())
 		if err != nil {
 			log.Warnf("error while getting transaction.to() code %v", err)
-		} else if len(code) == 0 {
+		} else if len(stateOverride.code(*transaction.To(), code)) == 0 {
 			return lowEnd, nil, nil
 		}
 	}
@@ -654,7 +714,7 @@ func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common
 			}
 		}
 
-		availableBalance = new(big.Int).Set(senderBalance)
+		availableBalance = new(big.Int).Set(stateOverride.balance(senderAddress, senderBalance))
 
 		if transaction.Value() != nil {
 			if transaction.Value().Cmp(availableBalance) > 0 {
@@ -711,6 +771,7 @@ func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common
 			ChainId:          s.cfg.ChainID,
 			ForkId:           forkID,
 			ContextId:        uuid.NewString(),
+			StateOverride:    executorStateOverride,
 		}
 
 		log.Debugf("EstimateGas[processBatchRequest.OldBatchNum]: %v", processBatchRequest.OldBatchNum)
@@ -836,3 +897,30 @@ func isGasEVMError(err error) bool {
 func isEVMRevertError(err error) bool {
 	return errors.Is(err, runtime.ErrExecutionReverted)
 }
+
+// BackfillTxAddressIndex indexes by address the txs stored before the address
+// index was created, from the most recent block to the genesis, a chunk of
+// blocks per db transaction. Several nodes sharing the db can run it at the
+// same time, each chunk is indexed by only one of them.
+func (s *State) BackfillTxAddressIndex(ctx context.Context) error {
+	for {
+		dbTx, err := s.BeginStateTransaction(ctx)
+		if err != nil {
+			return err
+		}
+		indexStart, err := s.IndexTxAddressesBefore(ctx, txAddressIndexBackfillBlocks, dbTx)
+		if err != nil {
+			if rollbackErr := dbTx.Rollback(ctx); rollbackErr != nil {
+				log.Errorf("error rolling back the tx address index backfill: %v", rollbackErr)
+			}
+			return err
+		}
+		if err := dbTx.Commit(ctx); err != nil {
+			return err
+		}
+		if indexStart == 0 {
+			return nil
+		}
+		log.Infof("txs indexed by address from the l2 block %d", indexStart)
+	}
+}
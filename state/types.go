@@ -131,6 +131,13 @@ type EffectiveGasPriceLog struct {
 	Error          string
 }
 
+// L2TxEffectiveGasPrice contains the gas used and the effective gas price paid by a tx included in a l2 block
+type L2TxEffectiveGasPrice struct {
+	TxHash            common.Hash
+	GasUsed           uint64
+	EffectiveGasPrice *big.Int
+}
+
 // StoreTxEGPData contains the data related to the effective gas price that needs to be stored when storing a tx
 type StoreTxEGPData struct {
 	EGPLog              *EffectiveGasPriceLog
@@ -261,6 +268,11 @@ func (t *TraceConfig) IsCallTracer() bool {
 	return t.Tracer != nil && *t.Tracer == "callTracer"
 }
 
+// IsFlatCallTracer returns true when should use flatCallTracer
+func (t *TraceConfig) IsFlatCallTracer() bool {
+	return t.Tracer != nil && *t.Tracer == "flatCallTracer"
+}
+
 // IsNoopTracer returns true when should use noopTracer
 func (t *TraceConfig) IsNoopTracer() bool {
 	return t.Tracer != nil && *t.Tracer == "noopTracer"
@@ -271,6 +283,11 @@ func (t *TraceConfig) IsPrestateTracer() bool {
 	return t.Tracer != nil && *t.Tracer == "prestateTracer"
 }
 
+// IsMuxTracer returns true when should use muxTracer
+func (t *TraceConfig) IsMuxTracer() bool {
+	return t.Tracer != nil && *t.Tracer == "muxTracer"
+}
+
 // IsJSCustomTracer returns true when should use js custom tracer
 func (t *TraceConfig) IsJSCustomTracer() bool {
 	return t.Tracer != nil && strings.Contains(*t.Tracer, "result") && strings.Contains(*t.Tracer, "fault")